@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 // Application constants
@@ -10,6 +11,9 @@ const (
 	DefaultRateLimit    = 100
 	DefaultRateWindow   = 60
 	DefaultHistoryLimit = 10
+	DefaultBatchWorkers = 4
+	DefaultJobWorkers   = 2
+	DefaultGRPCPort     = 9090
 )
 
 // AppConfig stores application configurations
@@ -17,6 +21,18 @@ type AppConfig struct {
 	RateLimit    int
 	RateWindow   int
 	HistoryLimit int
+	BatchWorkers int
+	JobWorkers   int
+	GRPCPort     int
+	// StrictPersistence makes generation fail when the generated resource cannot be stored,
+	// instead of returning it unsaved and without an ID
+	StrictPersistence bool
+	// ExportTemplateDir holds templates replacing the built-in ones of the world dossiers
+	ExportTemplateDir string
+	// AdminToken is the bearer token of privileged operations such as deleting worlds or listing webhooks, disabled when empty
+	AdminToken string
+	// WebhookAllowPrivate lets webhooks target loopback and private addresses, for local development only
+	WebhookAllowPrivate bool
 }
 
 // NewAppConfig creates a new instance of the application configuration
@@ -25,6 +41,15 @@ func NewAppConfig() *AppConfig {
 		RateLimit:    getEnvAsInt("RATE_LIMIT", DefaultRateLimit),
 		RateWindow:   getEnvAsInt("RATE_WINDOW", DefaultRateWindow),
 		HistoryLimit: getEnvAsInt("HISTORY_LIMIT", DefaultHistoryLimit),
+		BatchWorkers: getEnvAsInt("BATCH_WORKERS", DefaultBatchWorkers),
+		JobWorkers:   getEnvAsInt("JOB_WORKERS", DefaultJobWorkers),
+		GRPCPort:     getEnvAsInt("GRPC_PORT", DefaultGRPCPort),
+
+		StrictPersistence: getEnvAsBool("STRICT_PERSISTENCE", false),
+		ExportTemplateDir: os.Getenv("EXPORT_TEMPLATE_DIR"),
+
+		AdminToken:          os.Getenv("ADMIN_TOKEN"),
+		WebhookAllowPrivate: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE", false),
 	}
 }
 
@@ -38,6 +63,16 @@ func getEnvAsInt(key string, defaultVal int) int {
 	return defaultVal
 }
 
+// getEnvAsBool gets an environment variable as boolean
+func getEnvAsBool(key string, defaultVal bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultVal
+}
+
 // parseInt converts string to int
 func parseInt(value string) (int, error) {
 	var result int
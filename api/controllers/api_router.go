@@ -1,25 +1,83 @@
 package controllers
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/gql"
 	v1 "github.com/medinapdr/world-gen/controllers/v1"
+	v2 "github.com/medinapdr/world-gen/controllers/v2"
+	"github.com/medinapdr/world-gen/middlewares"
 	"github.com/medinapdr/world-gen/services"
 )
 
+// Dates of the deprecation of API v1 and of its removal, sent in the headers of its responses
+var (
+	V1DeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	V1Sunset       = time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)
+)
+
 // APIRouter handles routing requests to the appropriate API version controllers
 type APIRouter struct {
-	v1WorldController *v1.WorldController
+	v1WorldController    *v1.WorldController
+	v1SystemController   *v1.SystemController
+	v1LocationController *v1.LocationController
+	v1JobController      *v1.JobController
+	v1SessionController  *v1.SessionController
+	v1WebhookController  *v1.WebhookController
+	v1ExportController   *v1.ExportController
+	v1ImportController   *v1.ImportController
+	v2WorldController    *v2.WorldController
+	v2SystemController   *v2.SystemController
+	v2LocationController *v2.LocationController
+	v2JobController      *v2.JobController
+	v2SessionController  *v2.SessionController
+	v2WebhookController  *v2.WebhookController
+	graphQLController    *gql.GraphQLController
 }
 
 // NewAPIRouter creates a new API router
-func NewAPIRouter(worldService *services.WorldService) *APIRouter {
+func NewAPIRouter(worldService *services.WorldService, systemService *services.SystemService,
+	locationService *services.LocationService, jobService *services.JobService, eventBus *services.EventBus,
+	sessionService *services.SessionService, webhookService *services.WebhookService, exportService *services.ExportService) *APIRouter {
 	return &APIRouter{
-		v1WorldController: v1.NewWorldController(worldService),
+		v1WorldController:    v1.NewWorldController(worldService, eventBus),
+		v1SystemController:   v1.NewSystemController(systemService),
+		v1LocationController: v1.NewLocationController(worldService, locationService),
+		v1JobController:      v1.NewJobController(jobService),
+		v1SessionController:  v1.NewSessionController(worldService, sessionService),
+		v1WebhookController:  v1.NewWebhookController(webhookService),
+		v1ExportController:   v1.NewExportController(worldService, exportService),
+		v1ImportController:   v1.NewImportController(worldService),
+		v2WorldController:    v2.NewWorldController(worldService, eventBus),
+		v2SystemController:   v2.NewSystemController(systemService),
+		v2LocationController: v2.NewLocationController(worldService, locationService),
+		v2JobController:      v2.NewJobController(jobService),
+		v2SessionController:  v2.NewSessionController(worldService, sessionService),
+		v2WebhookController:  v2.NewWebhookController(webhookService),
+		graphQLController:    gql.NewGraphQLController(worldService, locationService),
 	}
 }
 
 // RegisterRoutes registers all API version routes in Echo
 func (r *APIRouter) RegisterRoutes(e *echo.Echo) {
-	v1Group := e.Group("/v1")
+	v1Group := e.Group("/v1", middlewares.Deprecation(V1DeprecatedAt, V1Sunset, "/v2"))
 	r.v1WorldController.RegisterRoutes(v1Group)
+	r.v1SystemController.RegisterRoutes(v1Group)
+	r.v1LocationController.RegisterRoutes(v1Group)
+	r.v1JobController.RegisterRoutes(v1Group)
+	r.v1SessionController.RegisterRoutes(v1Group)
+	r.v1WebhookController.RegisterRoutes(v1Group)
+	r.v1ExportController.RegisterRoutes(v1Group)
+	r.v1ImportController.RegisterRoutes(v1Group)
+
+	v2Group := e.Group("/v2")
+	r.v2WorldController.RegisterRoutes(v2Group)
+	r.v2SystemController.RegisterRoutes(v2Group)
+	r.v2LocationController.RegisterRoutes(v2Group)
+	r.v2JobController.RegisterRoutes(v2Group)
+	r.v2SessionController.RegisterRoutes(v2Group)
+	r.v2WebhookController.RegisterRoutes(v2Group)
+
+	r.graphQLController.RegisterRoutes(e)
 }
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	v2 "github.com/medinapdr/world-gen/controllers/v2"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// Statuses of the kinds of errors returned by the services
+var kindStatuses = []struct {
+	kind   error
+	status int
+}{
+	{services.ErrNotFound, http.StatusNotFound},
+	{services.ErrValidation, http.StatusBadRequest},
+	{services.ErrConflict, http.StatusConflict},
+	{services.ErrUnavailable, http.StatusServiceUnavailable},
+}
+
+// HTTPErrorHandler answers the errors returned by the handlers and middlewares. The errors of the services
+// get the status of their kind, echo.HTTPError keeps its own, and any other error is an internal error.
+// API v2 answers with problem details and API v1 with an error object.
+func HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		return
+	}
+
+	status, message := errorResponse(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("Error handling %s %s: %v", ctx.Request().Method, ctx.Request().URL.Path, err)
+	}
+
+	if ctx.Request().Method == http.MethodHead {
+		err = ctx.NoContent(status)
+	} else if strings.HasPrefix(ctx.Request().URL.Path, "/v2/") {
+		err = v2.WriteProblem(ctx, status, message)
+	} else {
+		err = ctx.JSON(status, map[string]string{"error": message})
+	}
+	if err != nil {
+		log.Printf("Error sending error response: %v", err)
+	}
+}
+
+// errorResponse chooses the status and message describing an error
+func errorResponse(err error) (int, string) {
+	for _, ks := range kindStatuses {
+		if errors.Is(err, ks.kind) {
+			// The message of the service is kept over the description of the handler
+			var he *echo.HTTPError
+			if errors.As(err, &he) && he.Internal != nil {
+				return ks.status, he.Internal.Error()
+			}
+			return ks.status, err.Error()
+		}
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		switch msg := he.Message.(type) {
+		case string:
+			return he.Code, msg
+		case map[string]string:
+			return he.Code, msg["error"]
+		default:
+			return he.Code, fmt.Sprint(msg)
+		}
+	}
+
+	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+}
@@ -0,0 +1,174 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Helper functions estimating the cost of a query before it runs
+
+const (
+	// maxComplexity is the highest cost accepted for a single query
+	maxComplexity = 1000
+	// complexityPerRequest is the cost charged to the rate limit as one request
+	complexityPerRequest = 50
+	// listEstimate is the assumed length of lists whose size cannot be requested
+	listEstimate = 10
+	// maxGenerations is the highest number of generating fields accepted in a single operation
+	maxGenerations = 10
+)
+
+// fieldCosts holds the own cost of the fields that do more than read a value
+var fieldCosts = map[string]int{
+	"generate_world":    10,
+	"generate_location": 10,
+	"worlds":            5,
+	"locations":         2,
+}
+
+// generatingFields are the mutations generating a resource, each charged as at least one request
+var generatingFields = map[string]bool{
+	"generate_world":    true,
+	"generate_location": true,
+}
+
+// listSizes gives the argument holding the length of a list field and its default, or
+// an empty argument when the length is fixed. Selections under a list are paid for each item.
+var listSizes = map[string]struct {
+	arg          string
+	defaultValue int
+}{
+	"worlds":    {"limit", 10},
+	"npcs":      {"count", 5},
+	"hooks":     {"count", 5},
+	"history":   {"", listEstimate},
+	"locations": {"", listEstimate},
+}
+
+// complexity computes the cost of the selected operation: each field costs 1 or its own cost,
+// plus the cost of its selections, multiplied by the length of the list it returns
+func complexity(doc *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) int {
+	fragments := documentFragments(doc)
+
+	var cost func(set *ast.SelectionSet) int
+	cost = func(set *ast.SelectionSet) int {
+		if set == nil {
+			return 0
+		}
+		total := 0
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				own, ok := fieldCosts[s.Name.Value]
+				if !ok {
+					own = 1
+				}
+				total += own + listSize(s, variables)*cost(s.SelectionSet)
+			case *ast.InlineFragment:
+				total += cost(s.SelectionSet)
+			case *ast.FragmentSpread:
+				// Validation has already rejected fragment cycles
+				if fragment, ok := fragments[s.Name.Value]; ok {
+					total += cost(fragment.SelectionSet)
+				}
+			}
+		}
+		return total
+	}
+
+	return cost(operation.SelectionSet)
+}
+
+// generations counts the generating fields of the selected operation, aliases and fragments included
+func generations(doc *ast.Document, operation *ast.OperationDefinition) int {
+	fragments := documentFragments(doc)
+
+	var count func(set *ast.SelectionSet) int
+	count = func(set *ast.SelectionSet) int {
+		if set == nil {
+			return 0
+		}
+		total := 0
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				if generatingFields[s.Name.Value] {
+					total++
+				}
+			case *ast.InlineFragment:
+				total += count(s.SelectionSet)
+			case *ast.FragmentSpread:
+				if fragment, ok := fragments[s.Name.Value]; ok {
+					total += count(fragment.SelectionSet)
+				}
+			}
+		}
+		return total
+	}
+
+	return count(operation.SelectionSet)
+}
+
+func documentFragments(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return fragments
+}
+
+// listSize returns the number of items a field is expected to return
+func listSize(field *ast.Field, variables map[string]interface{}) int {
+	size, ok := listSizes[field.Name.Value]
+	if !ok {
+		return 1
+	}
+	if size.arg == "" {
+		return size.defaultValue
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != size.arg {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				return min(max(n, 1), maxComplexity)
+			}
+		case *ast.Variable:
+			// Variables decoded from JSON are float64
+			if n, ok := variables[v.Name.Value].(float64); ok {
+				return min(max(int(n), 1), maxComplexity)
+			}
+		}
+	}
+	return size.defaultValue
+}
+
+// selectOperation finds the operation to run, by name when the document holds several
+func selectOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var selected *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if selected != nil {
+				return nil, fmt.Errorf("the operation name is required when the query holds several operations")
+			}
+			selected = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			selected = operation
+		}
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("unknown operation %q", name)
+	}
+	return selected, nil
+}
@@ -0,0 +1,68 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func parseOperation(t *testing.T, query string) (*ast.Document, *ast.OperationDefinition) {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	operation, err := selectOperation(doc, "")
+	if err != nil {
+		t.Fatalf("selectOperation() error = %v", err)
+	}
+	return doc, operation
+}
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      int
+	}{
+		{"field", `{ world(id: 1) { name } }`, nil, 2},
+		{"default list size", `{ worlds { name } }`, nil, 5 + 10},
+		{"list size argument", `{ worlds(limit: 3) { name theme } }`, nil, 5 + 3*2},
+		{"list size variable", `query($n: Int) { worlds(limit: $n) { name } }`, map[string]interface{}{"n": float64(20)}, 5 + 20},
+		{"fragment", `{ world(id: 1) { ...f } } fragment f on World { name theme }`, nil, 1 + 2},
+		{"generation", `mutation { generate_world { name } }`, nil, 10 + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, operation := parseOperation(t, tt.query)
+			if got := complexity(doc, operation, tt.variables); got != tt.want {
+				t.Errorf("complexity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerations(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"query", `{ worlds { name } }`, 0},
+		{"single", `mutation { generate_world { id } }`, 1},
+		{"aliases", `mutation { a: generate_world { id } b: generate_world { id } c: generate_location(world_id: 1) { id } }`, 3},
+		{"fragment", `mutation { ...g ...g } fragment g on Mutation { generate_world { id } }`, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, operation := parseOperation(t, tt.query)
+			if got := generations(doc, operation); got != tt.want {
+				t.Errorf("generations() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package gql
+
+import (
+	"errors"
+
+	"github.com/medinapdr/world-gen/services"
+)
+
+// resolverError is an error of a resolver reporting the kind of the service error in its extensions
+type resolverError struct {
+	err  error
+	code string
+}
+
+func (e *resolverError) Error() string { return e.err.Error() }
+func (e *resolverError) Unwrap() error { return e.err }
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// serviceError converts an error of the services to a GraphQL error coded after its kind
+func serviceError(err error) error {
+	code := "INTERNAL_SERVER_ERROR"
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		code = "BAD_USER_INPUT"
+	case errors.Is(err, services.ErrNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, services.ErrConflict):
+		code = "CONFLICT"
+	case errors.Is(err, services.ErrUnavailable):
+		code = "SERVICE_UNAVAILABLE"
+	}
+	return &resolverError{err: err, code: code}
+}
@@ -0,0 +1,133 @@
+// Package gql serves the GraphQL API, which exposes the same worlds as the REST API
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// GraphQLController serves GraphQL queries and mutations
+type GraphQLController struct {
+	worldService    *services.WorldService
+	locationService *services.LocationService
+	schema          graphql.Schema
+}
+
+// GraphQLRequest is a GraphQL query sent as JSON
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// NewGraphQLController creates a new instance of the controller
+func NewGraphQLController(worldService *services.WorldService, locationService *services.LocationService) *GraphQLController {
+	schema, err := newSchema(worldService, locationService)
+	if err != nil {
+		// The schema is static, so this only happens when it is defined incorrectly
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+
+	return &GraphQLController{
+		worldService:    worldService,
+		locationService: locationService,
+		schema:          schema,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *GraphQLController) RegisterRoutes(e *echo.Echo) {
+	e.POST("/graphql", c.Query)
+	e.GET("/graphql", c.Query)
+}
+
+// @Tags GraphQL
+// @Summary Runs a GraphQL query
+// @Description Queries worlds, their NPCs, hooks and locations, or generates worlds and locations. The schema can be introspected.
+// @Description Field names match the JSON names of the REST API. Each query costs 1 per field, multiplied by the length of the lists
+// @Description it is nested in; queries above 1000 are rejected, and every 50 counts as one request for the rate limit.
+// @Description Each generation counts as at least one request, and a query holds at most 10 generations.
+// @Description GET requests take the query, operationName and variables parameters and cannot run mutations.
+// @Accept json
+// @Produce json
+// @Param request body GraphQLRequest false "Query, operation name and variables"
+// @Success 200 {object} map[string]interface{} "data and errors"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /graphql [post]
+func (c *GraphQLController) Query(ctx echo.Context) error {
+	var req GraphQLRequest
+	if ctx.Request().Method == http.MethodGet {
+		req.Query = ctx.QueryParam("query")
+		req.OperationName = ctx.QueryParam("operationName")
+		if variables := ctx.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return graphQLError(ctx, http.StatusBadRequest, "Invalid variables")
+			}
+		}
+	} else if err := ctx.Bind(&req); err != nil {
+		return graphQLError(ctx, http.StatusBadRequest, "Invalid request body")
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+	if validation := graphql.ValidateDocument(&c.schema, doc, nil); !validation.IsValid {
+		return ctx.JSON(http.StatusBadRequest, &graphql.Result{Errors: validation.Errors})
+	}
+
+	operation, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return graphQLError(ctx, http.StatusBadRequest, err.Error())
+	}
+	if operation.Operation == ast.OperationTypeMutation && ctx.Request().Method == http.MethodGet {
+		return graphQLError(ctx, http.StatusMethodNotAllowed, "Mutations must be sent with POST")
+	}
+
+	cost := complexity(doc, operation, req.Variables)
+	if cost > maxComplexity {
+		return graphQLError(ctx, http.StatusBadRequest,
+			fmt.Sprintf("Query complexity %d exceeds the limit of %d", cost, maxComplexity))
+	}
+	// Every generation is charged like a request to the REST API, however cheap its selection
+	count := generations(doc, operation)
+	if count > maxGenerations {
+		return graphQLError(ctx, http.StatusBadRequest,
+			fmt.Sprintf("Query holds %d generations, exceeding the limit of %d", count, maxGenerations))
+	}
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, max(1+cost/complexityPerRequest, count)); err == nil && exceeded {
+		return graphQLError(ctx, http.StatusTooManyRequests, "Request limit exceeded. Try again later.")
+	}
+	ctx.Response().Header().Set("X-Query-Complexity", fmt.Sprint(cost))
+
+	reqCtx := withLoaders(ctx.Request().Context(), newLoaders(c.worldService, c.locationService))
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        c.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       reqCtx,
+	})
+
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// graphQLError sends an error in the GraphQL response format
+func graphQLError(ctx echo.Context, status int, message string) error {
+	return ctx.JSON(status, &graphql.Result{
+		Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)},
+	})
+}
@@ -0,0 +1,91 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// loader batches the keys requested while a level of the query is resolved and fetches
+// them with a single call once the first value is needed. Results are cached for the request.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, results: make(map[K]V), errs: make(map[K]error)}
+}
+
+// load queues a key and returns a thunk resolving its value, as expected by graphql-go resolvers
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, done := l.results[key]; !done && l.errs[key] == nil && !containsKey(l.pending, key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if containsKey(l.pending, key) {
+			keys := l.pending
+			l.pending = nil
+			results, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+				} else {
+					// Keys without a result resolve to null
+					l.results[k] = results[k]
+				}
+			}
+		}
+
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.results[key], nil
+	}
+}
+
+func containsKey[K comparable](keys []K, key K) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// loaders holds the loaders of a single request
+type loaders struct {
+	worlds    *loader[int, *models.World]
+	locations *loader[int, []models.Location]
+}
+
+func newLoaders(worldService *services.WorldService, locationService *services.LocationService) *loaders {
+	return &loaders{
+		worlds: newLoader(worldService.GetWorldsByIDs),
+		locations: newLoader(func(ctx context.Context, worldIDs []int) (map[int][]models.Location, error) {
+			return locationService.GetLocationsByWorldIDs(ctx, worldIDs)
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,307 @@
+package gql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// Field names follow the JSON names of the REST API so that clients can move between both
+
+// maxSearchLimit caps the page size of worlds searches, like the limit of /v1/worlds
+const maxSearchLimit = 100
+
+// jsonScalar passes nested structures through as they are serialized by the REST API
+var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "A value serialized as JSON, shaped like in the REST API",
+	Serialize:   func(value interface{}) interface{} { return value },
+})
+
+var stringList = graphql.NewList(graphql.String)
+
+// newSchema builds the schema, whose resolvers call the given services
+func newSchema(worldService *services.WorldService, locationService *services.LocationService) (graphql.Schema, error) {
+	npcType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "NPC",
+		Description: "A non-player character derived from a world and its ID",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.Int},
+			"world_id":    &graphql.Field{Type: graphql.Int},
+			"name":        &graphql.Field{Type: graphql.String},
+			"age":         &graphql.Field{Type: graphql.Int},
+			"culture":     &graphql.Field{Type: graphql.String},
+			"language":    &graphql.Field{Type: graphql.String},
+			"religion":    &graphql.Field{Type: graphql.String},
+			"occupation":  &graphql.Field{Type: graphql.String},
+			"traits":      &graphql.Field{Type: stringList},
+			"motivations": &graphql.Field{Type: stringList},
+		},
+	})
+
+	hookType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "AdventureHook",
+		Description: "An adventure seed derived from a world and its ID",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.Int},
+			"world_id":     &graphql.Field{Type: graphql.Int},
+			"title":        &graphql.Field{Type: graphql.String},
+			"tier":         &graphql.Field{Type: graphql.Int},
+			"tier_name":    &graphql.Field{Type: graphql.String},
+			"patron":       &graphql.Field{Type: jsonScalar},
+			"objective":    &graphql.Field{Type: graphql.String},
+			"complication": &graphql.Field{Type: graphql.String},
+			"location":     &graphql.Field{Type: graphql.String},
+			"reward":       &graphql.Field{Type: jsonScalar},
+		},
+	})
+
+	locationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Location",
+		Description: "A point of interest of a world, tied to one of its dangers",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.Int},
+			"world_id":    &graphql.Field{Type: graphql.Int},
+			"name":        &graphql.Field{Type: graphql.String},
+			"type":        &graphql.Field{Type: graphql.String},
+			"danger":      &graphql.Field{Type: graphql.String},
+			"feature":     &graphql.Field{Type: graphql.String},
+			"history":     &graphql.Field{Type: graphql.String},
+			"layout":      &graphql.Field{Type: jsonScalar},
+			"inhabitants": &graphql.Field{Type: jsonScalar},
+			"treasure":    &graphql.Field{Type: jsonScalar},
+			"created_at":  &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	worldType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "World",
+		Description: "A generated world",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.Int},
+			"name":         &graphql.Field{Type: graphql.String},
+			"description":  &graphql.Field{Type: graphql.String},
+			"population":   &graphql.Field{Type: graphql.Int},
+			"climate":      &graphql.Field{Type: graphql.String},
+			"theme":        &graphql.Field{Type: graphql.String},
+			"features":     &graphql.Field{Type: stringList},
+			"fauna":        &graphql.Field{Type: stringList},
+			"flora":        &graphql.Field{Type: stringList},
+			"cultures":     &graphql.Field{Type: stringList},
+			"dangers":      &graphql.Field{Type: stringList},
+			"languages":    &graphql.Field{Type: stringList},
+			"religions":    &graphql.Field{Type: jsonScalar},
+			"power_system": &graphql.Field{Type: jsonScalar},
+			"rarities":     &graphql.Field{Type: jsonScalar},
+			"system_id":    &graphql.Field{Type: graphql.Int},
+			"seed":         &graphql.Field{Type: graphql.String, Resolve: resolveSeed},
+			"created_at":   &graphql.Field{Type: graphql.DateTime},
+			"npcs": &graphql.Field{
+				Type:        graphql.NewList(npcType),
+				Description: "NPCs of the world, numbered from 1",
+				Args: graphql.FieldConfigArgument{
+					"count":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 5},
+					"culture": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					culture, _ := p.Args["culture"].(string)
+					count := clamp(p.Args["count"].(int), services.MaxNPCCount)
+					return worldService.GenerateNPCs(p.Source.(*models.World), count, culture)
+				},
+			},
+			"hooks": &graphql.Field{
+				Type:        graphql.NewList(hookType),
+				Description: "Adventure hooks of the world, numbered from 1",
+				Args: graphql.FieldConfigArgument{
+					"count": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 5},
+					"tier":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					count := clamp(p.Args["count"].(int), services.MaxHookCount)
+					return worldService.GenerateHooks(p.Source.(*models.World), count, p.Args["tier"].(int), nil)
+				},
+			},
+			"locations": &graphql.Field{
+				Type:        graphql.NewList(locationType),
+				Description: "Points of interest generated for the world, loaded in one query for every world of the response",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).locations.load(p.Context, p.Source.(*models.World).ID), nil
+				},
+			},
+		},
+	})
+
+	// The world of a location is batched like the worlds requested by ID
+	locationType.AddFieldConfig("world", &graphql.Field{
+		Type: worldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return loadersFrom(p.Context).worlds.load(p.Context, locationOf(p.Source).WorldID), nil
+		},
+	})
+
+	worldPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "WorldPage",
+		Description: "A page of worlds matching a search",
+		Fields: graphql.Fields{
+			"data":   &graphql.Field{Type: graphql.NewList(worldType), Resolve: resolvePageWorlds},
+			"total":  &graphql.Field{Type: graphql.Int},
+			"limit":  &graphql.Field{Type: graphql.Int},
+			"offset": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"world": &graphql.Field{
+				Type:        worldType,
+				Description: "A world by ID; worlds requested several times in a query are loaded at once",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).worlds.load(p.Context, p.Args["id"].(int)), nil
+				},
+			},
+			"worlds": &graphql.Field{
+				Type:        worldPageType,
+				Description: "Searches worlds by name or description, theme and climate",
+				Args: graphql.FieldConfigArgument{
+					"query":   &graphql.ArgumentConfig{Type: graphql.String},
+					"theme":   &graphql.ArgumentConfig{Type: graphql.String},
+					"climate": &graphql.ArgumentConfig{Type: graphql.String},
+					"limit":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+					"offset":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					text, _ := p.Args["query"].(string)
+					theme, _ := p.Args["theme"].(string)
+					climate, _ := p.Args["climate"].(string)
+					limit := clamp(p.Args["limit"].(int), maxSearchLimit)
+					offset := max(p.Args["offset"].(int), 0)
+
+					worlds, total, err := worldService.SearchWorlds(p.Context, text, theme, climate, limit, offset)
+					if err != nil {
+						return nil, fmt.Errorf("failed to search worlds")
+					}
+					return models.PaginatedWorldsResponse{Data: worlds, Total: total, Limit: limit, Offset: offset}, nil
+				},
+			},
+			"history": &graphql.Field{
+				Type:        graphql.NewList(worldType),
+				Description: "The latest generated worlds",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					worlds, err := worldService.GetWorldHistory(p.Context)
+					if err != nil {
+						return nil, fmt.Errorf("failed to retrieve history")
+					}
+					return worldPointers(worlds), nil
+				},
+			},
+			"location": &graphql.Field{
+				Type:        locationType,
+				Description: "A point of interest of a world",
+				Args: graphql.FieldConfigArgument{
+					"world_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return locationService.GetLocationByID(p.Context, p.Args["world_id"].(int), p.Args["id"].(int))
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"generate_world": &graphql.Field{
+				Type:        worldType,
+				Description: "Generates and stores a new world",
+				Args: graphql.FieldConfigArgument{
+					"theme":   &graphql.ArgumentConfig{Type: graphql.String},
+					"climate": &graphql.ArgumentConfig{Type: graphql.String},
+					"seed":    &graphql.ArgumentConfig{Type: graphql.String, Description: "64-bit seed, as a string"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var req models.GenerateWorldRequest
+					req.Theme, _ = p.Args["theme"].(string)
+					req.Climate, _ = p.Args["climate"].(string)
+					if err := worldService.ValidateGenerateRequest(&req); err != nil {
+						return nil, serviceError(err)
+					}
+
+					var opts []services.GenerateOption
+					if req.Climate != "" {
+						opts = append(opts, services.WithClimate(req.Climate))
+					}
+					if seedArg, ok := p.Args["seed"].(string); ok {
+						var seed int64
+						if _, err := fmt.Sscan(seedArg, &seed); err != nil {
+							return nil, &resolverError{err: fmt.Errorf("invalid seed %q", seedArg), code: "BAD_USER_INPUT"}
+						}
+						opts = append(opts, services.WithSeed(seed))
+					}
+					world, err := worldService.GenerateWorld(p.Context, req.Theme, opts...)
+					if err != nil {
+						return nil, serviceError(err)
+					}
+					return world, nil
+				},
+			},
+			"generate_location": &graphql.Field{
+				Type:        locationType,
+				Description: "Generates a point of interest of a world, around a random danger and feature when none is given",
+				Args: graphql.FieldConfigArgument{
+					"world_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"danger":   &graphql.ArgumentConfig{Type: graphql.String},
+					"feature":  &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					world, err := worldService.GetWorldByID(p.Context, p.Args["world_id"].(int))
+					if err != nil {
+						return nil, err
+					}
+					danger, _ := p.Args["danger"].(string)
+					feature, _ := p.Args["feature"].(string)
+					return locationService.GenerateLocation(p.Context, world, models.CreateLocationRequest{Danger: danger, Feature: feature})
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// resolveSeed returns seeds as strings, since they do not fit in a GraphQL Int
+func resolveSeed(p graphql.ResolveParams) (interface{}, error) {
+	return fmt.Sprint(p.Source.(*models.World).Seed), nil
+}
+
+func resolvePageWorlds(p graphql.ResolveParams) (interface{}, error) {
+	return worldPointers(p.Source.(models.PaginatedWorldsResponse).Data), nil
+}
+
+// worldPointers converts worlds so that every World resolver receives a *models.World
+func worldPointers(worlds []models.World) []*models.World {
+	pointers := make([]*models.World, len(worlds))
+	for i := range worlds {
+		pointers[i] = &worlds[i]
+	}
+	return pointers
+}
+
+// locationOf accepts the locations returned by value in lists and by pointer otherwise
+func locationOf(source interface{}) *models.Location {
+	if location, ok := source.(models.Location); ok {
+		return &location
+	}
+	return source.(*models.Location)
+}
+
+// clamp keeps a requested count between 1 and maxCount
+func clamp(count, maxCount int) int {
+	return min(max(count, 1), maxCount)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// Interval between the comments keeping idle event streams open through proxies
+const historyHeartbeat = 15 * time.Second
+
+// History answers the latest generated worlds
+func History(ctx echo.Context, worldService *services.WorldService) error {
+	worlds, err := worldService.GetWorldHistory(ctx.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve history").SetInternal(err)
+	}
+
+	return ctx.JSON(http.StatusOK, worlds)
+}
+
+// StreamHistory pushes every newly generated world of the theme and climate of the query as a Server-Sent Event,
+// after replaying the worlds generated since the last event ID the client sent
+func StreamHistory(ctx echo.Context, eventBus *services.EventBus) error {
+	theme := ctx.QueryParam("theme")
+	climate := ctx.QueryParam("climate")
+
+	lastEventID := int64(0)
+	lastEventParam := ctx.Request().Header.Get("Last-Event-ID")
+	if lastEventParam == "" {
+		lastEventParam = ctx.QueryParam("last_event_id")
+	}
+	if lastEventParam != "" {
+		id, err := strconv.ParseInt(lastEventParam, 10, 64)
+		if err != nil || id < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid last event ID")
+		}
+		lastEventID = id
+	}
+
+	reqCtx := ctx.Request().Context()
+	sub, err := eventBus.Subscribe(reqCtx, lastEventID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to subscribe to new worlds").SetInternal(err)
+	}
+	defer sub.Close()
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	// Skip live events already sent as part of the replay
+	replayed := make(map[int64]bool, len(sub.Replay))
+	send := func(event models.WorldEvent) error {
+		if event.Type != services.EventWorldGenerated {
+			return nil
+		}
+		if (theme != "" && event.World.Theme != theme) || (climate != "" && event.World.Climate != climate) {
+			return nil
+		}
+		data, err := json.Marshal(event.World)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	for _, event := range sub.Replay {
+		replayed[event.ID] = true
+		if err := send(event); err != nil {
+			return nil
+		}
+	}
+
+	heartbeat := time.NewTicker(historyHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				// The subscriber fell behind; the client reconnects and resumes from its last event
+				return nil
+			}
+			if replayed[event.ID] {
+				continue
+			}
+			if err := send(event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-reqCtx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+const (
+	// Time allowed to write a message to the participant
+	sessionWriteWait = 10 * time.Second
+	// Participants that stop answering pings for this long are disconnected
+	sessionPongWait   = 60 * time.Second
+	sessionPingPeriod = sessionPongWait * 9 / 10
+	// Largest message accepted from a participant
+	sessionMaxMessageSize = 64 * 1024
+)
+
+var sessionUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The API allows every origin, like the CORS middleware
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeSession upgrades the request to a WebSocket joining the editing session of a world
+// as the user of the query, and relays its messages until the participant leaves
+func ServeSession(ctx echo.Context, sessionService *services.SessionService, world *models.World) error {
+	user := ctx.QueryParam("user")
+	if user == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "The user parameter is required")
+	}
+
+	conn, err := sessionUpgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		// The upgrader has already answered the request
+		return nil
+	}
+	defer conn.Close()
+
+	// The session outlives the request context, which ends with the upgrade on some servers
+	sessionCtx := context.Background()
+	client, err := sessionService.Join(sessionCtx, world, user)
+	if err != nil {
+		conn.WriteJSON(models.SessionMessage{Type: services.SessionError, Message: "Failed to join the session"})
+		return nil
+	}
+	defer sessionService.Leave(sessionCtx, client)
+
+	go writeSessionMessages(conn, client)
+
+	conn.SetReadLimit(sessionMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(sessionPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(sessionPongWait))
+	})
+	for {
+		var msg models.SessionMessage
+		err := conn.ReadJSON(&msg)
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+			// Malformed messages are reported without closing the connection
+			msg = models.SessionMessage{Type: "invalid JSON"}
+		} else if err != nil {
+			return nil
+		}
+		sessionService.Handle(sessionCtx, client, msg)
+	}
+}
+
+// writeSessionMessages forwards the messages of the session to the participant and keeps the connection alive
+func writeSessionMessages(conn *websocket.Conn, client *services.SessionClient) {
+	ticker := time.NewTicker(sessionPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-client.Messages:
+			conn.SetWriteDeadline(time.Now().Add(sessionWriteWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too slow"))
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(sessionWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
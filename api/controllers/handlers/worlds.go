@@ -0,0 +1,175 @@
+// Package handlers holds the handlers shared by the API versions. Errors are returned to the central
+// error handler, which answers them in the format of the version the request was sent to.
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// FindWorld loads the world referenced by the id path parameter
+func FindWorld(ctx echo.Context, worldService *services.WorldService) (*models.World, error) {
+	id, err := PathID(ctx, "id", "world")
+	if err != nil {
+		return nil, err
+	}
+
+	return worldService.GetWorldByID(ctx.Request().Context(), id)
+}
+
+// PathID parses a positive integer ID path parameter
+func PathID(ctx echo.Context, param, resource string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(param))
+	if err != nil || id <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+resource+" ID")
+	}
+	return id, nil
+}
+
+// Religions answers the religions of a world, as an empty list when it has none
+func Religions(ctx echo.Context, world *models.World) error {
+	religions := world.Religions
+	if religions == nil {
+		religions = []models.Religion{}
+	}
+
+	return ctx.JSON(http.StatusOK, religions)
+}
+
+// Weather answers the weather of a region of a world on the date of the query
+func Weather(ctx echo.Context, worldService *services.WorldService, world *models.World) error {
+	weather, err := worldService.GenerateWeather(world, ctx.QueryParam("date"), ctx.QueryParam("region"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, weather)
+}
+
+// NPCs answers count NPCs of a world, of the culture of the query when there is one
+func NPCs(ctx echo.Context, worldService *services.WorldService, world *models.World, count int) error {
+	npcs, err := worldService.GenerateNPCs(world, count, ctx.QueryParam("culture"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, npcs)
+}
+
+// Hooks answers count adventure hooks of a world, skipping those used in the campaign of the query
+func Hooks(ctx echo.Context, worldService *services.WorldService, world *models.World, count, tier int) error {
+	var used map[int]bool
+	if campaign := ctx.QueryParam("campaign"); campaign != "" {
+		var err error
+		used, err = worldService.GetUsedHookIDs(ctx.Request().Context(), world.ID, campaign)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve used hooks").SetInternal(err)
+		}
+	}
+
+	hooks, err := worldService.GenerateHooks(world, count, tier, used)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, hooks)
+}
+
+// UseHook records the use of an adventure hook in the campaign of the request body
+func UseHook(ctx echo.Context, worldService *services.WorldService, world *models.World) error {
+	hookID, err := PathID(ctx, "hook_id", "hook")
+	if err != nil {
+		return err
+	}
+
+	var req models.UseHookRequest
+	if err := ctx.Bind(&req); err != nil || req.Campaign == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "A campaign is required")
+	}
+
+	usage, err := worldService.MarkHookUsed(ctx.Request().Context(), world.ID, hookID, req.Campaign)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark hook as used").SetInternal(err)
+	}
+
+	return ctx.JSON(http.StatusCreated, usage)
+}
+
+// EncounterTables answers the encounter tables of a world, only the one of the region of the query when there is one
+func EncounterTables(ctx echo.Context, worldService *services.WorldService, world *models.World) error {
+	tables, err := worldService.GenerateEncounterTables(world, ctx.QueryParam("region"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, tables)
+}
+
+// RollEncounter rolls on an encounter table of a world, answering the roll with the given status
+func RollEncounter(ctx echo.Context, worldService *services.WorldService, world *models.World, status int) error {
+	var req models.RollEncounterRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	roll, err := worldService.RollEncounter(world, req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return ctx.JSON(status, roll)
+}
+
+// EncounterRollTable answers the encounter table of a region as a Foundry VTT RollTable attachment
+func EncounterRollTable(ctx echo.Context, worldService *services.WorldService, world *models.World) error {
+	table, err := worldService.ExportEncounterTable(world, ctx.QueryParam("region"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="world-%d-encounters.json"`, world.ID))
+	return ctx.JSON(http.StatusOK, table)
+}
+
+// GenerateBatch generates the batch of worlds of the request body, streaming each one as a line of NDJSON.
+// The batch is charged to the rate limit as count requests.
+func GenerateBatch(ctx echo.Context, worldService *services.WorldService) error {
+	var req models.BatchGenerateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := worldService.ValidateBatchRequest(&req); err != nil {
+		return err
+	}
+
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, req.Count); err == nil && exceeded {
+		return middlewares.ErrRateLimitExceeded
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(res)
+	err := worldService.GenerateWorldBatch(ctx.Request().Context(), req, func(w *models.World) error {
+		if err := enc.Encode(w); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err != nil && ctx.Request().Context().Err() == nil {
+		enc.Encode(map[string]string{"error": err.Error()})
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/medinapdr/world-gen/models"
+	worldgenv1 "github.com/medinapdr/world-gen/proto/worldgen/v1"
+)
+
+// Helper functions converting models to their protobuf messages
+
+func toProtoWorld(w *models.World) *worldgenv1.World {
+	pw := &worldgenv1.World{
+		Id:          int32(w.ID),
+		Name:        w.Name,
+		Description: w.Description,
+		Population:  int64(w.Population),
+		Climate:     w.Climate,
+		Theme:       w.Theme,
+		Features:    w.Features,
+		Fauna:       w.Fauna,
+		Flora:       w.Flora,
+		Cultures:    w.Cultures,
+		Dangers:     w.Dangers,
+		Languages:   w.Languages,
+		PowerSystem: toProtoPowerSystem(w.PowerSystem),
+		Seed:        w.Seed,
+		Rarities:    w.Rarities,
+	}
+	if w.SystemID != nil {
+		systemID := int32(*w.SystemID)
+		pw.SystemId = &systemID
+	}
+	if !w.CreatedAt.IsZero() {
+		pw.CreatedAt = timestamppb.New(w.CreatedAt)
+	}
+	for _, religion := range w.Religions {
+		pw.Religions = append(pw.Religions, toProtoReligion(religion))
+	}
+	return pw
+}
+
+func toProtoReligion(r models.Religion) *worldgenv1.Religion {
+	pr := &worldgenv1.Religion{
+		Name:      r.Name,
+		Type:      r.Type,
+		Doctrine:  r.Doctrine,
+		Cultures:  r.Cultures,
+		Domains:   r.Domains,
+		Symbols:   r.Symbols,
+		HolySites: r.HolySites,
+		Rituals:   r.Rituals,
+		Taboos:    r.Taboos,
+	}
+	for _, deity := range r.Deities {
+		pr.Deities = append(pr.Deities, &worldgenv1.Deity{
+			Name:    deity.Name,
+			Title:   deity.Title,
+			Domains: deity.Domains,
+			Symbol:  deity.Symbol,
+		})
+	}
+	return pr
+}
+
+func toProtoPowerSystem(p *models.PowerSystem) *worldgenv1.PowerSystem {
+	if p == nil {
+		return nil
+	}
+
+	pp := &worldgenv1.PowerSystem{
+		Type:        p.Type,
+		Name:        p.Name,
+		Summary:     p.Summary,
+		Cultures:    p.Cultures,
+		Dangers:     p.Dangers,
+		Consequence: p.Consequence,
+	}
+	if m := p.Magic; m != nil {
+		pp.Magic = &worldgenv1.MagicSystem{
+			Source:        m.Source,
+			Cost:          m.Cost,
+			Limitations:   m.Limitations,
+			Practitioners: m.Practitioners,
+		}
+	}
+	if t := p.Technology; t != nil {
+		pp.Technology = &worldgenv1.TechnologyLevel{
+			Level:        t.Level,
+			Ftl:          t.FTL,
+			AiStatus:     t.AIStatus,
+			EnergySource: t.EnergySource,
+		}
+	}
+	if c := p.Collapse; c != nil {
+		pp.Collapse = &worldgenv1.Collapse{
+			Type:          c.Type,
+			YearsAgo:      int32(c.YearsAgo),
+			SurvivingTech: c.SurvivingTech,
+		}
+	}
+	return pp
+}
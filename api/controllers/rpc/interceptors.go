@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"net"
+	"runtime/debug"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/medinapdr/world-gen/middlewares"
+)
+
+// errRateLimitExceeded is returned when a client exceeds its request budget, like the 429 of the REST API
+var errRateLimitExceeded = status.Error(codes.ResourceExhausted, "Request limit exceeded. Try again later.")
+
+// recoverUnary turns a panic of a handler into an Internal error instead of crashing the server
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer recoverHandler(info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+// recoverStream turns a panic of a stream handler into an Internal error instead of crashing the server
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverHandler(info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+func recoverHandler(method string, err *error) {
+	if r := recover(); r != nil {
+		log.Printf("Panic in gRPC method %s: %v\n%s", method, r, debug.Stack())
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// rateLimitUnary counts every call against the budget of the calling address
+func rateLimitUnary(limiter *middlewares.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkRateLimit(ctx, limiter, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStream counts the opening of a stream against the budget of the calling address, like an SSE connection
+func rateLimitStream(limiter *middlewares.RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkRateLimit(ss.Context(), limiter, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// checkRateLimit charges a call, letting it through on Redis errors to avoid blocking requests
+func checkRateLimit(ctx context.Context, limiter *middlewares.RateLimiter, method string) error {
+	// Skip rate limiting for health checks
+	if strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	clientIP := p.Addr.String()
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	if exceeded, err := limiter.Allow(ctx, clientIP); err == nil && exceeded {
+		return errRateLimitExceeded
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package rpc serves the gRPC API, backed by the same services as the REST API
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/models"
+	worldgenv1 "github.com/medinapdr/world-gen/proto/worldgen/v1"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// maxSearchLimit caps the page size of searches, like the limit of /v1/worlds
+const maxSearchLimit = 100
+
+// WorldServer implements the WorldGenerator gRPC service
+type WorldServer struct {
+	worldgenv1.UnimplementedWorldGeneratorServer
+
+	worldService *services.WorldService
+	eventBus     *services.EventBus
+}
+
+// NewServer creates a gRPC server exposing the WorldGenerator service, health checking and reflection.
+// Calls are rate limited with the budgets of the REST API when a rate limiter is given.
+func NewServer(worldService *services.WorldService, eventBus *services.EventBus, rateLimiter *middlewares.RateLimiter) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{recoverUnary}
+	stream := []grpc.StreamServerInterceptor{recoverStream}
+	if rateLimiter != nil {
+		unary = append(unary, rateLimitUnary(rateLimiter))
+		stream = append(stream, rateLimitStream(rateLimiter))
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	worldgenv1.RegisterWorldGeneratorServer(server, &WorldServer{
+		worldService: worldService,
+		eventBus:     eventBus,
+	})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(worldgenv1.WorldGenerator_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
+
+// Generate creates and stores a new world
+func (s *WorldServer) Generate(ctx context.Context, req *worldgenv1.GenerateRequest) (*worldgenv1.World, error) {
+	generateReq := models.GenerateWorldRequest{Theme: req.Theme, Climate: req.Climate}
+	if err := s.worldService.ValidateGenerateRequest(&generateReq); err != nil {
+		return nil, statusError(err)
+	}
+
+	var opts []services.GenerateOption
+	if generateReq.Climate != "" {
+		opts = append(opts, services.WithClimate(generateReq.Climate))
+	}
+	if req.Seed != nil {
+		opts = append(opts, services.WithSeed(req.GetSeed()))
+	}
+
+	world, err := s.worldService.GenerateWorld(ctx, generateReq.Theme, opts...)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toProtoWorld(world), nil
+}
+
+// Get retrieves a world by ID
+func (s *WorldServer) Get(ctx context.Context, req *worldgenv1.GetRequest) (*worldgenv1.World, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid world ID")
+	}
+
+	world, err := s.worldService.GetWorldByID(ctx, int(req.Id))
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toProtoWorld(world), nil
+}
+
+// Search finds worlds by name or description, theme and climate
+func (s *WorldServer) Search(ctx context.Context, req *worldgenv1.SearchRequest) (*worldgenv1.SearchResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 10
+	}
+	limit = min(limit, maxSearchLimit)
+	offset := max(int(req.Offset), 0)
+
+	worlds, total, err := s.worldService.SearchWorlds(ctx, req.Query, req.Theme, req.Climate, limit, offset)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &worldgenv1.SearchResponse{Total: int32(total), Limit: int32(limit), Offset: int32(offset)}
+	for i := range worlds {
+		resp.Worlds = append(resp.Worlds, toProtoWorld(&worlds[i]))
+	}
+	return resp, nil
+}
+
+// StreamHistory sends every newly generated world, after replaying the ones generated since last_event_id.
+// A stream that falls too far behind ends with Unavailable, so that the client resumes from its last event.
+func (s *WorldServer) StreamHistory(req *worldgenv1.StreamHistoryRequest, stream grpc.ServerStreamingServer[worldgenv1.WorldEvent]) error {
+	ctx := stream.Context()
+	sub, err := s.eventBus.Subscribe(ctx, req.LastEventId)
+	if err != nil {
+		return status.Error(codes.Unavailable, "failed to subscribe to new worlds")
+	}
+	defer sub.Close()
+
+	send := func(event models.WorldEvent) error {
+		if event.Type != services.EventWorldGenerated ||
+			(req.Theme != "" && event.World.Theme != req.Theme) ||
+			(req.Climate != "" && event.World.Climate != req.Climate) {
+			return nil
+		}
+		return stream.Send(&worldgenv1.WorldEvent{
+			Id:        event.ID,
+			Type:      event.Type,
+			World:     toProtoWorld(&event.World),
+			CreatedAt: timestamppb.New(event.CreatedAt),
+		})
+	}
+
+	// Skip live events already sent as part of the replay
+	replayed := make(map[int64]bool, len(sub.Replay))
+	for _, event := range sub.Replay {
+		replayed[event.ID] = true
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "the stream fell behind, resume from the last event received")
+			}
+			if replayed[event.ID] {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// statusError converts an error of the world service to a gRPC status
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, services.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, services.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
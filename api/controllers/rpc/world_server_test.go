@@ -0,0 +1,53 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/medinapdr/world-gen/config"
+	worldgenv1 "github.com/medinapdr/world-gen/proto/worldgen/v1"
+	"github.com/medinapdr/world-gen/services"
+)
+
+func TestGenerateValidatesRequest(t *testing.T) {
+	db := &config.DatabaseConfig{}
+	bus := services.NewEventBus(db)
+	server := &WorldServer{worldService: services.NewWorldService(db, config.NewAppConfig(), bus), eventBus: bus}
+
+	tests := []struct {
+		name string
+		req  *worldgenv1.GenerateRequest
+		want codes.Code
+	}{
+		{"unknown theme", &worldgenv1.GenerateRequest{Theme: "western"}, codes.InvalidArgument},
+		{"unknown climate", &worldgenv1.GenerateRequest{Theme: "fantasy", Climate: "Lava"}, codes.InvalidArgument},
+		// Without a database, worlds are generated without being saved
+		{"default theme", &worldgenv1.GenerateRequest{}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.Generate(context.Background(), tt.req)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("Generate() code = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRecoverInterceptors(t *testing.T) {
+	panicking := func(context.Context, any) (any, error) { panic("boom") }
+	_, err := recoverUnary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"}, panicking)
+	if status.Code(err) != codes.Internal {
+		t.Errorf("recoverUnary() error = %v, want Internal", err)
+	}
+
+	err = recoverStream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, func(any, grpc.ServerStream) error { panic("boom") })
+	if status.Code(err) != codes.Internal {
+		t.Errorf("recoverStream() error = %v, want Internal", err)
+	}
+}
@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// ExportController manages the export of worlds as documents for API v1
+type ExportController struct {
+	worldService  *services.WorldService
+	exportService *services.ExportService
+}
+
+// NewExportController creates a new instance of the controller
+func NewExportController(worldService *services.WorldService, exportService *services.ExportService) *ExportController {
+	return &ExportController{
+		worldService:  worldService,
+		exportService: exportService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *ExportController) RegisterRoutes(g *echo.Group) {
+	g.GET("/world/:id/export", c.ExportWorld)
+	g.GET("/world/:id/export/:target", c.ExportWorldTo)
+	g.GET("/worlds/export", c.ExportWorlds)
+}
+
+// @Tags Export
+// @Summary Exports a world as a document
+// @Description Renders a dossier of the world with its description, every list, its religions, power system and locations.
+// @Description The Markdown and HTML dossiers come from templates that can be replaced through EXPORT_TEMPLATE_DIR;
+// @Description the PDF dossier is laid out from the Markdown one.
+// @Produce text/markdown
+// @Produce text/html
+// @Produce application/pdf
+// @Param id path int true "World ID"
+// @Param format query string false "Document format" Enums(md,html,pdf) default(md)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/world/{id}/export [get]
+func (c *ExportController) ExportWorld(ctx echo.Context) error {
+	format := dossierFormat(ctx)
+	if err := services.ValidateDossierFormat(format); err != nil {
+		return err
+	}
+
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	doc, err := c.exportService.ExportWorld(ctx.Request().Context(), world, format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export world").SetInternal(err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="%s"`, services.DossierFileName(world, format)))
+	return ctx.Blob(http.StatusOK, services.DossierContentType(format), doc)
+}
+
+// @Tags Export
+// @Summary Exports a world to a tool
+// @Description Builds a file that a tool imports natively. The obsidian target is a zip file of an Obsidian vault,
+// @Description with one note per world, culture, danger, language and location linked to each other with wikilinks.
+// @Description The foundry target is a Foundry VTT journal entry, with a page per section of the world and per location,
+// @Description to import into a journal entry or a journal compendium.
+// @Produce application/zip
+// @Produce json
+// @Param id path int true "World ID"
+// @Param target path string true "Tool to export to" Enums(obsidian,foundry)
+// @Success 200 {object} models.FoundryJournalEntry "Journal entry of the foundry target; the obsidian target answers a zip file"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/world/{id}/export/{target} [get]
+func (c *ExportController) ExportWorldTo(ctx echo.Context) error {
+	target := strings.ToLower(ctx.Param("target"))
+	if err := services.ValidateExportTarget(target); err != nil {
+		return err
+	}
+
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	fileName := services.ExportFileName(world)
+	if target == services.ExportTargetFoundry {
+		entry, err := c.exportService.ExportFoundryJournal(ctx.Request().Context(), world)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export world").SetInternal(err)
+		}
+
+		ctx.Response().Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="%s-foundry.json"`, fileName))
+		return ctx.JSON(http.StatusOK, entry)
+	}
+
+	vault, err := c.exportService.ExportObsidianVault(ctx.Request().Context(), world)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export world").SetInternal(err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="%s-obsidian.zip"`, fileName))
+	return ctx.Blob(http.StatusOK, "application/zip", vault)
+}
+
+// @Tags Export
+// @Summary Exports search results
+// @Description Renders the dossier of every world matching the search, like the export of a single world, and packs them in a zip file.
+// @Description The csv and parquet formats instead stream a dataset for analysis, with a row per world where every list is a JSON array,
+// @Description or with arrays=explode a row per item of the lists of a world, naming the list and the rarity of the item.
+// @Description Datasets hold up to 10000 worlds, 1000 by default.
+// @Description The export is charged to the rate limit as one request per world requested, or per hundred worlds of a dataset.
+// @Produce application/zip
+// @Produce text/csv
+// @Produce application/vnd.apache.parquet
+// @Param query query string false "Search query (name/description)"
+// @Param theme query string false "Filter by theme"
+// @Param climate query string false "Filter by climate"
+// @Param limit query int false "Limit results" default(10)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Param format query string false "Document or dataset format" Enums(md,html,pdf,csv,parquet) default(md)
+// @Param arrays query string false "Layout of the lists of the worlds in a dataset" Enums(json,explode) default(json)
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/worlds/export [get]
+func (c *ExportController) ExportWorlds(ctx echo.Context) error {
+	format := dossierFormat(ctx)
+	if format == services.DatasetFormatCSV || format == services.DatasetFormatParquet {
+		return c.exportDataset(ctx, format)
+	}
+	if err := services.ValidateDossierFormat(format); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown format %q, expected one of %s",
+			format, strings.Join(slices.Concat(services.DossierFormats, services.DatasetFormats), ", ")))
+	}
+
+	limit := parseLimitParam(ctx.QueryParam("limit"))
+	offset := parseOffsetParam(ctx.QueryParam("offset"))
+
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, limit); err == nil && exceeded {
+		return middlewares.ErrRateLimitExceeded
+	}
+
+	worlds, _, err := c.worldService.SearchWorlds(
+		ctx.Request().Context(),
+		ctx.QueryParam("query"),
+		ctx.QueryParam("theme"),
+		ctx.QueryParam("climate"),
+		limit,
+		offset,
+	)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search worlds").SetInternal(err)
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="worlds-%s.zip"`, time.Now().Format(time.DateOnly)))
+	res.WriteHeader(http.StatusOK)
+
+	// The archive is streamed, so a failure midway can only cut it short
+	if err := c.exportService.WriteArchive(ctx.Request().Context(), res, worlds, format); err != nil {
+		log.Printf("Error exporting worlds: %v", err)
+	}
+	return nil
+}
+
+// exportDataset streams the worlds matching the search as a CSV or Parquet dataset
+func (c *ExportController) exportDataset(ctx echo.Context, format string) error {
+	arrays := strings.ToLower(ctx.QueryParam("arrays"))
+	if arrays == "" {
+		arrays = services.DatasetArraysJSON
+	}
+	if err := services.ValidateDataset(format, arrays); err != nil {
+		return err
+	}
+
+	limit := parseDatasetLimitParam(ctx.QueryParam("limit"))
+	offset := parseOffsetParam(ctx.QueryParam("offset"))
+
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, (limit+99)/100); err == nil && exceeded {
+		return middlewares.ErrRateLimitExceeded
+	}
+
+	res := ctx.Response()
+	var dataset *services.DatasetWriter
+	startDataset := func() error {
+		res.Header().Set(echo.HeaderContentType, services.DatasetContentType(format))
+		res.Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="worlds-%s.%s"`, time.Now().Format(time.DateOnly), format))
+		res.WriteHeader(http.StatusOK)
+
+		var err error
+		dataset, err = services.NewDatasetWriter(res, format, arrays)
+		return err
+	}
+
+	// The response starts with the first world, so that a search that cannot run still answers an error
+	err := c.worldService.EachSearchedWorld(
+		ctx.Request().Context(),
+		ctx.QueryParam("query"),
+		ctx.QueryParam("theme"),
+		ctx.QueryParam("climate"),
+		limit,
+		offset,
+		func(w *models.World) error {
+			if !res.Committed {
+				if err := startDataset(); err != nil {
+					return err
+				}
+			}
+			return dataset.Write(w)
+		},
+	)
+	if !res.Committed {
+		if err != nil {
+			return err
+		}
+		// No world matches, so the dataset has no rows
+		if err := startDataset(); err != nil {
+			log.Printf("Error exporting dataset: %v", err)
+			return nil
+		}
+	}
+
+	// The dataset is streamed, so a failure midway can only cut it short
+	if err == nil && dataset != nil {
+		err = dataset.Close()
+	}
+	if err != nil {
+		log.Printf("Error exporting dataset: %v", err)
+	}
+	return nil
+}
+
+// dossierFormat returns the format query parameter, Markdown by default
+func dossierFormat(ctx echo.Context) string {
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		return services.DossierFormatMarkdown
+	}
+	return format
+}
+
+// parseDatasetLimitParam parses the limit parameter of a dataset, which allows more worlds than a page
+func parseDatasetLimitParam(limitStr string) int {
+	const defaultLimit = 1000
+	const maxLimit = 10000
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
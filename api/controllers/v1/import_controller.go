@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// Largest import document accepted
+const maxImportBodySize = 32 << 20
+
+// Media types of the import documents, by format
+var importMediaTypes = map[string]string{
+	echo.MIMEApplicationJSON: services.ImportFormatJSON,
+	"application/x-ndjson":   services.ImportFormatNDJSON,
+	"application/jsonl":      services.ImportFormatNDJSON,
+	"application/yaml":       services.ImportFormatYAML,
+	"application/x-yaml":     services.ImportFormatYAML,
+	"text/yaml":              services.ImportFormatYAML,
+}
+
+// ImportController manages the import of worlds for API v1
+type ImportController struct {
+	worldService *services.WorldService
+}
+
+// NewImportController creates a new instance of the controller
+func NewImportController(worldService *services.WorldService) *ImportController {
+	return &ImportController{
+		worldService: worldService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *ImportController) RegisterRoutes(g *echo.Group) {
+	g.POST("/worlds/import", c.ImportWorlds)
+}
+
+// @Tags World
+// @Summary Imports worlds
+// @Description Stores worlds read from a JSON, NDJSON or YAML document, such as the output of GET /v1/world/{id}, GET /v1/worlds or a batch.
+// @Description A JSON or YAML document holds a world, a list of worlds or a page of search results; YAML may hold several documents.
+// @Description The format comes from the Content-Type header unless the format parameter is given.
+// @Description Every world is validated and reported on its own. Atomic imports store every world or none, answering 422 when some world fails.
+// @Description New IDs are assigned unless ids=preserve, which keeps the IDs and star systems of the worlds and fails the worlds whose ID is taken.
+// @Description Preserving IDs requires the admin token.
+// @Security AdminToken
+// @Description The import is charged to the rate limit as one request per world.
+// @Accept json
+// @Accept application/x-ndjson
+// @Accept application/yaml
+// @Produce json
+// @Param request body []models.World true "Worlds to import"
+// @Param format query string false "Format of the document, overriding the Content-Type header" Enums(json,ndjson,yaml)
+// @Param atomic query bool false "Store every world or none" default(false)
+// @Param ids query string false "Keep the IDs of the worlds or assign new ones" Enums(remap,preserve) default(remap)
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string "ids=preserve without the admin token"
+// @Failure 403 {object} map[string]string "ids=preserve on a server without an admin token"
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} models.ImportResult "No world was imported"
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/worlds/import [post]
+func (c *ImportController) ImportWorlds(ctx echo.Context) error {
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		mediaType, _, _ := strings.Cut(ctx.Request().Header.Get(echo.HeaderContentType), ";")
+		var ok bool
+		if format, ok = importMediaTypes[strings.TrimSpace(strings.ToLower(mediaType))]; !ok {
+			return ctx.JSON(http.StatusUnsupportedMediaType, map[string]string{
+				"error": "Content-Type must be application/json, application/x-ndjson or application/yaml",
+			})
+		}
+	}
+
+	var opts services.ImportOptions
+	switch ids := ctx.QueryParam("ids"); ids {
+	case "", "remap":
+	case "preserve":
+		opts.PreserveIDs = true
+	default:
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "ids must be remap or preserve",
+		})
+	}
+	if atomic := ctx.QueryParam("atomic"); atomic != "" {
+		var err error
+		if opts.Atomic, err = strconv.ParseBool(atomic); err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "atomic must be true or false",
+			})
+		}
+	}
+
+	body := http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxImportBodySize)
+	records, err := services.DecodeImport(format, body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return ctx.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": "The document is too large",
+			})
+		}
+		return err
+	}
+
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, len(records)); err == nil && exceeded {
+		return middlewares.ErrRateLimitExceeded
+	}
+
+	result, err := c.worldService.ImportWorlds(ctx.Request().Context(), records, opts)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import worlds").SetInternal(err)
+	}
+
+	status := http.StatusOK
+	if result.Imported == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	return ctx.JSON(status, result)
+}
@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/middlewares"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// JobController manages requests related to background jobs for API v1
+type JobController struct {
+	jobService *services.JobService
+}
+
+// NewJobController creates a new instance of the controller
+func NewJobController(jobService *services.JobService) *JobController {
+	return &JobController{
+		jobService: jobService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *JobController) RegisterRoutes(g *echo.Group) {
+	g.POST("/jobs", c.CreateJob)
+	g.GET("/jobs/:id", c.GetJob)
+	g.DELETE("/jobs/:id", c.CancelJob)
+}
+
+// @Tags Job
+// @Summary Enqueues a background job
+// @Description Queues an expensive generation and returns right away with the job ID to poll.
+// @Description Types: world (params: theme, seed), batch (params: a batch request, charged to the rate limit as count requests),
+// @Description economy (params: world_id, turns) and map (params: world_id, location_id, format as ascii or svg).
+// @Description Failed jobs are retried with exponential backoff up to max_attempts; a retried batch keeps the worlds it already saved.
+// @Accept json
+// @Produce json
+// @Param request body models.CreateJobRequest true "Job type and parameters"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/jobs [post]
+func (c *JobController) CreateJob(ctx echo.Context) error {
+	var req models.CreateJobRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	weight, err := c.jobService.ValidateJobRequest(req)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if exceeded, err := middlewares.ChargeRateLimit(ctx, weight); err == nil && exceeded {
+		return middlewares.ErrRateLimitExceeded
+	}
+
+	job, err := c.jobService.CreateJob(ctx.Request().Context(), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to enqueue job").SetInternal(err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderLocation, "/v1/jobs/"+job.ID)
+	return ctx.JSON(http.StatusAccepted, job)
+}
+
+// @Tags Job
+// @Summary Gets a background job
+// @Description Retrieves the status, progress and, once it has succeeded, the result of a job.
+// @Description Jobs are kept for 24 hours after their last update.
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/jobs/{id} [get]
+func (c *JobController) GetJob(ctx echo.Context) error {
+	job, err := c.jobService.GetJob(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, job)
+}
+
+// @Tags Job
+// @Summary Cancels a background job
+// @Description Cancels a queued, retrying or running job
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/jobs/{id} [delete]
+func (c *JobController) CancelJob(ctx echo.Context) error {
+	job, err := c.jobService.CancelJob(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, job)
+}
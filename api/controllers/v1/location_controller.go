@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// LocationController manages requests related to the points of interest of worlds for API v1
+type LocationController struct {
+	worldService    *services.WorldService
+	locationService *services.LocationService
+}
+
+// NewLocationController creates a new instance of the controller
+func NewLocationController(worldService *services.WorldService, locationService *services.LocationService) *LocationController {
+	return &LocationController{
+		worldService:    worldService,
+		locationService: locationService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *LocationController) RegisterRoutes(g *echo.Group) {
+	g.POST("/world/:id/locations", c.GenerateLocation)
+	g.GET("/world/:id/locations", c.GetLocations)
+	g.GET("/world/:id/locations/:location_id", c.GetLocationByID)
+	g.GET("/world/:id/locations/:location_id/map", c.GetLocationMap)
+}
+
+// @Tags Location
+// @Summary Generates a point of interest of a world
+// @Description Creates a dungeon, ruin, bunker or similar location tied to one of the world's dangers and features,
+// @Description with a room layout, inhabitants drawn from the world's fauna and dangers, treasure and a short history.
+// @Description Random danger and feature of the world are used when they are not given.
+// @Accept json
+// @Produce json
+// @Param id path int true "World ID"
+// @Param request body models.CreateLocationRequest false "Danger and feature of the location"
+// @Success 201 {object} models.Location
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/world/{id}/locations [post]
+func (c *LocationController) GenerateLocation(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	var req models.CreateLocationRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	location, err := c.locationService.GenerateLocation(ctx.Request().Context(), world, req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, location)
+}
+
+// @Tags Location
+// @Summary Lists the points of interest of a world
+// @Description Retrieves every location generated for a world
+// @Produce json
+// @Param id path int true "World ID"
+// @Success 200 {array} models.Location
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/locations [get]
+func (c *LocationController) GetLocations(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	locations, err := c.locationService.GetLocations(ctx.Request().Context(), world.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve locations").SetInternal(err)
+	}
+
+	return ctx.JSON(http.StatusOK, locations)
+}
+
+// @Tags Location
+// @Summary Gets a point of interest of a world
+// @Description Retrieves a location of a world by its ID
+// @Produce json
+// @Param id path int true "World ID"
+// @Param location_id path int true "Location ID"
+// @Success 200 {object} models.Location
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/locations/{location_id} [get]
+func (c *LocationController) GetLocationByID(ctx echo.Context) error {
+	location, err := c.findLocation(ctx)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, location)
+}
+
+// @Tags Location
+// @Summary Renders the layout of a point of interest
+// @Description Draws the rooms and corridors of a location as ASCII text or as an SVG image
+// @Produce plain
+// @Produce image/svg+xml
+// @Param id path int true "World ID"
+// @Param location_id path int true "Location ID"
+// @Param format query string false "Rendering format" Enums(ascii,svg) default(svg)
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/locations/{location_id}/map [get]
+func (c *LocationController) GetLocationMap(ctx echo.Context) error {
+	location, err := c.findLocation(ctx)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(ctx.QueryParam("format"))
+	if format == "" {
+		format = services.LocationFormatSVG
+	}
+
+	rendered, err := c.locationService.RenderLocation(location, format)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	contentType := "image/svg+xml"
+	if format == services.LocationFormatASCII {
+		contentType = echo.MIMETextPlainCharsetUTF8
+	}
+	return ctx.Blob(http.StatusOK, contentType, []byte(rendered))
+}
+
+// findLocation loads the location referenced by the id and location_id path parameters
+func (c *LocationController) findLocation(ctx echo.Context) (*models.Location, error) {
+	worldID, err := handlers.PathID(ctx, "id", "world")
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := handlers.PathID(ctx, "location_id", "location")
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := c.locationService.GetLocationByID(ctx.Request().Context(), worldID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
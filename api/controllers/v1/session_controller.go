@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// SessionController manages the collaborative editing sessions of worlds for API v1
+type SessionController struct {
+	worldService   *services.WorldService
+	sessionService *services.SessionService
+}
+
+// NewSessionController creates a new instance of the controller
+func NewSessionController(worldService *services.WorldService, sessionService *services.SessionService) *SessionController {
+	return &SessionController{
+		worldService:   worldService,
+		sessionService: sessionService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *SessionController) RegisterRoutes(g *echo.Group) {
+	g.GET("/world/:id/session", c.JoinSession)
+}
+
+// @Tags Session
+// @Summary Joins the editing session of a world
+// @Description Upgrades to a WebSocket on which several users edit a world together. Messages are JSON objects with a type:
+// @Description - snapshot (sent on join and resync): the world, the description revision, the participants and the locks
+// @Description - presence: the participants and locks, sent when they change
+// @Description - edit {field, value}: sets name, population, climate or a list field; the edit with the highest stamp wins
+// @Description - text {revision, ops}: edits the description with an ot.js style operation based on a revision; the author receives an ack with the new revision and the others the transformed operation
+// @Description - lock / unlock {field}: reserves a field for 30 seconds, renewed by locking again
+// @Description - resync: asks for a new snapshot
+// @Description - error {message}
+// @Description Changes are written back to the database every 15 seconds and when the last participant leaves.
+// @Param id path int true "World ID"
+// @Param user query string true "Name shown to the other participants"
+// @Success 101 {object} models.SessionMessage "Switching Protocols"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/session [get]
+func (c *SessionController) JoinSession(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.ServeSession(ctx, c.sessionService, world)
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// SystemController manages requests related to star systems for API v1
+type SystemController struct {
+	systemService *services.SystemService
+}
+
+// NewSystemController creates a new instance of the controller
+func NewSystemController(systemService *services.SystemService) *SystemController {
+	return &SystemController{
+		systemService: systemService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *SystemController) RegisterRoutes(g *echo.Group) {
+	g.POST("/systems", c.GenerateSystem)
+	g.GET("/systems/:id", c.GetSystemByID)
+}
+
+// @Tags System
+// @Summary Generates a new star system
+// @Description Creates a sci-fi star system with its star, orbits, moons and habitable zone.
+// @Description Every habitable body gets a full world with a climate matching its orbit.
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSystemRequest false "Generation parameters"
+// @Success 201 {object} models.StarSystem
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
+// @Router /v1/systems [post]
+func (c *SystemController) GenerateSystem(ctx echo.Context) error {
+	var req models.CreateSystemRequest
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	system, err := c.systemService.GenerateSystem(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, system)
+}
+
+// @Tags System
+// @Summary Gets a specific star system by ID
+// @Description Retrieves a star system and the worlds of its habitable bodies
+// @Produce json
+// @Param id path int true "System ID"
+// @Success 200 {object} models.StarSystem
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/systems/{id} [get]
+func (c *SystemController) GetSystemByID(ctx echo.Context) error {
+	id, err := handlers.PathID(ctx, "id", "system")
+	if err != nil {
+		return err
+	}
+
+	system, err := c.systemService.GetSystemByID(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, system)
+}
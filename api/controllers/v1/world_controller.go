@@ -2,10 +2,13 @@
 package v1
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
 	"github.com/medinapdr/world-gen/models"
 	"github.com/medinapdr/world-gen/services"
 )
@@ -13,12 +16,14 @@ import (
 // WorldController manages requests related to worlds for API v1
 type WorldController struct {
 	worldService *services.WorldService
+	eventBus     *services.EventBus
 }
 
 // NewWorldController creates a new instance of the controller
-func NewWorldController(worldService *services.WorldService) *WorldController {
+func NewWorldController(worldService *services.WorldService, eventBus *services.EventBus) *WorldController {
 	return &WorldController{
 		worldService: worldService,
+		eventBus:     eventBus,
 	}
 }
 
@@ -29,8 +34,23 @@ func (c *WorldController) RegisterRoutes(g *echo.Group) {
 
 	g.GET("/world", c.GenerateWorld)
 	g.GET("/world/:id", c.GetWorldByID)
+	g.DELETE("/world/:id", c.DeleteWorld)
+	g.GET("/world/:id/religions", c.GetWorldReligions)
+	g.GET("/world/:id/economy", c.GetWorldEconomy)
+	g.GET("/world/:id/calendar", c.GetWorldCalendar)
+	g.GET("/world/:id/weather", c.GetWorldWeather)
+	g.GET("/world/:id/npcs", c.GetWorldNPCs)
+	g.GET("/world/:id/npcs/:npc_id", c.GetWorldNPC)
+	g.GET("/world/:id/hooks", c.GetWorldHooks)
+	g.GET("/world/:id/hooks/:hook_id", c.GetWorldHook)
+	g.POST("/world/:id/hooks/:hook_id/use", c.UseWorldHook)
+	g.GET("/world/:id/encounters", c.GetWorldEncounters)
+	g.POST("/world/:id/encounters/roll", c.RollWorldEncounter)
+	g.GET("/world/:id/encounters/export", c.ExportWorldEncounters)
 	g.GET("/worlds", c.SearchWorlds)
+	g.POST("/worlds/batch", c.GenerateWorldBatch)
 	g.GET("/history", c.GetHistory)
+	g.GET("/history/stream", c.StreamHistory)
 }
 
 // @Tags API
@@ -46,8 +66,37 @@ func (c *WorldController) Welcome(ctx echo.Context) error {
 		"endpoints": []map[string]string{
 			{"path": "/v1/world", "method": "GET", "description": "Generate a new random world"},
 			{"path": "/v1/world/{id}", "method": "GET", "description": "Get world by ID"},
+			{"path": "/v1/world/{id}", "method": "DELETE", "description": "Delete a world"},
+			{"path": "/v1/world/{id}/religions", "method": "GET", "description": "Get the religions of a world"},
+			{"path": "/v1/world/{id}/economy", "method": "GET", "description": "Get the resources, prices and trade routes of a world"},
+			{"path": "/v1/world/{id}/calendar", "method": "GET", "description": "Get the calendar of a world"},
+			{"path": "/v1/world/{id}/weather", "method": "GET", "description": "Get the weather of a world region on a date"},
+			{"path": "/v1/world/{id}/npcs", "method": "GET", "description": "Generate NPCs from the cultures and languages of a world"},
+			{"path": "/v1/world/{id}/npcs/{npc_id}", "method": "GET", "description": "Get an NPC of a world by ID"},
+			{"path": "/v1/world/{id}/hooks", "method": "GET", "description": "Generate adventure hooks, skipping those used in a campaign"},
+			{"path": "/v1/world/{id}/hooks/{hook_id}", "method": "GET", "description": "Get an adventure hook of a world by ID"},
+			{"path": "/v1/world/{id}/hooks/{hook_id}/use", "method": "POST", "description": "Mark an adventure hook as used in a campaign"},
+			{"path": "/v1/world/{id}/encounters", "method": "GET", "description": "Get the weighted d100 encounter tables of a world"},
+			{"path": "/v1/world/{id}/encounters/roll", "method": "POST", "description": "Roll on the encounter table of a region"},
+			{"path": "/v1/world/{id}/encounters/export", "method": "GET", "description": "Export an encounter table as a Foundry VTT RollTable"},
+			{"path": "/v1/world/{id}/session", "method": "GET", "description": "Join the collaborative editing session of a world over WebSocket"},
+			{"path": "/v1/world/{id}/locations", "method": "POST", "description": "Generate a point of interest tied to a world danger"},
+			{"path": "/v1/world/{id}/locations", "method": "GET", "description": "List the points of interest of a world"},
+			{"path": "/v1/world/{id}/locations/{location_id}", "method": "GET", "description": "Get a point of interest by ID"},
+			{"path": "/v1/world/{id}/locations/{location_id}/map", "method": "GET", "description": "Render a point of interest as ASCII or SVG"},
 			{"path": "/v1/worlds", "method": "GET", "description": "Search for worlds with filters"},
+			{"path": "/v1/worlds/batch", "method": "POST", "description": "Generate a batch of worlds, streamed as NDJSON"},
 			{"path": "/v1/history", "method": "GET", "description": "Get recently generated worlds history"},
+			{"path": "/v1/history/stream", "method": "GET", "description": "Stream newly generated worlds as Server-Sent Events"},
+			{"path": "/v1/systems", "method": "POST", "description": "Generate a sci-fi star system with its habitable worlds"},
+			{"path": "/v1/systems/{id}", "method": "GET", "description": "Get star system by ID"},
+			{"path": "/v1/jobs", "method": "POST", "description": "Enqueue a world, batch, economy or map generation job"},
+			{"path": "/v1/jobs/{id}", "method": "GET", "description": "Get the status, progress and result of a job"},
+			{"path": "/v1/jobs/{id}", "method": "DELETE", "description": "Cancel a job"},
+			{"path": "/v1/webhooks", "method": "POST", "description": "Register a webhook for world events"},
+			{"path": "/v1/webhooks", "method": "GET", "description": "List the registered webhooks"},
+			{"path": "/v1/webhooks/{id}", "method": "DELETE", "description": "Unregister a webhook"},
+			{"path": "/v1/webhooks/{id}/deliveries", "method": "GET", "description": "Get the delivery log of a webhook"},
 		},
 		"documentation": "/swagger/index.html",
 	})
@@ -56,20 +105,37 @@ func (c *WorldController) Welcome(ctx echo.Context) error {
 // @Tags World
 // @Summary Generates a new world
 // @Description Creates a world with random characteristics based on the chosen theme
+// @Description Elements are drawn from weighted pools, and the rarity tier of each one is reported in rarities.
 // @Produce json
 // @Param theme query string false "World theme" Enums(fantasy,sci-fi,post-apocalyptic) default(fantasy)
+// @Param seed query int false "Seed making generation reproducible; the seed used is returned with the world"
+// @Param diagnostics query bool false "Include the report of the coherence rules run after generation"
 // @Success 200 {object} models.World
+// @Failure 400 {object} map[string]string
 // @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "Service Unavailable"
 // @Router /v1/world [get]
 func (c *WorldController) GenerateWorld(ctx echo.Context) error {
 	theme := ctx.QueryParam("theme")
 
-	world, err := c.worldService.GenerateWorld(ctx.Request().Context(), theme)
+	var opts []services.GenerateOption
+	if seedStr := ctx.QueryParam("seed"); seedStr != "" {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid seed",
+			})
+		}
+		opts = append(opts, services.WithSeed(seed))
+	}
+	if ctx.QueryParam("diagnostics") == "true" {
+		opts = append(opts, services.WithDiagnostics())
+	}
+
+	world, err := c.worldService.GenerateWorld(ctx.Request().Context(), theme, opts...)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to generate world",
-		})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate world").SetInternal(err)
 	}
 
 	return ctx.JSON(http.StatusOK, world)
@@ -77,39 +143,369 @@ func (c *WorldController) GenerateWorld(ctx echo.Context) error {
 
 // @Tags World
 // @Summary Gets a specific world by ID
-// @Description Retrieves a world from the database by its ID
+// @Description Retrieves a world from the database by its ID.
+// @Description The representation is negotiated with the Accept header: JSON by default, or YAML, NDJSON, XML and MessagePack
+// @Description with the field names of JSON, or the Markdown dossier of the world without its locations.
 // @Produce json
+// @Produce application/yaml
+// @Produce application/x-ndjson
+// @Produce text/markdown
+// @Produce application/xml
+// @Produce application/msgpack
 // @Param id path int true "World ID"
+// @Param diagnostics query bool false "Include the report of the coherence rules, checked without repairing the world"
 // @Success 200 {object} models.World
 // @Failure 404 {object} map[string]string
+// @Failure 406 {object} map[string]interface{} "None of the accepted media types is available, listed in available"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /v1/world/{id} [get]
 func (c *WorldController) GetWorldByID(ctx echo.Context) error {
-	id, err := parseID(ctx.Param("id"))
+	encoder, ok := negotiateEncoder(ctx)
+	if !ok {
+		return notAcceptable(ctx)
+	}
+
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	if ctx.QueryParam("diagnostics") == "true" {
+		world.Diagnostics = c.worldService.CheckWorld(world)
+	}
+
+	return respond(ctx, encoder, http.StatusOK, world)
+}
+
+// @Tags World
+// @Summary Deletes a world
+// @Description Removes a world along with its points of interest and notifies the webhooks subscribed to world.deleted.
+// @Description Requires the admin token, and is disabled when the server has none.
+// @Security AdminToken
+// @Param id path int true "World ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id} [delete]
+func (c *WorldController) DeleteWorld(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	if err := c.worldService.DeleteWorld(ctx.Request().Context(), world.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete world").SetInternal(err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Tags World
+// @Summary Gets the religions of a world
+// @Description Retrieves the deities and belief systems generated for a world's cultures
+// @Produce json
+// @Param id path int true "World ID"
+// @Success 200 {array} models.Religion
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/religions [get]
+func (c *WorldController) GetWorldReligions(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.Religions(ctx, world)
+}
+
+// @Tags World
+// @Summary Gets the economy of a world
+// @Description Computes the resource map, production per settlement, market prices and trade routes of a world.
+// @Description When turns is set, the market is simulated for that many turns and price shocks are reported.
+// @Produce json
+// @Param id path int true "World ID"
+// @Param turns query int false "Number of market simulation turns" default(0) maximum(100)
+// @Success 200 {object} models.Economy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/economy [get]
+func (c *WorldController) GetWorldEconomy(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	turns, err := parseTurnsParam(ctx.QueryParam("turns"))
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid world ID",
+			"error": "Invalid number of turns",
 		})
 	}
 
-	world, err := c.worldService.GetWorldByID(ctx.Request().Context(), id)
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateEconomy(world, turns))
+}
+
+// @Tags World
+// @Summary Gets the calendar of a world
+// @Description Retrieves the day and year length, the months named in the world's language and its festivals
+// @Produce json
+// @Param id path int true "World ID"
+// @Success 200 {object} models.Calendar
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/calendar [get]
+func (c *WorldController) GetWorldCalendar(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateCalendar(world))
+}
+
+// @Tags World
+// @Summary Gets the weather of a world
+// @Description Returns the temperature, precipitation and weather events of a region on a date of the world calendar
+// @Produce json
+// @Param id path int true "World ID"
+// @Param date query string false "Date in the world calendar, as year-month-day" default(1-1-1)
+// @Param region query string false "Region or settlement name, defaults to the first region"
+// @Success 200 {object} models.Weather
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/weather [get]
+func (c *WorldController) GetWorldWeather(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "world not found" {
-			status = http.StatusNotFound
-		}
-		return ctx.JSON(status, map[string]string{
-			"error": err.Error(),
+		return err
+	}
+
+	return handlers.Weather(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Generates NPCs of a world
+// @Description Generates characters named in the world's languages, with a culture, an occupation fitting the climate
+// @Description and theme, personality traits and motivations. NPCs are numbered and always the same for a given ID.
+// @Produce json
+// @Param id path int true "World ID"
+// @Param count query int false "Number of NPCs" default(5) maximum(50)
+// @Param culture query string false "Only generate NPCs of this culture"
+// @Success 200 {array} models.NPC
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/npcs [get]
+func (c *WorldController) GetWorldNPCs(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	count, err := parseCountParam(ctx.QueryParam("count"), services.MaxNPCCount)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid count",
 		})
 	}
 
-	return ctx.JSON(http.StatusOK, world)
+	return handlers.NPCs(ctx, c.worldService, world, count)
+}
+
+// @Tags World
+// @Summary Gets an NPC of a world by ID
+// @Description Regenerates the NPC of a world with the given ID
+// @Produce json
+// @Param id path int true "World ID"
+// @Param npc_id path int true "NPC ID"
+// @Success 200 {object} models.NPC
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/npcs/{npc_id} [get]
+func (c *WorldController) GetWorldNPC(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	npcID, err := handlers.PathID(ctx, "npc_id", "NPC")
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateNPC(world, npcID))
+}
+
+// @Tags World
+// @Summary Generates adventure hooks of a world
+// @Description Combines the world's dangers, cultures, fauna and features into adventure seeds with a patron, objective,
+// @Description complication, location, reward and difficulty tier. Hooks are numbered and always the same for a given ID.
+// @Description When a campaign is given, hooks already used in it are skipped.
+// @Produce json
+// @Param id path int true "World ID"
+// @Param count query int false "Number of hooks" default(5) maximum(20)
+// @Param tier query int false "Only generate hooks of this difficulty tier" Enums(1,2,3,4)
+// @Param campaign query string false "Skip the hooks already used in this campaign"
+// @Success 200 {array} models.AdventureHook
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/hooks [get]
+func (c *WorldController) GetWorldHooks(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	count, err := parseCountParam(ctx.QueryParam("count"), services.MaxHookCount)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid count",
+		})
+	}
+
+	tier := 0
+	if tierStr := ctx.QueryParam("tier"); tierStr != "" {
+		if tier, err = strconv.Atoi(tierStr); err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid tier",
+			})
+		}
+	}
+
+	return handlers.Hooks(ctx, c.worldService, world, count, tier)
+}
+
+// @Tags World
+// @Summary Gets an adventure hook of a world by ID
+// @Description Regenerates the adventure hook of a world with the given ID
+// @Produce json
+// @Param id path int true "World ID"
+// @Param hook_id path int true "Hook ID"
+// @Success 200 {object} models.AdventureHook
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/hooks/{hook_id} [get]
+func (c *WorldController) GetWorldHook(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	hookID, err := handlers.PathID(ctx, "hook_id", "hook")
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateHook(world, hookID))
+}
+
+// @Tags World
+// @Summary Marks an adventure hook as used
+// @Description Records that a hook was used in a campaign, so it is no longer offered for that campaign
+// @Accept json
+// @Produce json
+// @Param id path int true "World ID"
+// @Param hook_id path int true "Hook ID"
+// @Param request body models.UseHookRequest true "Campaign using the hook"
+// @Success 201 {object} models.HookUsage
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/hooks/{hook_id}/use [post]
+func (c *WorldController) UseWorldHook(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.UseHook(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Gets the encounter tables of a world
+// @Description Builds weighted d100 encounter tables from the world's fauna, cultures and dangers, one per region of its map.
+// @Description Weights depend on the climate, the theme and the terrain of the region.
+// @Produce json
+// @Param id path int true "World ID"
+// @Param region query string false "Only return the table of this region or settlement"
+// @Success 200 {array} models.EncounterTable
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/encounters [get]
+func (c *WorldController) GetWorldEncounters(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.EncounterTables(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Rolls a random encounter
+// @Description Rolls on the encounter table of a region, defaulting to the first region of the world.
+// @Description The seed of the roll is returned, and sending it back repeats the same roll.
+// @Accept json
+// @Produce json
+// @Param id path int true "World ID"
+// @Param request body models.RollEncounterRequest false "Region and seed of the roll"
+// @Success 200 {object} models.EncounterRoll
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/encounters/roll [post]
+func (c *WorldController) RollWorldEncounter(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.RollEncounter(ctx, c.worldService, world, http.StatusOK)
+}
+
+// @Tags World
+// @Summary Exports an encounter table for virtual tabletops
+// @Description Converts the encounter table of a region, defaulting to the first region, to the RollTable JSON format
+// @Description that Foundry VTT imports
+// @Produce json
+// @Param id path int true "World ID"
+// @Param region query string false "Region or settlement name"
+// @Success 200 {object} models.FoundryRollTable
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/world/{id}/encounters/export [get]
+func (c *WorldController) ExportWorldEncounters(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.EncounterRollTable(ctx, c.worldService, world)
 }
 
 // @Tags World
 // @Summary Search for worlds
-// @Description Search for worlds based on various criteria
+// @Description Search for worlds based on various criteria.
+// @Description The representation is negotiated with the Accept header like a single world; NDJSON has a line per world of the page
+// @Description and Markdown the dossier of every world of the page.
 // @Produce json
+// @Produce application/yaml
+// @Produce application/x-ndjson
+// @Produce text/markdown
+// @Produce application/xml
+// @Produce application/msgpack
 // @Param query query string false "Search query (name/description)"
 // @Param theme query string false "Filter by theme"
 // @Param climate query string false "Filter by climate"
@@ -117,9 +513,15 @@ func (c *WorldController) GetWorldByID(ctx echo.Context) error {
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {object} models.PaginatedWorldsResponse
 // @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]interface{} "None of the accepted media types is available, listed in available"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /v1/worlds [get]
 func (c *WorldController) SearchWorlds(ctx echo.Context) error {
+	encoder, ok := negotiateEncoder(ctx)
+	if !ok {
+		return notAcceptable(ctx)
+	}
+
 	query := ctx.QueryParam("query")
 	theme := ctx.QueryParam("theme")
 	climate := ctx.QueryParam("climate")
@@ -137,9 +539,7 @@ func (c *WorldController) SearchWorlds(ctx echo.Context) error {
 	)
 
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to search worlds",
-		})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search worlds").SetInternal(err)
 	}
 
 	response := models.PaginatedWorldsResponse{
@@ -149,7 +549,22 @@ func (c *WorldController) SearchWorlds(ctx echo.Context) error {
 		Total:  total,
 	}
 
-	return ctx.JSON(http.StatusOK, response)
+	return respond(ctx, encoder, http.StatusOK, response)
+}
+
+// @Tags World
+// @Summary Generates a batch of worlds
+// @Description Generates count worlds concurrently following a theme mix and constraints, streaming each one as a line of NDJSON once saved.
+// @Description The batch is charged to the rate limit as count requests. A line with an error field ends the stream if generation fails midway.
+// @Accept json
+// @Produce application/x-ndjson
+// @Param request body models.BatchGenerateRequest true "Batch size, theme weights and constraints"
+// @Success 200 {object} models.World "One world per line"
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /v1/worlds/batch [post]
+func (c *WorldController) GenerateWorldBatch(ctx echo.Context) error {
+	return handlers.GenerateBatch(ctx, c.worldService)
 }
 
 // @Tags World
@@ -160,21 +575,50 @@ func (c *WorldController) SearchWorlds(ctx echo.Context) error {
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /v1/history [get]
 func (c *WorldController) GetHistory(ctx echo.Context) error {
-	worlds, err := c.worldService.GetWorldHistory(ctx.Request().Context())
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve history",
-		})
-	}
+	return handlers.History(ctx, c.worldService)
+}
 
-	return ctx.JSON(http.StatusOK, worlds)
+// @Tags World
+// @Summary Streams newly generated worlds
+// @Description Pushes every newly generated world as a Server-Sent Event named world.generated, whose data is the world.
+// @Description Reconnecting clients send the Last-Event-ID header (or the last_event_id query parameter) to receive the worlds they missed.
+// @Produce text/event-stream
+// @Param theme query string false "Only stream worlds of this theme"
+// @Param climate query string false "Only stream worlds of this climate"
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Param last_event_id query int false "ID of the last event received, for clients unable to set headers"
+// @Success 200 {object} models.World "One event per world"
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/history/stream [get]
+func (c *WorldController) StreamHistory(ctx echo.Context) error {
+	return handlers.StreamHistory(ctx, c.eventBus)
 }
 
 // Helper functions
 
-// parseID converts ID parameter string to int
-func parseID(idParam string) (int, error) {
-	return strconv.Atoi(idParam)
+// negotiateEncoder picks the encoder of the representation the Accept header prefers,
+// reporting false when none of the accepted media types is available
+func negotiateEncoder(ctx echo.Context) (services.Encoder, bool) {
+	ctx.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	return services.NegotiateEncoder(ctx.Request().Header.Get(echo.HeaderAccept))
+}
+
+// notAcceptable answers a request accepting none of the available media types, listing them
+func notAcceptable(ctx echo.Context) error {
+	return ctx.JSON(http.StatusNotAcceptable, map[string]any{
+		"error":     "None of the accepted media types is available",
+		"available": services.EncoderMediaTypes(),
+	})
+}
+
+// respond sends a resource encoded with the negotiated encoder
+func respond(ctx echo.Context, encoder services.Encoder, status int, v any) error {
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, v); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to encode response").SetInternal(err)
+	}
+	return ctx.Blob(status, encoder.ContentType(), buf.Bytes())
 }
 
 // parseLimitParam parses and validates the limit parameter
@@ -211,3 +655,41 @@ func parseOffsetParam(offsetStr string) int {
 
 	return offset
 }
+
+// parseTurnsParam parses the number of simulation turns, capped at the service maximum
+func parseTurnsParam(turnsStr string) (int, error) {
+	if turnsStr == "" {
+		return 0, nil
+	}
+
+	turns, err := strconv.Atoi(turnsStr)
+	if err != nil || turns < 0 {
+		return 0, fmt.Errorf("invalid turns %q", turnsStr)
+	}
+
+	if turns > services.MaxEconomyTurns {
+		return services.MaxEconomyTurns, nil
+	}
+
+	return turns, nil
+}
+
+// parseCountParam parses the number of items to generate, capped at maxCount
+func parseCountParam(countStr string, maxCount int) (int, error) {
+	const defaultCount = 5
+
+	if countStr == "" {
+		return defaultCount, nil
+	}
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid count %q", countStr)
+	}
+
+	if count > maxCount {
+		return maxCount, nil
+	}
+
+	return count, nil
+}
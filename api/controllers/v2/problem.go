@@ -0,0 +1,33 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 7807 problem details
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problem sends an RFC 7807 problem details response
+func problem(ctx echo.Context, status int, detail string) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return ctx.JSON(status, models.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: ctx.Request().URL.Path,
+	})
+}
+
+// WriteProblem sends an RFC 7807 problem details response, for the central error handler
+func WriteProblem(ctx echo.Context, status int, detail string) error {
+	return problem(ctx, status, detail)
+}
+
+// internalError describes an unexpected error with detail, keeping the cause for the central error handler
+func internalError(err error, detail string) error {
+	return echo.NewHTTPError(http.StatusInternalServerError, detail).SetInternal(err)
+}
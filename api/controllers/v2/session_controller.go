@@ -0,0 +1,48 @@
+package v2
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// SessionController manages the collaborative editing sessions of worlds for API v2
+type SessionController struct {
+	worldService   *services.WorldService
+	sessionService *services.SessionService
+}
+
+// NewSessionController creates a new instance of the controller
+func NewSessionController(worldService *services.WorldService, sessionService *services.SessionService) *SessionController {
+	return &SessionController{
+		worldService:   worldService,
+		sessionService: sessionService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *SessionController) RegisterRoutes(g *echo.Group) {
+	g.GET("/worlds/:id/session", c.JoinSession)
+}
+
+// @Tags Session
+// @Summary Joins the editing session of a world
+// @Description Upgrades to a WebSocket on which several users edit a world together, with the messages of the v1 session:
+// @Description snapshot, presence, edit, text, ack, lock, unlock, resync and error.
+// @Description Changes are written back to the database every 15 seconds and when the last participant leaves.
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param user query string true "Name shown to the other participants"
+// @Success 101 {object} models.SessionMessage "Switching Protocols"
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/session [get]
+func (c *SessionController) JoinSession(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.ServeSession(ctx, c.sessionService, world)
+}
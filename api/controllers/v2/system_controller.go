@@ -0,0 +1,87 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// SystemController manages requests related to star systems for API v2
+type SystemController struct {
+	systemService *services.SystemService
+}
+
+// NewSystemController creates a new instance of the controller
+func NewSystemController(systemService *services.SystemService) *SystemController {
+	return &SystemController{
+		systemService: systemService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *SystemController) RegisterRoutes(g *echo.Group) {
+	g.POST("/systems", c.CreateSystem)
+	g.GET("/systems/:id", c.GetSystem)
+}
+
+// @Tags System
+// @Summary Generates a new star system
+// @Description Creates a sci-fi star system with its star, orbits, moons and habitable zone.
+// @Description Every habitable body gets a full world with a climate matching its orbit.
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param request body models.CreateSystemRequest false "Generation parameters"
+// @Success 201 {object} models.StarSystem
+// @Header 201 {string} Location "URL of the new star system"
+// @Failure 400 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/systems [post]
+func (c *SystemController) CreateSystem(ctx echo.Context) error {
+	var req models.CreateSystemRequest
+	if err := ctx.Bind(&req); err != nil {
+		return problem(ctx, http.StatusBadRequest, "Invalid request body")
+	}
+
+	system, err := c.systemService.GenerateSystem(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	if system.ID != 0 {
+		ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/v2/systems/%d", system.ID))
+	}
+	return ctx.JSON(http.StatusCreated, system)
+}
+
+// @Tags System
+// @Summary Gets a specific star system by ID
+// @Description Retrieves a star system and the worlds of its habitable bodies
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "System ID"
+// @Success 200 {object} models.StarSystem
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/systems/{id} [get]
+func (c *SystemController) GetSystem(ctx echo.Context) error {
+	id, err := handlers.PathID(ctx, "id", "system")
+	if err != nil {
+		return err
+	}
+
+	system, err := c.systemService.GetSystemByID(ctx.Request().Context(), id)
+	if err != nil {
+		return internalError(err, "Failed to retrieve system")
+	}
+
+	return ctx.JSON(http.StatusOK, system)
+}
@@ -0,0 +1,130 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// WebhookController manages requests related to webhooks for API v2
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new instance of the controller
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *WebhookController) RegisterRoutes(g *echo.Group) {
+	g.POST("/webhooks", c.CreateWebhook)
+	g.GET("/webhooks", c.ListWebhooks)
+	g.DELETE("/webhooks/:id", c.DeleteWebhook)
+	g.GET("/webhooks/:id/deliveries", c.ListDeliveries)
+}
+
+// @Tags Webhook
+// @Summary Registers a webhook
+// @Description Subscribes a URL to world.generated, world.updated, world.deleted and world.imported events. Each event is POSTed as JSON with the headers
+// @Description X-WorldGen-Event, X-WorldGen-Delivery, X-WorldGen-Timestamp and X-WorldGen-Signature, which holds "sha256=" followed by
+// @Description the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret. Failed deliveries are retried 5 times with exponential
+// @Description backoff starting at 10 seconds, then kept in a dead-letter list. The secret is only returned in this response.
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param request body models.CreateWebhookRequest true "URL and events, all events when empty"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx echo.Context) error {
+	var req models.CreateWebhookRequest
+	if err := ctx.Bind(&req); err != nil {
+		return problem(ctx, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.webhookService.ValidateWebhookRequest(ctx.Request().Context(), &req); err != nil {
+		return problem(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	webhook, err := c.webhookService.CreateWebhook(ctx.Request().Context(), req)
+	if err != nil {
+		return internalError(err, "Failed to register webhook")
+	}
+
+	return ctx.JSON(http.StatusCreated, webhook)
+}
+
+// @Tags Webhook
+// @Summary Lists the webhooks
+// @Description Retrieves the registered webhooks, without their secrets.
+// @Description Requires the admin token, and is disabled when the server has none.
+// @Security AdminToken
+// @Produce json
+// @Produce application/problem+json
+// @Success 200 {array} models.Webhook
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/webhooks [get]
+func (c *WebhookController) ListWebhooks(ctx echo.Context) error {
+	webhooks, err := c.webhookService.GetWebhooks(ctx.Request().Context())
+	if err != nil {
+		return internalError(err, "Failed to retrieve webhooks")
+	}
+
+	return ctx.JSON(http.StatusOK, webhooks)
+}
+
+// @Tags Webhook
+// @Summary Unregisters a webhook
+// @Description Deletes a webhook; its pending deliveries are dropped.
+// @Description Requires the admin token, and is disabled when the server has none.
+// @Security AdminToken
+// @Produce application/problem+json
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx echo.Context) error {
+	if err := c.webhookService.DeleteWebhook(ctx.Request().Context(), ctx.Param("id")); err != nil {
+		return internalError(err, "Failed to unregister webhook")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Tags Webhook
+// @Summary Gets the delivery log of a webhook
+// @Description Retrieves the latest 100 delivery attempts of a webhook, most recent first.
+// @Description Requires the admin token, and is disabled when the server has none.
+// @Security AdminToken
+// @Produce json
+// @Produce application/problem+json
+// @Param id path string true "Webhook ID"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListDeliveries(ctx echo.Context) error {
+	deliveries, err := c.webhookService.GetDeliveries(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return internalError(err, "Failed to retrieve deliveries")
+	}
+
+	return ctx.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,608 @@
+// package v2 contains version 2 API controllers, which name resources with plural nouns
+// and report errors as RFC 7807 problem details
+package v2
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/medinapdr/world-gen/controllers/handlers"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/medinapdr/world-gen/services"
+)
+
+// WorldController manages requests related to worlds for API v2
+type WorldController struct {
+	worldService *services.WorldService
+	eventBus     *services.EventBus
+}
+
+// NewWorldController creates a new instance of the controller
+func NewWorldController(worldService *services.WorldService, eventBus *services.EventBus) *WorldController {
+	return &WorldController{
+		worldService: worldService,
+		eventBus:     eventBus,
+	}
+}
+
+// RegisterRoutes registers the controller routes in Echo
+func (c *WorldController) RegisterRoutes(g *echo.Group) {
+	g.GET("", c.Welcome)
+
+	g.POST("/worlds", c.CreateWorld)
+	g.GET("/worlds", c.ListWorlds)
+	g.POST("/worlds/batches", c.CreateWorldBatch)
+	g.GET("/worlds/:id", c.GetWorld)
+	g.DELETE("/worlds/:id", c.DeleteWorld)
+	g.GET("/worlds/:id/religions", c.GetWorldReligions)
+	g.GET("/worlds/:id/economy", c.GetWorldEconomy)
+	g.GET("/worlds/:id/calendar", c.GetWorldCalendar)
+	g.GET("/worlds/:id/weather", c.GetWorldWeather)
+	g.GET("/worlds/:id/npcs", c.ListWorldNPCs)
+	g.GET("/worlds/:id/npcs/:npc_id", c.GetWorldNPC)
+	g.GET("/worlds/:id/hooks", c.ListWorldHooks)
+	g.GET("/worlds/:id/hooks/:hook_id", c.GetWorldHook)
+	g.POST("/worlds/:id/hooks/:hook_id/usages", c.CreateHookUsage)
+	g.GET("/worlds/:id/encounters", c.ListWorldEncounters)
+	g.POST("/worlds/:id/encounters/rolls", c.CreateEncounterRoll)
+	g.GET("/worlds/:id/encounters/roll-table", c.GetEncounterRollTable)
+	g.GET("/history", c.GetHistory)
+	g.GET("/history/stream", c.StreamHistory)
+}
+
+// @Tags API
+// @Summary API v2 welcome page
+// @Description Provides information about the API v2 endpoints
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v2 [get]
+func (c *WorldController) Welcome(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"api":     "World Generator API",
+		"version": "v2",
+		"endpoints": []map[string]string{
+			{"path": "/v2/worlds", "method": "POST", "description": "Generate a new world"},
+			{"path": "/v2/worlds", "method": "GET", "description": "Search for worlds with filters"},
+			{"path": "/v2/worlds/batches", "method": "POST", "description": "Generate a batch of worlds, streamed as NDJSON"},
+			{"path": "/v2/worlds/{id}", "method": "GET", "description": "Get world by ID"},
+			{"path": "/v2/worlds/{id}", "method": "DELETE", "description": "Delete a world"},
+			{"path": "/v2/worlds/{id}/religions", "method": "GET", "description": "Get the religions of a world"},
+			{"path": "/v2/worlds/{id}/economy", "method": "GET", "description": "Get the resources, prices and trade routes of a world"},
+			{"path": "/v2/worlds/{id}/calendar", "method": "GET", "description": "Get the calendar of a world"},
+			{"path": "/v2/worlds/{id}/weather", "method": "GET", "description": "Get the weather of a world region on a date"},
+			{"path": "/v2/worlds/{id}/npcs", "method": "GET", "description": "Generate NPCs from the cultures and languages of a world"},
+			{"path": "/v2/worlds/{id}/npcs/{npc_id}", "method": "GET", "description": "Get an NPC of a world by ID"},
+			{"path": "/v2/worlds/{id}/hooks", "method": "GET", "description": "Generate adventure hooks, skipping those used in a campaign"},
+			{"path": "/v2/worlds/{id}/hooks/{hook_id}", "method": "GET", "description": "Get an adventure hook of a world by ID"},
+			{"path": "/v2/worlds/{id}/hooks/{hook_id}/usages", "method": "POST", "description": "Record the use of an adventure hook in a campaign"},
+			{"path": "/v2/worlds/{id}/encounters", "method": "GET", "description": "Get the weighted d100 encounter tables of a world"},
+			{"path": "/v2/worlds/{id}/encounters/rolls", "method": "POST", "description": "Roll on the encounter table of a region"},
+			{"path": "/v2/worlds/{id}/encounters/roll-table", "method": "GET", "description": "Get an encounter table as a Foundry VTT RollTable"},
+			{"path": "/v2/worlds/{id}/session", "method": "GET", "description": "Join the collaborative editing session of a world over WebSocket"},
+			{"path": "/v2/worlds/{id}/locations", "method": "POST", "description": "Generate a point of interest tied to a world danger"},
+			{"path": "/v2/worlds/{id}/locations", "method": "GET", "description": "List the points of interest of a world"},
+			{"path": "/v2/worlds/{id}/locations/{location_id}", "method": "GET", "description": "Get a point of interest by ID"},
+			{"path": "/v2/worlds/{id}/locations/{location_id}/map", "method": "GET", "description": "Render a point of interest as ASCII or SVG"},
+			{"path": "/v2/history", "method": "GET", "description": "Get recently generated worlds history"},
+			{"path": "/v2/history/stream", "method": "GET", "description": "Stream newly generated worlds as Server-Sent Events"},
+			{"path": "/v2/systems", "method": "POST", "description": "Generate a sci-fi star system with its habitable worlds"},
+			{"path": "/v2/systems/{id}", "method": "GET", "description": "Get star system by ID"},
+			{"path": "/v2/jobs", "method": "POST", "description": "Enqueue a world, batch, economy or map generation job"},
+			{"path": "/v2/jobs/{id}", "method": "GET", "description": "Get the status, progress and result of a job"},
+			{"path": "/v2/jobs/{id}", "method": "DELETE", "description": "Cancel a job"},
+			{"path": "/v2/webhooks", "method": "POST", "description": "Register a webhook for world events"},
+			{"path": "/v2/webhooks", "method": "GET", "description": "List the registered webhooks"},
+			{"path": "/v2/webhooks/{id}", "method": "DELETE", "description": "Unregister a webhook"},
+			{"path": "/v2/webhooks/{id}/deliveries", "method": "GET", "description": "Get the delivery log of a webhook"},
+		},
+		"errors":        "application/problem+json (RFC 7807)",
+		"documentation": "/swagger/index.html",
+	})
+}
+
+// @Tags World
+// @Summary Generates a new world
+// @Description Creates a world with random characteristics based on the chosen theme and stores it.
+// @Description Elements are drawn from weighted pools, and the rarity tier of each one is reported in rarities.
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param request body models.GenerateWorldRequest false "Theme, climate and seed of the world"
+// @Success 201 {object} models.World
+// @Header 201 {string} Location "URL of the new world"
+// @Failure 400 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/worlds [post]
+func (c *WorldController) CreateWorld(ctx echo.Context) error {
+	var req models.GenerateWorldRequest
+	if err := ctx.Bind(&req); err != nil {
+		return problem(ctx, http.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.worldService.ValidateGenerateRequest(&req); err != nil {
+		return problem(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	var opts []services.GenerateOption
+	if req.Climate != "" {
+		opts = append(opts, services.WithClimate(req.Climate))
+	}
+	if req.Seed != nil {
+		opts = append(opts, services.WithSeed(*req.Seed))
+	}
+	if req.Diagnostics {
+		opts = append(opts, services.WithDiagnostics())
+	}
+
+	world, err := c.worldService.GenerateWorld(ctx.Request().Context(), req.Theme, opts...)
+	if err != nil {
+		return internalError(err, "Failed to generate world")
+	}
+
+	if world.ID != 0 {
+		ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/v2/worlds/%d", world.ID))
+	}
+	return ctx.JSON(http.StatusCreated, world)
+}
+
+// @Tags World
+// @Summary Search for worlds
+// @Description Lists the stored worlds matching a search query, theme and climate, newest first
+// @Produce json
+// @Produce application/problem+json
+// @Param query query string false "Search query (name/description)"
+// @Param theme query string false "Filter by theme"
+// @Param climate query string false "Filter by climate"
+// @Param limit query int false "Limit results" default(10) minimum(1) maximum(100)
+// @Param offset query int false "Offset for pagination" default(0) minimum(0)
+// @Success 200 {object} models.PaginatedWorldsResponse
+// @Failure 400 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/worlds [get]
+func (c *WorldController) ListWorlds(ctx echo.Context) error {
+	limit, err := queryInt(ctx, "limit", 10, 1, maxLimit)
+	if err != nil {
+		return err
+	}
+	offset, err := queryInt(ctx, "offset", 0, 0, math.MaxInt)
+	if err != nil {
+		return err
+	}
+
+	worlds, total, err := c.worldService.SearchWorlds(ctx.Request().Context(),
+		ctx.QueryParam("query"), ctx.QueryParam("theme"), ctx.QueryParam("climate"), limit, offset)
+	if err != nil {
+		return internalError(err, "Failed to search worlds")
+	}
+
+	return ctx.JSON(http.StatusOK, models.PaginatedWorldsResponse{
+		Data:   worlds,
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	})
+}
+
+// @Tags World
+// @Summary Generates a batch of worlds
+// @Description Generates count worlds concurrently following a theme mix and constraints, streaming each one as a line of NDJSON once saved.
+// @Description The batch is charged to the rate limit as count requests. A line with an error field ends the stream if generation fails midway.
+// @Accept json
+// @Produce application/x-ndjson
+// @Produce application/problem+json
+// @Param request body models.BatchGenerateRequest true "Batch size, theme weights and constraints"
+// @Success 200 {object} models.World "One world per line"
+// @Failure 400 {object} models.Problem
+// @Failure 429 {object} models.Problem
+// @Router /v2/worlds/batches [post]
+func (c *WorldController) CreateWorldBatch(ctx echo.Context) error {
+	return handlers.GenerateBatch(ctx, c.worldService)
+}
+
+// @Tags World
+// @Summary Gets a specific world by ID
+// @Description Retrieves a world from the database by its ID
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param diagnostics query bool false "Include the report of the coherence rules, checked without repairing the world"
+// @Success 200 {object} models.World
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/worlds/{id} [get]
+func (c *WorldController) GetWorld(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	if ctx.QueryParam("diagnostics") == "true" {
+		world.Diagnostics = c.worldService.CheckWorld(world)
+	}
+
+	return ctx.JSON(http.StatusOK, world)
+}
+
+// @Tags World
+// @Summary Deletes a world
+// @Description Removes a world along with its points of interest and notifies the webhooks subscribed to world.deleted.
+// @Description Requires the admin token, and is disabled when the server has none.
+// @Security AdminToken
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Success 204
+// @Failure 400 {object} models.Problem
+// @Failure 401 {object} models.Problem
+// @Failure 403 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/worlds/{id} [delete]
+func (c *WorldController) DeleteWorld(ctx echo.Context) error {
+	id, err := handlers.PathID(ctx, "id", "world")
+	if err != nil {
+		return err
+	}
+
+	if err := c.worldService.DeleteWorld(ctx.Request().Context(), id); err != nil {
+		return internalError(err, "Failed to delete world")
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Tags World
+// @Summary Gets the religions of a world
+// @Description Retrieves the deities and belief systems generated for a world's cultures
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Success 200 {array} models.Religion
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/religions [get]
+func (c *WorldController) GetWorldReligions(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.Religions(ctx, world)
+}
+
+// @Tags World
+// @Summary Gets the economy of a world
+// @Description Computes the resource map, production per settlement, market prices and trade routes of a world.
+// @Description When turns is set, the market is simulated for that many turns and price shocks are reported.
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param turns query int false "Number of market simulation turns" default(0) minimum(0) maximum(100)
+// @Success 200 {object} models.Economy
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/economy [get]
+func (c *WorldController) GetWorldEconomy(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	turns, err := queryInt(ctx, "turns", 0, 0, services.MaxEconomyTurns)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateEconomy(world, turns))
+}
+
+// @Tags World
+// @Summary Gets the calendar of a world
+// @Description Retrieves the day and year length, the months named in the world's language and its festivals
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Success 200 {object} models.Calendar
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/calendar [get]
+func (c *WorldController) GetWorldCalendar(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateCalendar(world))
+}
+
+// @Tags World
+// @Summary Gets the weather of a world
+// @Description Returns the temperature, precipitation and weather events of a region on a date of the world calendar
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param date query string false "Date in the world calendar, as year-month-day" default(1-1-1)
+// @Param region query string false "Region or settlement name, defaults to the first region"
+// @Success 200 {object} models.Weather
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/weather [get]
+func (c *WorldController) GetWorldWeather(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.Weather(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Generates NPCs of a world
+// @Description Generates characters named in the world's languages, with a culture, an occupation fitting the climate
+// @Description and theme, personality traits and motivations. NPCs are numbered and always the same for a given ID.
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param count query int false "Number of NPCs" default(5) minimum(1) maximum(50)
+// @Param culture query string false "Only generate NPCs of this culture"
+// @Success 200 {array} models.NPC
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/npcs [get]
+func (c *WorldController) ListWorldNPCs(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	count, err := queryInt(ctx, "count", defaultCount, 1, services.MaxNPCCount)
+	if err != nil {
+		return err
+	}
+
+	return handlers.NPCs(ctx, c.worldService, world, count)
+}
+
+// @Tags World
+// @Summary Gets an NPC of a world by ID
+// @Description Regenerates the NPC of a world with the given ID
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param npc_id path int true "NPC ID"
+// @Success 200 {object} models.NPC
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/npcs/{npc_id} [get]
+func (c *WorldController) GetWorldNPC(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	npcID, err := handlers.PathID(ctx, "npc_id", "NPC")
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateNPC(world, npcID))
+}
+
+// @Tags World
+// @Summary Generates adventure hooks of a world
+// @Description Combines the world's dangers, cultures, fauna and features into adventure seeds with a patron, objective,
+// @Description complication, location, reward and difficulty tier. Hooks are numbered and always the same for a given ID.
+// @Description When a campaign is given, hooks already used in it are skipped.
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param count query int false "Number of hooks" default(5) minimum(1) maximum(20)
+// @Param tier query int false "Only generate hooks of this difficulty tier" Enums(1,2,3,4)
+// @Param campaign query string false "Skip the hooks already used in this campaign"
+// @Success 200 {array} models.AdventureHook
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/hooks [get]
+func (c *WorldController) ListWorldHooks(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	count, err := queryInt(ctx, "count", defaultCount, 1, services.MaxHookCount)
+	if err != nil {
+		return err
+	}
+	tier, err := queryInt(ctx, "tier", 0, 1, 4)
+	if err != nil {
+		return err
+	}
+
+	return handlers.Hooks(ctx, c.worldService, world, count, tier)
+}
+
+// @Tags World
+// @Summary Gets an adventure hook of a world by ID
+// @Description Regenerates the adventure hook of a world with the given ID
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param hook_id path int true "Hook ID"
+// @Success 200 {object} models.AdventureHook
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/hooks/{hook_id} [get]
+func (c *WorldController) GetWorldHook(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	hookID, err := handlers.PathID(ctx, "hook_id", "hook")
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, c.worldService.GenerateHook(world, hookID))
+}
+
+// @Tags World
+// @Summary Records the use of an adventure hook
+// @Description Records that a hook was used in a campaign, so it is no longer offered for that campaign
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param hook_id path int true "Hook ID"
+// @Param request body models.UseHookRequest true "Campaign using the hook"
+// @Success 201 {object} models.HookUsage
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/worlds/{id}/hooks/{hook_id}/usages [post]
+func (c *WorldController) CreateHookUsage(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.UseHook(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Gets the encounter tables of a world
+// @Description Builds weighted d100 encounter tables from the world's fauna, cultures and dangers, one per region of its map.
+// @Description Weights depend on the climate, the theme and the terrain of the region.
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param region query string false "Only return the table of this region or settlement"
+// @Success 200 {array} models.EncounterTable
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/encounters [get]
+func (c *WorldController) ListWorldEncounters(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.EncounterTables(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Rolls a random encounter
+// @Description Rolls on the encounter table of a region, defaulting to the first region of the world.
+// @Description The seed of the roll is returned, and sending it back repeats the same roll.
+// @Accept json
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param request body models.RollEncounterRequest false "Region and seed of the roll"
+// @Success 201 {object} models.EncounterRoll
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/encounters/rolls [post]
+func (c *WorldController) CreateEncounterRoll(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.RollEncounter(ctx, c.worldService, world, http.StatusCreated)
+}
+
+// @Tags World
+// @Summary Gets an encounter table for virtual tabletops
+// @Description Converts the encounter table of a region, defaulting to the first region, to the RollTable JSON format
+// @Description that Foundry VTT imports
+// @Produce json
+// @Produce application/problem+json
+// @Param id path int true "World ID"
+// @Param region query string false "Region or settlement name"
+// @Success 200 {object} models.FoundryRollTable
+// @Failure 400 {object} models.Problem
+// @Failure 404 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/worlds/{id}/encounters/roll-table [get]
+func (c *WorldController) GetEncounterRollTable(ctx echo.Context) error {
+	world, err := handlers.FindWorld(ctx, c.worldService)
+	if err != nil {
+		return err
+	}
+
+	return handlers.EncounterRollTable(ctx, c.worldService, world)
+}
+
+// @Tags World
+// @Summary Gets world history
+// @Description Retrieves the latest generated worlds (stored in Redis)
+// @Produce json
+// @Produce application/problem+json
+// @Success 200 {array} models.World
+// @Failure 500 {object} models.Problem
+// @Failure 503 {object} models.Problem
+// @Router /v2/history [get]
+func (c *WorldController) GetHistory(ctx echo.Context) error {
+	return handlers.History(ctx, c.worldService)
+}
+
+// @Tags World
+// @Summary Streams newly generated worlds
+// @Description Pushes every newly generated world as a Server-Sent Event named world.generated, whose data is the world.
+// @Description Reconnecting clients send the Last-Event-ID header (or the last_event_id query parameter) to receive the worlds they missed.
+// @Produce text/event-stream
+// @Produce application/problem+json
+// @Param theme query string false "Only stream worlds of this theme"
+// @Param climate query string false "Only stream worlds of this climate"
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Param last_event_id query int false "ID of the last event received, for clients unable to set headers"
+// @Success 200 {object} models.World "One event per world"
+// @Failure 400 {object} models.Problem
+// @Failure 500 {object} models.Problem
+// @Router /v2/history/stream [get]
+func (c *WorldController) StreamHistory(ctx echo.Context) error {
+	return handlers.StreamHistory(ctx, c.eventBus)
+}
+
+// Helper functions
+
+const (
+	// Default number of NPCs and hooks generated per request
+	defaultCount = 5
+	// Largest page of search results
+	maxLimit = 100
+)
+
+// queryInt parses an integer query parameter between lo and hi, returning def when it is absent
+func queryInt(ctx echo.Context, name string, def, lo, hi int) (int, error) {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < lo || n > hi {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must be an integer between %d and %d", name, lo, hi))
+	}
+	return n, nil
+}
@@ -58,6 +58,54 @@ const docTemplate = `{
                 }
             }
         },
+        "/graphql": {
+            "post": {
+                "description": "Queries worlds, their NPCs, hooks and locations, or generates worlds and locations. The schema can be introspected.\nField names match the JSON names of the REST API. Each query costs 1 per field, multiplied by the length of the lists\nit is nested in; queries above 1000 are rejected, and every 50 counts as one request for the rate limit.\nEach generation counts as at least one request, and a query holds at most 10 generations.\nGET requests take the query, operationName and variables parameters and cannot run mutations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "Runs a GraphQL query",
+                "parameters": [
+                    {
+                        "description": "Query, operation name and variables",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/gql.GraphQLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data and errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/v1": {
             "get": {
                 "description": "Provides information about the API v1 endpoints",
@@ -111,37 +159,110 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/world": {
+        "/v1/history/stream": {
             "get": {
-                "description": "Creates a world with random characteristics based on the chosen theme",
+                "description": "Pushes every newly generated world as a Server-Sent Event named world.generated, whose data is the world.\nReconnecting clients send the Last-Event-ID header (or the last_event_id query parameter) to receive the worlds they missed.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
                     "World"
                 ],
-                "summary": "Generates a new world",
+                "summary": "Streams newly generated worlds",
                 "parameters": [
                     {
-                        "enum": [
-                            "fantasy",
-                            "sci-fi",
-                            "post-apocalyptic"
-                        ],
                         "type": "string",
-                        "default": "fantasy",
-                        "description": "World theme",
+                        "description": "Only stream worlds of this theme",
                         "name": "theme",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream worlds of this climate",
+                        "name": "climate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received, for clients unable to set headers",
+                        "name": "last_event_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "One event per world",
                         "schema": {
                             "$ref": "#/definitions/models.World"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs": {
+            "post": {
+                "description": "Queues an expensive generation and returns right away with the job ID to poll.\nTypes: world (params: theme, seed), batch (params: a batch request, charged to the rate limit as count requests),\neconomy (params: world_id, turns) and map (params: world_id, location_id, format as ascii or svg).\nFailed jobs are retried with exponential backoff up to max_attempts; a retried batch keeps the worlds it already saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Enqueues a background job",
+                "parameters": [
+                    {
+                        "description": "Job type and parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "429": {
                         "description": "Too Many Requests",
                         "schema": {
@@ -163,20 +284,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/world/{id}": {
+        "/v1/jobs/{id}": {
             "get": {
-                "description": "Retrieves a world from the database by its ID",
+                "description": "Retrieves the status, progress and, once it has succeeded, the result of a job.\nJobs are kept for 24 hours after their last update.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "World"
+                    "Job"
                 ],
-                "summary": "Gets a specific world by ID",
+                "summary": "Gets a background job",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "World ID",
+                        "type": "string",
+                        "description": "Job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -186,7 +307,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.World"
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
                     "404": {
@@ -208,57 +329,155 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/worlds": {
-            "get": {
-                "description": "Search for worlds based on various criteria",
+            },
+            "delete": {
+                "description": "Cancels a queued, retrying or running job",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "World"
+                    "Job"
                 ],
-                "summary": "Search for worlds",
+                "summary": "Cancels a background job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query (name/description)",
-                        "name": "query",
-                        "in": "query"
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by theme",
-                        "name": "theme",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by climate",
-                        "name": "climate",
-                        "in": "query"
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/systems": {
+            "post": {
+                "description": "Creates a sci-fi star system with its star, orbits, moons and habitable zone.\nEvery habitable body gets a full world with a climate matching its orbit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Generates a new star system",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit results",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Generation parameters",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSystemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StarSystem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/systems/{id}": {
+            "get": {
+                "description": "Retrieves a star system and the worlds of its habitable bodies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Gets a specific star system by ID",
+                "parameters": [
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "System ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedWorldsResponse"
+                            "$ref": "#/definitions/models.StarSystem"
                         }
                     },
                     "400": {
@@ -270,6 +489,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -281,26 +509,5689 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.PaginatedWorldsResponse": {
-            "type": "object",
+        },
+        "/v1/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Retrieves the registered webhooks, without their secrets.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Lists the webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribes a URL to world.generated, world.updated, world.deleted and world.imported events. Each event is POSTed as JSON with the headers\nX-WorldGen-Event, X-WorldGen-Delivery, X-WorldGen-Timestamp and X-WorldGen-Signature, which holds \"sha256=\" followed by\nthe hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret. Failed deliveries are retried 5 times with exponential\nbackoff starting at 10 seconds, then kept in a dead-letter list. The secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Registers a webhook",
+                "parameters": [
+                    {
+                        "description": "URL and events, all events when empty",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Deletes a webhook; its pending deliveries are dropped.\nRequires the admin token, and is disabled when the server has none.",
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Unregisters a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Retrieves the latest 100 delivery attempts of a webhook, most recent first.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Gets the delivery log of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world": {
+            "get": {
+                "description": "Creates a world with random characteristics based on the chosen theme\nElements are drawn from weighted pools, and the rarity tier of each one is reported in rarities.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates a new world",
+                "parameters": [
+                    {
+                        "enum": [
+                            "fantasy",
+                            "sci-fi",
+                            "post-apocalyptic"
+                        ],
+                        "type": "string",
+                        "default": "fantasy",
+                        "description": "World theme",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed making generation reproducible; the seed used is returned with the world",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the report of the coherence rules run after generation",
+                        "name": "diagnostics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}": {
+            "get": {
+                "description": "Retrieves a world from the database by its ID.\nThe representation is negotiated with the Accept header: JSON by default, or YAML, NDJSON, XML and MessagePack\nwith the field names of JSON, or the Markdown dossier of the world without its locations.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/x-ndjson",
+                    "text/markdown",
+                    "application/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets a specific world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the report of the coherence rules, checked without repairing the world",
+                        "name": "diagnostics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted media types is available, listed in available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Removes a world along with its points of interest and notifies the webhooks subscribed to world.deleted.\nRequires the admin token, and is disabled when the server has none.",
+                "tags": [
+                    "World"
+                ],
+                "summary": "Deletes a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/calendar": {
+            "get": {
+                "description": "Retrieves the day and year length, the months named in the world's language and its festivals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the calendar of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Calendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/economy": {
+            "get": {
+                "description": "Computes the resource map, production per settlement, market prices and trade routes of a world.\nWhen turns is set, the market is simulated for that many turns and price shocks are reported.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the economy of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of market simulation turns",
+                        "name": "turns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Economy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/encounters": {
+            "get": {
+                "description": "Builds weighted d100 encounter tables from the world's fauna, cultures and dangers, one per region of its map.\nWeights depend on the climate, the theme and the terrain of the region.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the encounter tables of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the table of this region or settlement",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EncounterTable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/encounters/export": {
+            "get": {
+                "description": "Converts the encounter table of a region, defaulting to the first region, to the RollTable JSON format\nthat Foundry VTT imports",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Exports an encounter table for virtual tabletops",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region or settlement name",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FoundryRollTable"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/encounters/roll": {
+            "post": {
+                "description": "Rolls on the encounter table of a region, defaulting to the first region of the world.\nThe seed of the roll is returned, and sending it back repeats the same roll.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Rolls a random encounter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Region and seed of the roll",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RollEncounterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EncounterRoll"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/export": {
+            "get": {
+                "description": "Renders a dossier of the world with its description, every list, its religions, power system and locations.\nThe Markdown and HTML dossiers come from templates that can be replaced through EXPORT_TEMPLATE_DIR;\nthe PDF dossier is laid out from the Markdown one.",
+                "produces": [
+                    "text/markdown",
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exports a world as a document",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "md",
+                            "html",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "default": "md",
+                        "description": "Document format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/export/{target}": {
+            "get": {
+                "description": "Builds a file that a tool imports natively. The obsidian target is a zip file of an Obsidian vault,\nwith one note per world, culture, danger, language and location linked to each other with wikilinks.\nThe foundry target is a Foundry VTT journal entry, with a page per section of the world and per location,\nto import into a journal entry or a journal compendium.",
+                "produces": [
+                    "application/zip",
+                    "application/json"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exports a world to a tool",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "obsidian",
+                            "foundry"
+                        ],
+                        "type": "string",
+                        "description": "Tool to export to",
+                        "name": "target",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal entry of the foundry target; the obsidian target answers a zip file",
+                        "schema": {
+                            "$ref": "#/definitions/models.FoundryJournalEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/hooks": {
+            "get": {
+                "description": "Combines the world's dangers, cultures, fauna and features into adventure seeds with a patron, objective,\ncomplication, location, reward and difficulty tier. Hooks are numbered and always the same for a given ID.\nWhen a campaign is given, hooks already used in it are skipped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates adventure hooks of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 20,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of hooks",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            1,
+                            2,
+                            3,
+                            4
+                        ],
+                        "type": "integer",
+                        "description": "Only generate hooks of this difficulty tier",
+                        "name": "tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skip the hooks already used in this campaign",
+                        "name": "campaign",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AdventureHook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/hooks/{hook_id}": {
+            "get": {
+                "description": "Regenerates the adventure hook of a world with the given ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets an adventure hook of a world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hook ID",
+                        "name": "hook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdventureHook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/hooks/{hook_id}/use": {
+            "post": {
+                "description": "Records that a hook was used in a campaign, so it is no longer offered for that campaign",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Marks an adventure hook as used",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hook ID",
+                        "name": "hook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campaign using the hook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UseHookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HookUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/locations": {
+            "get": {
+                "description": "Retrieves every location generated for a world",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Lists the points of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a dungeon, ruin, bunker or similar location tied to one of the world's dangers and features,\nwith a room layout, inhabitants drawn from the world's fauna and dangers, treasure and a short history.\nRandom danger and feature of the world are used when they are not given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Generates a point of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Danger and feature of the location",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/locations/{location_id}": {
+            "get": {
+                "description": "Retrieves a location of a world by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Gets a point of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/locations/{location_id}/map": {
+            "get": {
+                "description": "Draws the rooms and corridors of a location as ASCII text or as an SVG image",
+                "produces": [
+                    "text/plain",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Renders the layout of a point of interest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ascii",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "default": "svg",
+                        "description": "Rendering format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/npcs": {
+            "get": {
+                "description": "Generates characters named in the world's languages, with a culture, an occupation fitting the climate\nand theme, personality traits and motivations. NPCs are numbered and always the same for a given ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates NPCs of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of NPCs",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only generate NPCs of this culture",
+                        "name": "culture",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NPC"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/npcs/{npc_id}": {
+            "get": {
+                "description": "Regenerates the NPC of a world with the given ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets an NPC of a world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "NPC ID",
+                        "name": "npc_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NPC"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/religions": {
+            "get": {
+                "description": "Retrieves the deities and belief systems generated for a world's cultures",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the religions of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Religion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/session": {
+            "get": {
+                "description": "Upgrades to a WebSocket on which several users edit a world together. Messages are JSON objects with a type:\n- snapshot (sent on join and resync): the world, the description revision, the participants and the locks\n- presence: the participants and locks, sent when they change\n- edit {field, value}: sets name, population, climate or a list field; the edit with the highest stamp wins\n- text {revision, ops}: edits the description with an ot.js style operation based on a revision; the author receives an ack with the new revision and the others the transformed operation\n- lock / unlock {field}: reserves a field for 30 seconds, renewed by locking again\n- resync: asks for a new snapshot\n- error {message}\nChanges are written back to the database every 15 seconds and when the last participant leaves.",
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Joins the editing session of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name shown to the other participants",
+                        "name": "user",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/world/{id}/weather": {
+            "get": {
+                "description": "Returns the temperature, precipitation and weather events of a region on a date of the world calendar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the weather of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1-1-1",
+                        "description": "Date in the world calendar, as year-month-day",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region or settlement name, defaults to the first region",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Weather"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/worlds": {
+            "get": {
+                "description": "Search for worlds based on various criteria.\nThe representation is negotiated with the Accept header like a single world; NDJSON has a line per world of the page\nand Markdown the dossier of every world of the page.",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "application/x-ndjson",
+                    "text/markdown",
+                    "application/xml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Search for worlds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (name/description)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by theme",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by climate",
+                        "name": "climate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedWorldsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "None of the accepted media types is available, listed in available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/worlds/batch": {
+            "post": {
+                "description": "Generates count worlds concurrently following a theme mix and constraints, streaming each one as a line of NDJSON once saved.\nThe batch is charged to the rate limit as count requests. A line with an error field ends the stream if generation fails midway.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates a batch of worlds",
+                "parameters": [
+                    {
+                        "description": "Batch size, theme weights and constraints",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchGenerateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One world per line",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/worlds/export": {
+            "get": {
+                "description": "Renders the dossier of every world matching the search, like the export of a single world, and packs them in a zip file.\nThe csv and parquet formats instead stream a dataset for analysis, with a row per world where every list is a JSON array,\nor with arrays=explode a row per item of the lists of a world, naming the list and the rarity of the item.\nDatasets hold up to 10000 worlds, 1000 by default.\nThe export is charged to the rate limit as one request per world requested, or per hundred worlds of a dataset.",
+                "produces": [
+                    "application/zip",
+                    "text/csv",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exports search results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (name/description)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by theme",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by climate",
+                        "name": "climate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "md",
+                            "html",
+                            "pdf",
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "default": "md",
+                        "description": "Document or dataset format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "explode"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Layout of the lists of the worlds in a dataset",
+                        "name": "arrays",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/worlds/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Stores worlds read from a JSON, NDJSON or YAML document, such as the output of GET /v1/world/{id}, GET /v1/worlds or a batch.\nA JSON or YAML document holds a world, a list of worlds or a page of search results; YAML may hold several documents.\nThe format comes from the Content-Type header unless the format parameter is given.\nEvery world is validated and reported on its own. Atomic imports store every world or none, answering 422 when some world fails.\nNew IDs are assigned unless ids=preserve, which keeps the IDs and star systems of the worlds and fails the worlds whose ID is taken.\nPreserving IDs requires the admin token.\nThe import is charged to the rate limit as one request per world.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Imports worlds",
+                "parameters": [
+                    {
+                        "description": "Worlds to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.World"
+                            }
+                        }
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "yaml"
+                        ],
+                        "type": "string",
+                        "description": "Format of the document, overriding the Content-Type header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Store every world or none",
+                        "name": "atomic",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "remap",
+                            "preserve"
+                        ],
+                        "type": "string",
+                        "default": "remap",
+                        "description": "Keep the IDs of the worlds or assign new ones",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "ids=preserve without the admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "ids=preserve on a server without an admin token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No world was imported",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResult"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v2": {
+            "get": {
+                "description": "Provides information about the API v2 endpoints",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API"
+                ],
+                "summary": "API v2 welcome page",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/history": {
+            "get": {
+                "description": "Retrieves the latest generated worlds (stored in Redis)",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets world history",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.World"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/history/stream": {
+            "get": {
+                "description": "Pushes every newly generated world as a Server-Sent Event named world.generated, whose data is the world.\nReconnecting clients send the Last-Event-ID header (or the last_event_id query parameter) to receive the worlds they missed.",
+                "produces": [
+                    "text/event-stream",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Streams newly generated worlds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only stream worlds of this theme",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream worlds of this climate",
+                        "name": "climate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last event received, for clients unable to set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One event per world",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/jobs": {
+            "post": {
+                "description": "Queues an expensive generation and returns right away with the job ID to poll.\nTypes: world (params: theme, seed), batch (params: a batch request, charged to the rate limit as count requests),\neconomy (params: world_id, turns) and map (params: world_id, location_id, format as ascii or svg).\nFailed jobs are retried with exponential backoff up to max_attempts; a retried batch keeps the worlds it already saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Enqueues a background job",
+                "parameters": [
+                    {
+                        "description": "Job type and parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateJobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/jobs/{id}": {
+            "get": {
+                "description": "Retrieves the status, progress and, once it has succeeded, the result of a job.\nJobs are kept for 24 hours after their last update.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Gets a background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancels a queued, retrying or running job",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Cancels a background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/systems": {
+            "post": {
+                "description": "Creates a sci-fi star system with its star, orbits, moons and habitable zone.\nEvery habitable body gets a full world with a climate matching its orbit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Generates a new star system",
+                "parameters": [
+                    {
+                        "description": "Generation parameters",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSystemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StarSystem"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new star system"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/systems/{id}": {
+            "get": {
+                "description": "Retrieves a star system and the worlds of its habitable bodies",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Gets a specific star system by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "System ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StarSystem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Retrieves the registered webhooks, without their secrets.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Lists the webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribes a URL to world.generated, world.updated, world.deleted and world.imported events. Each event is POSTed as JSON with the headers\nX-WorldGen-Event, X-WorldGen-Delivery, X-WorldGen-Timestamp and X-WorldGen-Signature, which holds \"sha256=\" followed by\nthe hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret. Failed deliveries are retried 5 times with exponential\nbackoff starting at 10 seconds, then kept in a dead-letter list. The secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Registers a webhook",
+                "parameters": [
+                    {
+                        "description": "URL and events, all events when empty",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Deletes a webhook; its pending deliveries are dropped.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Unregisters a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Retrieves the latest 100 delivery attempts of a webhook, most recent first.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Gets the delivery log of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds": {
+            "get": {
+                "description": "Lists the stored worlds matching a search query, theme and climate, newest first",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Search for worlds",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (name/description)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by theme",
+                        "name": "theme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by climate",
+                        "name": "climate",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedWorldsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a world with random characteristics based on the chosen theme and stores it.\nElements are drawn from weighted pools, and the rarity tier of each one is reported in rarities.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates a new world",
+                "parameters": [
+                    {
+                        "description": "Theme, climate and seed of the world",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenerateWorldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new world"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/batches": {
+            "post": {
+                "description": "Generates count worlds concurrently following a theme mix and constraints, streaming each one as a line of NDJSON once saved.\nThe batch is charged to the rate limit as count requests. A line with an error field ends the stream if generation fails midway.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates a batch of worlds",
+                "parameters": [
+                    {
+                        "description": "Batch size, theme weights and constraints",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchGenerateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One world per line",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}": {
+            "get": {
+                "description": "Retrieves a world from the database by its ID",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets a specific world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the report of the coherence rules, checked without repairing the world",
+                        "name": "diagnostics",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.World"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Removes a world along with its points of interest and notifies the webhooks subscribed to world.deleted.\nRequires the admin token, and is disabled when the server has none.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Deletes a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/calendar": {
+            "get": {
+                "description": "Retrieves the day and year length, the months named in the world's language and its festivals",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the calendar of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Calendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/economy": {
+            "get": {
+                "description": "Computes the resource map, production per settlement, market prices and trade routes of a world.\nWhen turns is set, the market is simulated for that many turns and price shocks are reported.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the economy of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of market simulation turns",
+                        "name": "turns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Economy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/encounters": {
+            "get": {
+                "description": "Builds weighted d100 encounter tables from the world's fauna, cultures and dangers, one per region of its map.\nWeights depend on the climate, the theme and the terrain of the region.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the encounter tables of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the table of this region or settlement",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EncounterTable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/encounters/roll-table": {
+            "get": {
+                "description": "Converts the encounter table of a region, defaulting to the first region, to the RollTable JSON format\nthat Foundry VTT imports",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets an encounter table for virtual tabletops",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region or settlement name",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FoundryRollTable"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/encounters/rolls": {
+            "post": {
+                "description": "Rolls on the encounter table of a region, defaulting to the first region of the world.\nThe seed of the roll is returned, and sending it back repeats the same roll.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Rolls a random encounter",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Region and seed of the roll",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RollEncounterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EncounterRoll"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/hooks": {
+            "get": {
+                "description": "Combines the world's dangers, cultures, fauna and features into adventure seeds with a patron, objective,\ncomplication, location, reward and difficulty tier. Hooks are numbered and always the same for a given ID.\nWhen a campaign is given, hooks already used in it are skipped.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates adventure hooks of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 20,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of hooks",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            1,
+                            2,
+                            3,
+                            4
+                        ],
+                        "type": "integer",
+                        "description": "Only generate hooks of this difficulty tier",
+                        "name": "tier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Skip the hooks already used in this campaign",
+                        "name": "campaign",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AdventureHook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/hooks/{hook_id}": {
+            "get": {
+                "description": "Regenerates the adventure hook of a world with the given ID",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets an adventure hook of a world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hook ID",
+                        "name": "hook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AdventureHook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/hooks/{hook_id}/usages": {
+            "post": {
+                "description": "Records that a hook was used in a campaign, so it is no longer offered for that campaign",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Records the use of an adventure hook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hook ID",
+                        "name": "hook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campaign using the hook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UseHookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HookUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/locations": {
+            "get": {
+                "description": "Retrieves every location generated for a world",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Lists the points of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a dungeon, ruin, bunker or similar location tied to one of the world's dangers and features,\nwith a room layout, inhabitants drawn from the world's fauna and dangers, treasure and a short history.\nRandom danger and feature of the world are used when they are not given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Generates a point of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Danger and feature of the location",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Location"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/locations/{location_id}": {
+            "get": {
+                "description": "Retrieves a location of a world by its ID",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Gets a point of interest of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/locations/{location_id}/map": {
+            "get": {
+                "description": "Draws the rooms and corridors of a location as ASCII text or as an SVG image",
+                "produces": [
+                    "text/plain",
+                    "image/svg+xml",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Location"
+                ],
+                "summary": "Renders the layout of a point of interest",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ascii",
+                            "svg"
+                        ],
+                        "type": "string",
+                        "default": "svg",
+                        "description": "Rendering format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/npcs": {
+            "get": {
+                "description": "Generates characters named in the world's languages, with a culture, an occupation fitting the climate\nand theme, personality traits and motivations. NPCs are numbered and always the same for a given ID.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Generates NPCs of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Number of NPCs",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only generate NPCs of this culture",
+                        "name": "culture",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NPC"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/npcs/{npc_id}": {
+            "get": {
+                "description": "Regenerates the NPC of a world with the given ID",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets an NPC of a world by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "NPC ID",
+                        "name": "npc_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.NPC"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/religions": {
+            "get": {
+                "description": "Retrieves the deities and belief systems generated for a world's cultures",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the religions of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Religion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/session": {
+            "get": {
+                "description": "Upgrades to a WebSocket on which several users edit a world together, with the messages of the v1 session:\nsnapshot, presence, edit, text, ack, lock, unlock, resync and error.\nChanges are written back to the database every 15 seconds and when the last participant leaves.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Joins the editing session of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name shown to the other participants",
+                        "name": "user",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.SessionMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/worlds/{id}/weather": {
+            "get": {
+                "description": "Returns the temperature, precipitation and weather events of a region on a date of the world calendar",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "World"
+                ],
+                "summary": "Gets the weather of a world",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "World ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1-1-1",
+                        "description": "Date in the world calendar, as year-month-day",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Region or settlement name, defaults to the first region",
+                        "name": "region",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Weather"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gql.GraphQLRequest": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.AdventureHook": {
+            "type": "object",
+            "properties": {
+                "complication": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "objective": {
+                    "type": "string"
+                },
+                "patron": {
+                    "$ref": "#/definitions/models.HookPatron"
+                },
+                "reward": {
+                    "$ref": "#/definitions/models.HookReward"
+                },
+                "tier": {
+                    "type": "integer"
+                },
+                "tier_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "world_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchConstraints": {
+            "type": "object",
+            "properties": {
+                "climates": {
+                    "description": "Climates the worlds are evenly drawn from; any climate when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_population": {
+                    "type": "integer"
+                },
+                "min_population": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchGenerateRequest": {
+            "type": "object",
+            "properties": {
+                "constraints": {
+                    "$ref": "#/definitions/models.BatchConstraints"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "seed": {
+                    "description": "Seed makes the whole batch reproducible",
+                    "type": "integer"
+                },
+                "themes": {
+                    "description": "Themes maps each theme to its relative weight in the batch; fantasy only when empty",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.Calendar": {
+            "type": "object",
+            "properties": {
+                "day_length_hours": {
+                    "type": "integer"
+                },
+                "festivals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Festival"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Month"
+                    }
+                },
+                "year_length_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CalendarDate": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "month_name": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CelestialBody": {
+            "type": "object",
+            "properties": {
+                "climate": {
+                    "type": "string"
+                },
+                "designation": {
+                    "type": "string"
+                },
+                "habitable": {
+                    "type": "boolean"
+                },
+                "moons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CelestialBody"
+                    }
+                },
+                "orbit_au": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "world_id": {
+                    "type": "integer"
+                },
+                "world_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CoherenceIssue": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Collapse": {
+            "type": "object",
+            "properties": {
+                "surviving_tech": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "years_ago": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateJobRequest": {
+            "type": "object",
+            "properties": {
+                "max_attempts": {
+                    "description": "MaxAttempts defaults to 3",
+                    "type": "integer"
+                },
+                "params": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "batch"
+                }
+            }
+        },
+        "models.CreateLocationRequest": {
+            "type": "object",
+            "properties": {
+                "danger": {
+                    "type": "string"
+                },
+                "feature": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSystemRequest": {
+            "type": "object",
+            "properties": {
+                "star_class": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "world.generated",
+                        "world.deleted"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret is generated when left empty",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/worlds"
+                }
+            }
+        },
+        "models.Deity": {
+            "type": "object",
+            "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Diagnostics": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CoherenceIssue"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Economy": {
+            "type": "object",
+            "properties": {
+                "goods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Good"
+                    }
+                },
+                "map": {
+                    "$ref": "#/definitions/models.WorldMap"
+                },
+                "production": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SettlementEconomy"
+                    }
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ResourceDeposit"
+                    }
+                },
+                "simulation": {
+                    "$ref": "#/definitions/models.MarketSimulation"
+                },
+                "trade_routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TradeRoute"
+                    }
+                }
+            }
+        },
+        "models.EncounterEntry": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "rarity": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EncounterRoll": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "$ref": "#/definitions/models.EncounterEntry"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "roll": {
+                    "type": "integer"
+                },
+                "seed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EncounterTable": {
+            "type": "object",
+            "properties": {
+                "dice": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncounterEntry"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "terrain": {
+                    "type": "string"
+                },
+                "world_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Festival": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FoundryJournalEntry": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "description": "Flags keep where the entry came from, under the world-gen scope",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {}
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FoundryJournalPage"
+                    }
+                }
+            }
+        },
+        "models.FoundryJournalPage": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "text": {
+                    "$ref": "#/definitions/models.FoundryPageText"
+                },
+                "title": {
+                    "$ref": "#/definitions/models.FoundryPageTitle"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FoundryPageText": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "Format is 1 for HTML",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FoundryPageTitle": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "integer"
+                },
+                "show": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FoundryRollResult": {
+            "type": "object",
+            "properties": {
+                "drawn": {
+                    "type": "boolean"
+                },
+                "img": {
+                    "type": "string"
+                },
+                "range": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FoundryRollTable": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "displayRoll": {
+                    "type": "boolean"
+                },
+                "formula": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "boolean"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FoundryRollResult"
+                    }
+                }
+            }
+        },
+        "models.GenerateWorldRequest": {
+            "type": "object",
+            "properties": {
+                "climate": {
+                    "description": "Climate is random when empty",
+                    "type": "string",
+                    "example": "Temperate"
+                },
+                "diagnostics": {
+                    "description": "Diagnostics includes the report of the coherence rules run after generation",
+                    "type": "boolean"
+                },
+                "seed": {
+                    "description": "Seed makes generation reproducible",
+                    "type": "integer"
+                },
+                "theme": {
+                    "description": "Theme defaults to fantasy",
+                    "type": "string",
+                    "example": "fantasy"
+                }
+            }
+        },
+        "models.Good": {
+            "type": "object",
+            "properties": {
+                "base_price": {
+                    "type": "number"
+                },
+                "demand": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "scarcity": {
+                    "type": "string"
+                },
+                "supply": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HabitableZone": {
+            "type": "object",
+            "properties": {
+                "inner_au": {
+                    "type": "number"
+                },
+                "outer_au": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.HookPatron": {
+            "type": "object",
+            "properties": {
+                "culture": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "npc_id": {
+                    "type": "integer"
+                },
+                "occupation": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HookReward": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HookUsage": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string"
+                },
+                "hook_id": {
+                    "type": "integer"
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "world_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRecord": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the ID of the stored world",
+                    "type": "integer"
+                },
+                "index": {
+                    "description": "Index is the position of the world in the imported documents, from 0",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "description": "SourceID is the ID the world had in the imported documents",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRecord"
+                    }
+                },
+                "rolled_back": {
+                    "description": "RolledBack is set when an atomic import stored nothing because some record failed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Inhabitant": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "hostile": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "origin": {
+                    "type": "string"
+                },
+                "room": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "params": {
+                    "description": "Params holds the parameters of the job, whose shape depends on its type",
+                    "type": "object"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "result": {
+                    "description": "Result is only filled in once the job has succeeded",
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Location": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "danger": {
+                    "type": "string"
+                },
+                "feature": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inhabitants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Inhabitant"
+                    }
+                },
+                "layout": {
+                    "$ref": "#/definitions/models.LocationLayout"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "treasure": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Treasure"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "world_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LocationLayout": {
+            "type": "object",
+            "properties": {
+                "ascii": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "legend": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Room"
+                    }
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MagicSystem": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string"
+                },
+                "limitations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "practitioners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarketEvent": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "goods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "settlement": {
+                    "type": "string"
+                },
+                "turn": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarketSimulation": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarketEvent"
+                    }
+                },
+                "final_prices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "shocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceShock"
+                    }
+                },
+                "turns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Month": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "season": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NPC": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "culture": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "motivations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "occupation": {
+                    "type": "string"
+                },
+                "religion": {
+                    "type": "string"
+                },
+                "traits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "world_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PaginatedWorldsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.World"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Point": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PowerSystem": {
+            "type": "object",
+            "properties": {
+                "collapse": {
+                    "$ref": "#/definitions/models.Collapse"
+                },
+                "consequence": {
+                    "type": "string"
+                },
+                "cultures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dangers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "magic": {
+                    "$ref": "#/definitions/models.MagicSystem"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "technology": {
+                    "$ref": "#/definitions/models.TechnologyLevel"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceShock": {
+            "type": "object",
+            "properties": {
+                "cause": {
+                    "type": "string"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "good": {
+                    "type": "string"
+                },
+                "new_price": {
+                    "type": "number"
+                },
+                "old_price": {
+                    "type": "number"
+                },
+                "turn": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "world not found: ID 42"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/v2/worlds/42"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.Region": {
+            "type": "object",
+            "properties": {
+                "coastal": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "settlement": {
+                    "type": "string"
+                },
+                "terrain": {
+                    "type": "string"
+                },
+                "tiles": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Religion": {
+            "type": "object",
+            "properties": {
+                "cultures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Deity"
+                    }
+                },
+                "doctrine": {
+                    "type": "string"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "holy_sites": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rituals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "taboos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResourceDeposit": {
+            "type": "object",
+            "properties": {
+                "good": {
+                    "type": "string"
+                },
+                "position": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "richness": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RollEncounterRequest": {
+            "type": "object",
+            "properties": {
+                "region": {
+                    "type": "string"
+                },
+                "seed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Room": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "exits": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                },
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SessionLock": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SessionMessage": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "locks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.SessionLock"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "ops": {
+                    "description": "Ops is a text operation on the description: positive numbers retain, negative numbers delete and strings insert",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SessionPeer"
+                    }
+                },
+                "revision": {
+                    "description": "Revision of the description a text operation applies to, or that it produced; absent means 0",
+                    "type": "integer"
+                },
+                "stamp": {
+                    "description": "Stamp orders the edits of a field; the edit with the highest stamp wins",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                },
+                "world": {
+                    "$ref": "#/definitions/models.World"
+                }
+            }
+        },
+        "models.SessionPeer": {
+            "type": "object",
             "properties": {
-                "data": {
+                "client": {
+                    "type": "string"
+                },
+                "joined_at": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Settlement": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "population": {
+                    "type": "integer"
+                },
+                "position": {
+                    "$ref": "#/definitions/models.Point"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "terrain": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SettlementEconomy": {
+            "type": "object",
+            "properties": {
+                "demand": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "prices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "production": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "settlement": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Star": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "luminosity": {
+                    "type": "number"
+                },
+                "mass": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "temperature_k": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StarSystem": {
+            "type": "object",
+            "properties": {
+                "bodies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CelestialBody"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "habitable_zone": {
+                    "$ref": "#/definitions/models.HabitableZone"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "star": {
+                    "$ref": "#/definitions/models.Star"
+                },
+                "worlds": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.World"
                     }
+                }
+            }
+        },
+        "models.TechnologyLevel": {
+            "type": "object",
+            "properties": {
+                "ai_status": {
+                    "type": "string"
                 },
-                "limit": {
+                "energy_source": {
+                    "type": "string"
+                },
+                "ftl": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TradeRoute": {
+            "type": "object",
+            "properties": {
+                "cost": {
                     "type": "integer"
                 },
-                "offset": {
+                "from": {
+                    "type": "string"
+                },
+                "inbound": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outbound": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Point"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Treasure": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "room": {
                     "type": "integer"
                 },
-                "total": {
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UseHookRequest": {
+            "type": "object",
+            "properties": {
+                "campaign": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Weather": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "type": "string"
+                },
+                "date": {
+                    "$ref": "#/definitions/models.CalendarDate"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "precipitation_mm": {
+                    "type": "number"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "season": {
+                    "type": "string"
+                },
+                "temperature_c": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the payloads; it is only returned when the webhook is created",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt": {
                     "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "next_retry": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is delivered, retrying or dead once every attempt has failed",
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "webhook_id": {
+                    "type": "string"
                 }
             }
         },
@@ -328,6 +6219,14 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "diagnostics": {
+                    "description": "Diagnostics is only filled in when the coherence report is requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Diagnostics"
+                        }
+                    ]
+                },
                 "fauna": {
                     "type": "array",
                     "items": {
@@ -361,11 +6260,80 @@ const docTemplate = `{
                 "population": {
                     "type": "integer"
                 },
+                "power_system": {
+                    "$ref": "#/definitions/models.PowerSystem"
+                },
+                "rarities": {
+                    "description": "Rarities maps every feature, creature, plant, culture, danger and language of the world to its rarity tier",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "religions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Religion"
+                    }
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "system_id": {
+                    "type": "integer"
+                },
                 "theme": {
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version counts the saves of the world, so that an update based on an older version is refused",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WorldMap": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "legend": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Region"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "settlements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Settlement"
+                    }
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "Admin token of destructive operations, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
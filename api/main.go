@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"math/rand"
+	"net"
 	"net/http"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 
 	"github.com/medinapdr/world-gen/config"
 	"github.com/medinapdr/world-gen/controllers"
+	"github.com/medinapdr/world-gen/controllers/rpc"
 	customMiddleware "github.com/medinapdr/world-gen/middlewares"
 	"github.com/medinapdr/world-gen/services"
 
@@ -26,18 +30,31 @@ import (
 // @host localhost:8080
 // @BasePath /
 // @schemes http https
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin token of destructive operations, as "Bearer <token>"
 
 func apiVersions(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"versions": []map[string]interface{}{
 			{
-				"version":  "v1",
-				"status":   "stable",
-				"docs":     "/swagger/index.html",
-				"released": "2025-05-04",
+				"version":    "v1",
+				"status":     "deprecated",
+				"docs":       "/swagger/index.html",
+				"released":   "2025-05-04",
+				"deprecated": controllers.V1DeprecatedAt.Format(time.DateOnly),
+				"sunset":     controllers.V1Sunset.Format(time.DateOnly),
+			},
+			{
+				"version": "v2",
+				"status":  "stable",
+				"docs":    "/swagger/index.html",
 			},
 		},
-		"current_version": "v1",
+		"current_version": "v2",
+		"graphql":         "/graphql",
+		"grpc":            "worldgen.v1.WorldGenerator",
 	})
 }
 
@@ -54,10 +71,24 @@ func main() {
 	defer dbConfig.Close()
 
 	// Initialize services
-	worldService := services.NewWorldService(dbConfig, appConfig)
+	eventBus := services.NewEventBus(dbConfig)
+	eventBus.Start(context.Background())
+	webhookService := services.NewWebhookService(dbConfig, appConfig, eventBus)
+	webhookService.Start(context.Background())
+	worldService := services.NewWorldService(dbConfig, appConfig, eventBus)
+	systemService := services.NewSystemService(dbConfig, worldService)
+	locationService := services.NewLocationService(dbConfig, appConfig)
+	jobService := services.NewJobService(dbConfig, appConfig, worldService, locationService)
+	jobService.Start(context.Background())
+	sessionService := services.NewSessionService(dbConfig, worldService)
+	sessionService.Start(context.Background())
+	exportService := services.NewExportService(appConfig, locationService)
 
 	// Create router
-	apiRouter := controllers.NewAPIRouter(worldService)
+	apiRouter := controllers.NewAPIRouter(worldService, systemService, locationService, jobService, eventBus, sessionService, webhookService, exportService)
+
+	// Start the gRPC server next to Echo
+	go startGRPCServer(dbConfig, appConfig, worldService, eventBus)
 
 	// Set up and start Echo server
 	e := setupEchoServer(dbConfig, appConfig, apiRouter)
@@ -74,20 +105,55 @@ func setupDatabaseConnections(dbConfig *config.DatabaseConfig) {
 	}
 }
 
+func startGRPCServer(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, worldService *services.WorldService, eventBus *services.EventBus) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", appConfig.GRPCPort))
+	if err != nil {
+		log.Printf("Warning: Failed to listen for gRPC: %v", err)
+		return
+	}
+
+	var rateLimiter *customMiddleware.RateLimiter
+	if dbConfig.RedisClient != nil {
+		rateLimiter = customMiddleware.NewRateLimiter(dbConfig.RedisClient, appConfig)
+	}
+	if err := rpc.NewServer(worldService, eventBus, rateLimiter).Serve(listener); err != nil {
+		log.Printf("Warning: gRPC server stopped: %v", err)
+	}
+}
+
 func setupEchoServer(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, apiRouter *controllers.APIRouter) *echo.Echo {
 	e := echo.New()
+	// Answer the errors of the services with the status of their kind
+	e.HTTPErrorHandler = controllers.HTTPErrorHandler
 
 	// Set up middleware
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		// Let browsers read the headers announcing the removal of API v1
+		ExposeHeaders: []string{"Deprecation", "Sunset", "Link"},
+	}))
 
 	if dbConfig.RedisClient != nil {
 		rateLimiter := customMiddleware.NewRateLimiter(dbConfig.RedisClient, appConfig)
 		e.Use(rateLimiter.Middleware())
 	}
 
+	// Deleting worlds cannot be undone, imported worlds keeping their IDs move the ID sequence and
+	// the URLs of webhooks are often credentials, so these are left to the holders of the admin token
+	e.Use(customMiddleware.RequireAdminToken(appConfig.AdminToken,
+		"DELETE /v1/world/:id",
+		"DELETE /v2/worlds/:id",
+		"POST /v1/worlds/import?ids=preserve",
+		"GET /v1/webhooks",
+		"DELETE /v1/webhooks/:id",
+		"GET /v1/webhooks/:id/deliveries",
+		"GET /v2/webhooks",
+		"DELETE /v2/webhooks/:id",
+		"GET /v2/webhooks/:id/deliveries",
+	))
+
 	// Set up routes
-	e.GET("/", redirectToV1)
+	e.GET("/", redirectToV2)
 	e.GET("/health", healthCheck)
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.GET("/api", apiVersions)
@@ -98,8 +164,8 @@ func setupEchoServer(dbConfig *config.DatabaseConfig, appConfig *config.AppConfi
 	return e
 }
 
-func redirectToV1(c echo.Context) error {
-	return c.Redirect(http.StatusMovedPermanently, "/v1")
+func redirectToV2(c echo.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, "/v2")
 }
 
 func healthCheck(c echo.Context) error {
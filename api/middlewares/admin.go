@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAdminToken restricts routes, given as "METHOD /route/:param", to the requests bearing the admin token.
+// A route given as "METHOD /route?param=value" is only restricted when the query parameter has that value.
+// The routes are disabled when no token is configured.
+func RequireAdminToken(token string, routes ...string) echo.MiddlewareFunc {
+	// Query parameters restricting each route, empty when the whole route is restricted
+	protected := make(map[string][]url.Values, len(routes))
+	for _, route := range routes {
+		route, query, _ := strings.Cut(route, "?")
+		conditions, _ := url.ParseQuery(query)
+		protected[route] = append(protected[route], conditions)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !restricted(protected[c.Request().Method+" "+c.Path()], c.QueryParams()) {
+				return next(c)
+			}
+
+			if token == "" {
+				return echo.NewHTTPError(http.StatusForbidden, "This operation is disabled on this server")
+			}
+			bearer, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return echo.NewHTTPError(http.StatusUnauthorized, "A valid admin token is required")
+			}
+			return next(c)
+		}
+	}
+}
+
+// restricted reports whether the query of a request meets one of the conditions of its route
+func restricted(conditions []url.Values, query url.Values) bool {
+	for _, condition := range conditions {
+		met := true
+		for param, values := range condition {
+			met = met && query.Get(param) == values[0]
+		}
+		if met {
+			return true
+		}
+	}
+	return false
+}
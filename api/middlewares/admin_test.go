@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		target string
+		auth   string
+		want   int
+	}{
+		{"open route", "secret", http.MethodGet, "/worlds/1", "", http.StatusOK},
+		{"restricted route without a token", "secret", http.MethodDelete, "/worlds/1", "", http.StatusUnauthorized},
+		{"restricted route with a wrong token", "secret", http.MethodDelete, "/worlds/1", "Bearer nope", http.StatusUnauthorized},
+		{"restricted route with the token", "secret", http.MethodDelete, "/worlds/1", "Bearer secret", http.StatusOK},
+		{"restricted route on a server without a token", "", http.MethodDelete, "/worlds/1", "Bearer secret", http.StatusForbidden},
+		{"query condition not met", "secret", http.MethodPost, "/import?ids=remap", "", http.StatusOK},
+		{"query condition absent", "secret", http.MethodPost, "/import", "", http.StatusOK},
+		{"query condition met", "secret", http.MethodPost, "/import?atomic=true&ids=preserve", "", http.StatusUnauthorized},
+		{"query condition met with the token", "secret", http.MethodPost, "/import?ids=preserve", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(RequireAdminToken(tt.token, "DELETE /worlds/:id", "POST /import?ids=preserve"))
+			ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+			e.GET("/worlds/:id", ok)
+			e.DELETE("/worlds/:id", ok)
+			e.POST("/import", ok)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.auth != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecation marks every response of a deprecated API version with the Deprecation (RFC 9745) and Sunset (RFC 8594)
+// headers, and links to the version replacing it
+func Deprecation(deprecatedAt, sunset time.Time, successor string) echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set("Deprecation", deprecation)
+			header.Set("Sunset", sunsetDate)
+			header.Add("Link", link)
+			return next(c)
+		}
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// rateLimitChargeKey stores in the Echo context the function charging the client's budget again
+const rateLimitChargeKey = "rate-limit-charge"
+
 // RateLimiter implements a request rate limiting middleware
 type RateLimiter struct {
 	redisClient *redis.Client
@@ -43,9 +46,14 @@ func (r *RateLimiter) Middleware() echo.MiddlewareFunc {
 				// Continue on Redis errors to avoid blocking requests
 				return next(c)
 			} else if exceeded {
-				return respondWithRateLimitExceeded(c)
+				return ErrRateLimitExceeded
 			}
 
+			// Let handlers charge heavier requests by their weight
+			c.Set(rateLimitChargeKey, func(units int64) (bool, error) {
+				return r.charge(c, units, true)
+			})
+
 			return next(c)
 		}
 	}
@@ -59,29 +67,58 @@ func isExemptFromRateLimit(path string) bool {
 
 // isRateLimitExceeded checks if the client has exceeded their rate limit
 func (r *RateLimiter) isRateLimitExceeded(c echo.Context) (bool, error) {
-	userIP := c.RealIP()
-	key := "rate-limit:" + userIP
-	ctx := context.Background()
+	return r.charge(c, 1, false)
+}
+
+// charge adds units to the request counter of the client and checks the limit.
+// Refunded charges are taken back when they exceed the limit, so a rejected
+// heavy request does not use up the whole window.
+func (r *RateLimiter) charge(c echo.Context, units int64, refund bool) (bool, error) {
+	return r.chargeClient(context.Background(), c.RealIP(), units, refund)
+}
+
+// Allow counts a request of a client identified by its IP address, sharing the budget of its REST requests.
+// It reports whether the budget was exceeded; nothing is counted without Redis.
+func (r *RateLimiter) Allow(ctx context.Context, clientIP string) (bool, error) {
+	if r.redisClient == nil {
+		return false, nil
+	}
+	return r.chargeClient(ctx, clientIP, 1, false)
+}
+
+func (r *RateLimiter) chargeClient(ctx context.Context, clientIP string, units int64, refund bool) (bool, error) {
+	key := "rate-limit:" + clientIP
 
 	// Increment request counter for this IP
-	count, err := r.redisClient.Incr(ctx, key).Result()
+	count, err := r.redisClient.IncrBy(ctx, key, units).Result()
 	if err != nil {
 		return false, err
 	}
 
 	// Set expiration on new keys
-	if count == 1 {
+	if count == units {
 		windowDuration := time.Duration(r.appConfig.RateWindow) * time.Second
 		r.redisClient.Expire(ctx, key, windowDuration)
 	}
 
 	// Check if limit exceeded
-	return count > int64(r.appConfig.RateLimit), nil
+	exceeded := count > int64(r.appConfig.RateLimit)
+	if exceeded && refund {
+		r.redisClient.DecrBy(ctx, key, units)
+	}
+	return exceeded, nil
 }
 
-// respondWithRateLimitExceeded returns a standard rate limit exceeded response
-func respondWithRateLimitExceeded(c echo.Context) error {
-	return c.JSON(http.StatusTooManyRequests, map[string]string{
-		"error": "Request limit exceeded. Try again later.",
-	})
+// ChargeRateLimit charges a request weighing several ordinary requests to the client's budget.
+// The request already counted as one, so weight-1 more units are charged. It reports whether the
+// budget was exceeded, in which case nothing more is charged. Nothing is charged without rate limiting.
+func ChargeRateLimit(c echo.Context, weight int) (bool, error) {
+	charge, ok := c.Get(rateLimitChargeKey).(func(int64) (bool, error))
+	if !ok || weight <= 1 {
+		return false, nil
+	}
+	return charge(int64(weight - 1))
 }
+
+// ErrRateLimitExceeded is returned when a client exceeds its request budget
+var ErrRateLimitExceeded = echo.NewHTTPError(http.StatusTooManyRequests, "Request limit exceeded. Try again later.")
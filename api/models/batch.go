@@ -0,0 +1,19 @@
+package models
+
+// BatchGenerateRequest describes a batch of worlds to generate in a single request
+type BatchGenerateRequest struct {
+	Count int `json:"count" example:"100"`
+	// Themes maps each theme to its relative weight in the batch; fantasy only when empty
+	Themes      map[string]int   `json:"themes,omitempty"`
+	Constraints BatchConstraints `json:"constraints"`
+	// Seed makes the whole batch reproducible
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// BatchConstraints restricts the worlds generated in a batch
+type BatchConstraints struct {
+	// Climates the worlds are evenly drawn from; any climate when empty
+	Climates      []string `json:"climates,omitempty"`
+	MinPopulation int      `json:"min_population,omitempty"`
+	MaxPopulation int      `json:"max_population,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// Calendar describes how time is measured in a world
+type Calendar struct {
+	Language   string     `json:"language"`
+	DayLength  int        `json:"day_length_hours"`
+	YearLength int        `json:"year_length_days"`
+	Months     []Month    `json:"months"`
+	Festivals  []Festival `json:"festivals"`
+}
+
+// Month is a month of a world calendar
+type Month struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Days   int    `json:"days"`
+	Season string `json:"season"`
+}
+
+// Festival is a yearly celebration held on a given day of the calendar
+type Festival struct {
+	Name        string `json:"name"`
+	Month       int    `json:"month"`
+	Day         int    `json:"day"`
+	Description string `json:"description"`
+}
+
+// CalendarDate is a date expressed in a world calendar
+type CalendarDate struct {
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+	MonthName string `json:"month_name"`
+	Day       int    `json:"day"`
+}
+
+// Weather is the weather of a world region on a given date
+type Weather struct {
+	Date          CalendarDate `json:"date"`
+	Region        string       `json:"region"`
+	Season        string       `json:"season"`
+	Temperature   float64      `json:"temperature_c"`
+	Precipitation float64      `json:"precipitation_mm"`
+	Conditions    string       `json:"conditions"`
+	Events        []string     `json:"events"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Diagnostics is the report of the coherence rules run against a world
+type Diagnostics struct {
+	Valid  bool             `json:"valid"`
+	Issues []CoherenceIssue `json:"issues"`
+}
+
+// CoherenceIssue is a problem found by a coherence rule, and what was done about it
+type CoherenceIssue struct {
+	Rule    string `json:"rule"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Action  string `json:"action"`
+}
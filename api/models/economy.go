@@ -0,0 +1,73 @@
+package models
+
+// Economy describes the resources, production, prices and trade of a world
+type Economy struct {
+	Map         WorldMap            `json:"map"`
+	Resources   []ResourceDeposit   `json:"resources"`
+	Production  []SettlementEconomy `json:"production"`
+	Goods       []Good              `json:"goods"`
+	TradeRoutes []TradeRoute        `json:"trade_routes"`
+	Simulation  *MarketSimulation   `json:"simulation,omitempty"`
+}
+
+// ResourceDeposit is a source of a good found on a map tile
+type ResourceDeposit struct {
+	Good     string `json:"good"`
+	Position Point  `json:"position"`
+	Richness int    `json:"richness"`
+}
+
+// SettlementEconomy holds what a settlement produces and consumes per turn, and its local prices
+type SettlementEconomy struct {
+	Settlement string             `json:"settlement"`
+	Production map[string]int     `json:"production"`
+	Demand     map[string]int     `json:"demand"`
+	Prices     map[string]float64 `json:"prices"`
+}
+
+// Good is a tradeable good with its market price
+type Good struct {
+	Name      string  `json:"name"`
+	BasePrice float64 `json:"base_price"`
+	Price     float64 `json:"price"`
+	Supply    int     `json:"supply"`
+	Demand    int     `json:"demand"`
+	Scarcity  string  `json:"scarcity"`
+}
+
+// TradeRoute is the least-cost path between two settlements and the goods flowing along it
+type TradeRoute struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Outbound []string `json:"outbound"`
+	Inbound  []string `json:"inbound"`
+	Cost     int      `json:"cost"`
+	Path     []Point  `json:"path"`
+}
+
+// MarketSimulation reports the events and price shocks of a step-based market simulation
+type MarketSimulation struct {
+	Turns       int                `json:"turns"`
+	Events      []MarketEvent      `json:"events"`
+	Shocks      []PriceShock       `json:"shocks"`
+	FinalPrices map[string]float64 `json:"final_prices"`
+}
+
+// MarketEvent is something that changed production during a simulation turn
+type MarketEvent struct {
+	Turn        int      `json:"turn"`
+	Type        string   `json:"type"`
+	Settlement  string   `json:"settlement"`
+	Goods       []string `json:"goods"`
+	Description string   `json:"description"`
+}
+
+// PriceShock is a sudden change in the market price of a good
+type PriceShock struct {
+	Turn     int     `json:"turn"`
+	Good     string  `json:"good"`
+	OldPrice float64 `json:"old_price"`
+	NewPrice float64 `json:"new_price"`
+	Change   float64 `json:"change_percent"`
+	Cause    string  `json:"cause"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// EncounterTable is a weighted d100 table of the random encounters of a world region
+type EncounterTable struct {
+	WorldID int              `json:"world_id"`
+	Name    string           `json:"name"`
+	Region  string           `json:"region"`
+	Terrain string           `json:"terrain"`
+	Dice    string           `json:"dice"`
+	Entries []EncounterEntry `json:"entries"`
+}
+
+// EncounterEntry is a row of an encounter table, selected when the d100 roll falls between Min and Max
+type EncounterEntry struct {
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Rarity   string `json:"rarity"`
+	Quantity string `json:"quantity,omitempty"`
+}
+
+// RollEncounterRequest selects the table to roll on and, optionally, the seed of the roll
+type RollEncounterRequest struct {
+	Region string `json:"region"`
+	Seed   *int64 `json:"seed"`
+}
+
+// EncounterRoll is the result of a roll on an encounter table
+type EncounterRoll struct {
+	Region   string         `json:"region"`
+	Seed     int64          `json:"seed"`
+	Roll     int            `json:"roll"`
+	Entry    EncounterEntry `json:"entry"`
+	Quantity int            `json:"quantity"`
+}
+
+// FoundryRollTable is an encounter table in the RollTable JSON format imported by Foundry VTT
+type FoundryRollTable struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Formula     string              `json:"formula"`
+	Replacement bool                `json:"replacement"`
+	DisplayRoll bool                `json:"displayRoll"`
+	Results     []FoundryRollResult `json:"results"`
+}
+
+// FoundryRollResult is a text result of a Foundry VTT RollTable
+type FoundryRollResult struct {
+	Type   int    `json:"type"`
+	Text   string `json:"text"`
+	Img    string `json:"img"`
+	Weight int    `json:"weight"`
+	Range  [2]int `json:"range"`
+	Drawn  bool   `json:"drawn"`
+}
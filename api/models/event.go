@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// WorldEvent notifies that a world was generated, updated or deleted
+type WorldEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	World     World     `json:"world"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// FoundryJournalEntry is a journal entry of Foundry VTT, imported with the Import Data action of a journal entry
+// or added to a journal compendium
+type FoundryJournalEntry struct {
+	Name  string               `json:"name"`
+	Pages []FoundryJournalPage `json:"pages"`
+	// Flags keep where the entry came from, under the world-gen scope
+	Flags map[string]map[string]any `json:"flags"`
+}
+
+// FoundryJournalPage is a text page of a journal entry
+type FoundryJournalPage struct {
+	Name  string           `json:"name"`
+	Type  string           `json:"type"`
+	Title FoundryPageTitle `json:"title"`
+	Text  FoundryPageText  `json:"text"`
+	Sort  int              `json:"sort"`
+}
+
+// FoundryPageTitle sets how the name of a page is shown
+type FoundryPageTitle struct {
+	Show  bool `json:"show"`
+	Level int  `json:"level"`
+}
+
+// FoundryPageText is the HTML content of a page
+type FoundryPageText struct {
+	// Format is 1 for HTML
+	Format  int    `json:"format"`
+	Content string `json:"content"`
+}
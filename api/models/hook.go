@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// AdventureHook is a structured adventure seed built from a world's dangers, cultures, fauna and features.
+// Hooks are derived from the world and their ID, so the same ID always yields the same hook.
+type AdventureHook struct {
+	ID           int        `json:"id"`
+	WorldID      int        `json:"world_id"`
+	Title        string     `json:"title"`
+	Tier         int        `json:"tier"`
+	TierName     string     `json:"tier_name"`
+	Patron       HookPatron `json:"patron"`
+	Objective    string     `json:"objective"`
+	Complication string     `json:"complication"`
+	Location     string     `json:"location"`
+	Reward       HookReward `json:"reward"`
+}
+
+// HookPatron is the NPC offering the hook, fetchable through the NPC endpoints
+type HookPatron struct {
+	NPCID      int    `json:"npc_id"`
+	Name       string `json:"name"`
+	Culture    string `json:"culture"`
+	Occupation string `json:"occupation"`
+}
+
+// HookReward is what the patron offers for completing the hook
+type HookReward struct {
+	Description string `json:"description"`
+	Value       int    `json:"value"`
+}
+
+// UseHookRequest names the campaign a hook is used in
+type UseHookRequest struct {
+	Campaign string `json:"campaign"`
+}
+
+// HookUsage records that a hook was used in a campaign
+type HookUsage struct {
+	Campaign string    `json:"campaign"`
+	WorldID  int       `json:"world_id"`
+	HookID   int       `json:"hook_id"`
+	UsedAt   time.Time `json:"used_at"`
+}
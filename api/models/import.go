@@ -0,0 +1,22 @@
+package models
+
+// ImportResult reports the outcome of an import record by record
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+	// RolledBack is set when an atomic import stored nothing because some record failed
+	RolledBack bool           `json:"rolled_back,omitempty"`
+	Records    []ImportRecord `json:"records"`
+}
+
+// ImportRecord is the outcome of importing one world
+type ImportRecord struct {
+	// Index is the position of the world in the imported documents, from 0
+	Index int    `json:"index"`
+	Name  string `json:"name,omitempty"`
+	// SourceID is the ID the world had in the imported documents
+	SourceID int `json:"source_id,omitempty"`
+	// ID is the ID of the stored world
+	ID    int    `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job is an asynchronous generation request processed by background workers
+type Job struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	// Params holds the parameters of the job, whose shape depends on its type
+	Params json.RawMessage `json:"params,omitempty" swaggertype:"object"`
+	// Result is only filled in once the job has succeeded
+	Result json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	Error  string          `json:"error,omitempty"`
+	// Checkpoint holds the state a retry resumes from, which is not exposed by the API
+	Checkpoint    json.RawMessage `json:"checkpoint,omitempty" swaggerignore:"true"`
+	Attempts      int             `json:"attempts"`
+	MaxAttempts   int             `json:"max_attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// CreateJobRequest describes a job to enqueue
+type CreateJobRequest struct {
+	Type   string          `json:"type" example:"batch"`
+	Params json.RawMessage `json:"params,omitempty" swaggertype:"object"`
+	// MaxAttempts defaults to 3
+	MaxAttempts int `json:"max_attempts,omitempty"`
+}
+
+// WorldJobParams are the parameters of a world job
+type WorldJobParams struct {
+	Theme string `json:"theme,omitempty"`
+	Seed  *int64 `json:"seed,omitempty"`
+}
+
+// EconomyJobParams are the parameters of an economy job
+type EconomyJobParams struct {
+	WorldID int `json:"world_id"`
+	Turns   int `json:"turns,omitempty"`
+}
+
+// MapJobParams are the parameters of a map job
+type MapJobParams struct {
+	WorldID    int `json:"world_id"`
+	LocationID int `json:"location_id"`
+	// Format is ascii or svg, defaulting to svg
+	Format string `json:"format,omitempty"`
+}
+
+// MapJobResult is the map of a location rendered by a map job
+type MapJobResult struct {
+	Format string `json:"format"`
+	Map    string `json:"map"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Location is a point of interest of a world, such as a dungeon, ruin or bunker, tied to one of its dangers
+type Location struct {
+	ID          int            `json:"id,omitempty"`
+	WorldID     int            `json:"world_id"`
+	Name        string         `json:"name"`
+	Type        string         `json:"type"`
+	Danger      string         `json:"danger"`
+	Feature     string         `json:"feature"`
+	History     string         `json:"history"`
+	Layout      LocationLayout `json:"layout"`
+	Inhabitants []Inhabitant   `json:"inhabitants"`
+	Treasure    []Treasure     `json:"treasure"`
+	CreatedAt   time.Time      `json:"created_at,omitempty"`
+}
+
+// LocationLayout is the grid of a location with its rooms and the ASCII rendering of the grid
+type LocationLayout struct {
+	Width  int               `json:"width"`
+	Height int               `json:"height"`
+	Rooms  []Room            `json:"rooms"`
+	ASCII  []string          `json:"ascii"`
+	Legend map[string]string `json:"legend"`
+}
+
+// Room is a rectangular room of a location, connected to other rooms by corridors
+type Room struct {
+	ID          int    `json:"id"`
+	Label       string `json:"label"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Exits       []int  `json:"exits"`
+}
+
+// Inhabitant is a group of creatures found in a room
+type Inhabitant struct {
+	Name    string `json:"name"`
+	Origin  string `json:"origin"`
+	Count   int    `json:"count"`
+	Room    int    `json:"room"`
+	Hostile bool   `json:"hostile"`
+}
+
+// Treasure is an item of value hidden in a room
+type Treasure struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+	Room  int    `json:"room"`
+}
+
+// CreateLocationRequest selects the danger and feature a location is built around.
+// Random ones from the world are used when left empty.
+type CreateLocationRequest struct {
+	Danger  string `json:"danger"`
+	Feature string `json:"feature"`
+}
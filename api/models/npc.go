@@ -0,0 +1,16 @@
+package models
+
+// NPC is a non-player character of a world. NPCs are derived from the world and their ID,
+// so the same ID always yields the same character.
+type NPC struct {
+	ID          int      `json:"id"`
+	WorldID     int      `json:"world_id"`
+	Name        string   `json:"name"`
+	Age         int      `json:"age"`
+	Culture     string   `json:"culture"`
+	Language    string   `json:"language"`
+	Religion    string   `json:"religion,omitempty"`
+	Occupation  string   `json:"occupation"`
+	Traits      []string `json:"traits"`
+	Motivations []string `json:"motivations"`
+}
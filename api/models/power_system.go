@@ -0,0 +1,37 @@
+package models
+
+// PowerSystem describes how power works in a world, according to its theme
+type PowerSystem struct {
+	Type        string           `json:"type"`
+	Name        string           `json:"name"`
+	Summary     string           `json:"summary"`
+	Magic       *MagicSystem     `json:"magic,omitempty"`
+	Technology  *TechnologyLevel `json:"technology,omitempty"`
+	Collapse    *Collapse        `json:"collapse,omitempty"`
+	Cultures    []string         `json:"cultures"`
+	Dangers     []string         `json:"dangers"`
+	Consequence string           `json:"consequence"`
+}
+
+// MagicSystem describes the rules of magic in fantasy worlds
+type MagicSystem struct {
+	Source        string   `json:"source"`
+	Cost          string   `json:"cost"`
+	Limitations   []string `json:"limitations"`
+	Practitioners []string `json:"practitioners"`
+}
+
+// TechnologyLevel describes the technology available in sci-fi worlds
+type TechnologyLevel struct {
+	Level        string `json:"level"`
+	FTL          string `json:"ftl"`
+	AIStatus     string `json:"ai_status"`
+	EnergySource string `json:"energy_source"`
+}
+
+// Collapse describes the end of the old world and what survived it in post-apocalyptic worlds
+type Collapse struct {
+	Type          string   `json:"type"`
+	YearsAgo      int      `json:"years_ago"`
+	SurvivingTech []string `json:"surviving_tech"`
+}
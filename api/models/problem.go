@@ -0,0 +1,10 @@
+package models
+
+// Problem is an RFC 7807 problem details object, the body of the error responses of API v2
+type Problem struct {
+	Type     string `json:"type" example:"about:blank"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"world not found: ID 42"`
+	Instance string `json:"instance,omitempty" example:"/v2/worlds/42"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Religion represents a faith or belief system followed by some of a world's cultures
+type Religion struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Doctrine  string   `json:"doctrine"`
+	Cultures  []string `json:"cultures"`
+	Domains   []string `json:"domains"`
+	Deities   []Deity  `json:"deities,omitempty"`
+	Symbols   []string `json:"symbols"`
+	HolySites []string `json:"holy_sites"`
+	Rituals   []string `json:"rituals"`
+	Taboos    []string `json:"taboos"`
+}
+
+// Deity represents a god, spirit or revered entity worshipped by a religion
+type Deity struct {
+	Name    string   `json:"name"`
+	Title   string   `json:"title"`
+	Domains []string `json:"domains"`
+	Symbol  string   `json:"symbol"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SessionMessage is exchanged over the WebSocket of a world editing session.
+// Which fields are set depends on the type of the message.
+type SessionMessage struct {
+	Type  string          `json:"type"`
+	Field string          `json:"field,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+	// Revision of the description a text operation applies to, or that it produced; absent means 0
+	Revision int `json:"revision,omitempty"`
+	// Ops is a text operation on the description: positive numbers retain, negative numbers delete and strings insert
+	Ops    json.RawMessage `json:"ops,omitempty" swaggertype:"array,object"`
+	User   string          `json:"user,omitempty"`
+	Client string          `json:"client,omitempty"`
+	// Stamp orders the edits of a field; the edit with the highest stamp wins
+	Stamp   int64                  `json:"stamp,omitempty"`
+	World   *World                 `json:"world,omitempty"`
+	Peers   []SessionPeer          `json:"peers,omitempty"`
+	Locks   map[string]SessionLock `json:"locks,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// SessionPeer is a participant of an editing session
+type SessionPeer struct {
+	Client   string    `json:"client"`
+	User     string    `json:"user"`
+	JoinedAt time.Time `json:"joined_at"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// SessionLock reserves a field for one participant until it expires or is released
+type SessionLock struct {
+	Field     string    `json:"field"`
+	User      string    `json:"user"`
+	Client    string    `json:"client"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
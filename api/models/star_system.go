@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// StarSystem is a star with its orbiting bodies, parent of the worlds generated for its habitable bodies
+type StarSystem struct {
+	ID            int             `json:"id,omitempty"`
+	Name          string          `json:"name"`
+	Star          Star            `json:"star"`
+	HabitableZone HabitableZone   `json:"habitable_zone"`
+	Bodies        []CelestialBody `json:"bodies"`
+	Worlds        []World         `json:"worlds"`
+	CreatedAt     time.Time       `json:"created_at,omitempty"`
+}
+
+// Star describes the primary star of a system
+type Star struct {
+	Name        string  `json:"name"`
+	Class       string  `json:"class"`
+	Description string  `json:"description"`
+	Temperature int     `json:"temperature_k"`
+	Luminosity  float64 `json:"luminosity"`
+	Mass        float64 `json:"mass"`
+}
+
+// HabitableZone is the range of orbits, in astronomical units, where liquid water can exist
+type HabitableZone struct {
+	InnerAU float64 `json:"inner_au"`
+	OuterAU float64 `json:"outer_au"`
+}
+
+// CelestialBody is a planet, moon or asteroid belt of a star system
+type CelestialBody struct {
+	Designation string          `json:"designation"`
+	Type        string          `json:"type"`
+	OrbitAU     float64         `json:"orbit_au,omitempty"`
+	Habitable   bool            `json:"habitable"`
+	Climate     string          `json:"climate,omitempty"`
+	WorldID     int             `json:"world_id,omitempty"`
+	WorldName   string          `json:"world_name,omitempty"`
+	Moons       []CelestialBody `json:"moons,omitempty"`
+}
+
+// CreateSystemRequest represents the optional parameters of a star system generation
+type CreateSystemRequest struct {
+	StarClass string `json:"star_class"`
+}
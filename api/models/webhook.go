@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Webhook receives the world events it subscribed to as signed HTTP POST requests
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the payloads; it is only returned when the webhook is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhookRequest describes a webhook to register
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" example:"https://example.com/hooks/worlds"`
+	Events []string `json:"events" example:"world.generated,world.deleted"`
+	// Secret is generated when left empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	EventID   int64  `json:"event_id"`
+	Event     string `json:"event"`
+	Attempt   int    `json:"attempt"`
+	// Status is delivered, retrying or dead once every attempt has failed
+	Status     string     `json:"status"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	DurationMS int64      `json:"duration_ms"`
+	NextRetry  *time.Time `json:"next_retry,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -3,19 +3,29 @@ package models
 import "time"
 
 type World struct {
-	ID          int       `json:"id,omitempty"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Population  int       `json:"population"`
-	Climate     string    `json:"climate"`
-	Features    []string  `json:"features"`
-	Theme       string    `json:"theme"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	Fauna       []string  `json:"fauna,omitempty"`
-	Flora       []string  `json:"flora,omitempty"`
-	Cultures    []string  `json:"cultures,omitempty"`
-	Dangers     []string  `json:"dangers,omitempty"`
-	Languages   []string  `json:"languages,omitempty"`
+	ID          int          `json:"id,omitempty"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Population  int          `json:"population"`
+	Climate     string       `json:"climate"`
+	Features    []string     `json:"features"`
+	Theme       string       `json:"theme"`
+	CreatedAt   time.Time    `json:"created_at,omitempty"`
+	Fauna       []string     `json:"fauna,omitempty"`
+	Flora       []string     `json:"flora,omitempty"`
+	Cultures    []string     `json:"cultures,omitempty"`
+	Dangers     []string     `json:"dangers,omitempty"`
+	Languages   []string     `json:"languages,omitempty"`
+	Religions   []Religion   `json:"religions,omitempty"`
+	PowerSystem *PowerSystem `json:"power_system,omitempty"`
+	SystemID    *int         `json:"system_id,omitempty"`
+	Seed        int64        `json:"seed,omitempty"`
+	// Version counts the saves of the world, so that an update based on an older version is refused
+	Version int `json:"version,omitempty"`
+	// Rarities maps every feature, creature, plant, culture, danger and language of the world to its rarity tier
+	Rarities map[string]string `json:"rarities,omitempty"`
+	// Diagnostics is only filled in when the coherence report is requested
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"`
 }
 
 // PaginatedWorldsResponse represents a paginated list of worlds with metadata
@@ -25,3 +35,15 @@ type PaginatedWorldsResponse struct {
 	Limit  int     `json:"limit"`
 	Offset int     `json:"offset"`
 }
+
+// GenerateWorldRequest describes a world to generate
+type GenerateWorldRequest struct {
+	// Theme defaults to fantasy
+	Theme string `json:"theme,omitempty" example:"fantasy"`
+	// Climate is random when empty
+	Climate string `json:"climate,omitempty" example:"Temperate"`
+	// Seed makes generation reproducible
+	Seed *int64 `json:"seed,omitempty"`
+	// Diagnostics includes the report of the coherence rules run after generation
+	Diagnostics bool `json:"diagnostics,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// WorldMap is a terrain grid of a world with its regions and settlements
+type WorldMap struct {
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Rows        []string          `json:"rows"`
+	Legend      map[string]string `json:"legend"`
+	Regions     []Region          `json:"regions"`
+	Settlements []Settlement      `json:"settlements"`
+}
+
+// Region is an area of a world map around one of its settlements
+type Region struct {
+	Name       string `json:"name"`
+	Settlement string `json:"settlement"`
+	Terrain    string `json:"terrain"`
+	Coastal    bool   `json:"coastal"`
+	Tiles      int    `json:"tiles"`
+}
+
+// Point is a tile position on a world map
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Settlement represents a village, town or city placed on a world map
+type Settlement struct {
+	Name       string `json:"name"`
+	Size       string `json:"size"`
+	Population int    `json:"population"`
+	Terrain    string `json:"terrain"`
+	Position   Point  `json:"position"`
+}
@@ -0,0 +1,1342 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: worldgen/v1/world_generator.proto
+
+package worldgenv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type World struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Population  int64                  `protobuf:"varint,4,opt,name=population,proto3" json:"population,omitempty"`
+	Climate     string                 `protobuf:"bytes,5,opt,name=climate,proto3" json:"climate,omitempty"`
+	Theme       string                 `protobuf:"bytes,6,opt,name=theme,proto3" json:"theme,omitempty"`
+	Features    []string               `protobuf:"bytes,7,rep,name=features,proto3" json:"features,omitempty"`
+	Fauna       []string               `protobuf:"bytes,8,rep,name=fauna,proto3" json:"fauna,omitempty"`
+	Flora       []string               `protobuf:"bytes,9,rep,name=flora,proto3" json:"flora,omitempty"`
+	Cultures    []string               `protobuf:"bytes,10,rep,name=cultures,proto3" json:"cultures,omitempty"`
+	Dangers     []string               `protobuf:"bytes,11,rep,name=dangers,proto3" json:"dangers,omitempty"`
+	Languages   []string               `protobuf:"bytes,12,rep,name=languages,proto3" json:"languages,omitempty"`
+	Religions   []*Religion            `protobuf:"bytes,13,rep,name=religions,proto3" json:"religions,omitempty"`
+	PowerSystem *PowerSystem           `protobuf:"bytes,14,opt,name=power_system,json=powerSystem,proto3" json:"power_system,omitempty"`
+	SystemId    *int32                 `protobuf:"varint,15,opt,name=system_id,json=systemId,proto3,oneof" json:"system_id,omitempty"`
+	Seed        int64                  `protobuf:"varint,16,opt,name=seed,proto3" json:"seed,omitempty"`
+	// Rarity tier of every feature, creature, plant, culture, danger and language
+	Rarities      map[string]string      `protobuf:"bytes,17,rep,name=rarities,proto3" json:"rarities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *World) Reset() {
+	*x = World{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *World) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*World) ProtoMessage() {}
+
+func (x *World) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use World.ProtoReflect.Descriptor instead.
+func (*World) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *World) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *World) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *World) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *World) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *World) GetClimate() string {
+	if x != nil {
+		return x.Climate
+	}
+	return ""
+}
+
+func (x *World) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *World) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *World) GetFauna() []string {
+	if x != nil {
+		return x.Fauna
+	}
+	return nil
+}
+
+func (x *World) GetFlora() []string {
+	if x != nil {
+		return x.Flora
+	}
+	return nil
+}
+
+func (x *World) GetCultures() []string {
+	if x != nil {
+		return x.Cultures
+	}
+	return nil
+}
+
+func (x *World) GetDangers() []string {
+	if x != nil {
+		return x.Dangers
+	}
+	return nil
+}
+
+func (x *World) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *World) GetReligions() []*Religion {
+	if x != nil {
+		return x.Religions
+	}
+	return nil
+}
+
+func (x *World) GetPowerSystem() *PowerSystem {
+	if x != nil {
+		return x.PowerSystem
+	}
+	return nil
+}
+
+func (x *World) GetSystemId() int32 {
+	if x != nil && x.SystemId != nil {
+		return *x.SystemId
+	}
+	return 0
+}
+
+func (x *World) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *World) GetRarities() map[string]string {
+	if x != nil {
+		return x.Rarities
+	}
+	return nil
+}
+
+func (x *World) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type Religion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Doctrine      string                 `protobuf:"bytes,3,opt,name=doctrine,proto3" json:"doctrine,omitempty"`
+	Cultures      []string               `protobuf:"bytes,4,rep,name=cultures,proto3" json:"cultures,omitempty"`
+	Domains       []string               `protobuf:"bytes,5,rep,name=domains,proto3" json:"domains,omitempty"`
+	Deities       []*Deity               `protobuf:"bytes,6,rep,name=deities,proto3" json:"deities,omitempty"`
+	Symbols       []string               `protobuf:"bytes,7,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	HolySites     []string               `protobuf:"bytes,8,rep,name=holy_sites,json=holySites,proto3" json:"holy_sites,omitempty"`
+	Rituals       []string               `protobuf:"bytes,9,rep,name=rituals,proto3" json:"rituals,omitempty"`
+	Taboos        []string               `protobuf:"bytes,10,rep,name=taboos,proto3" json:"taboos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Religion) Reset() {
+	*x = Religion{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Religion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Religion) ProtoMessage() {}
+
+func (x *Religion) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Religion.ProtoReflect.Descriptor instead.
+func (*Religion) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Religion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Religion) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Religion) GetDoctrine() string {
+	if x != nil {
+		return x.Doctrine
+	}
+	return ""
+}
+
+func (x *Religion) GetCultures() []string {
+	if x != nil {
+		return x.Cultures
+	}
+	return nil
+}
+
+func (x *Religion) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Religion) GetDeities() []*Deity {
+	if x != nil {
+		return x.Deities
+	}
+	return nil
+}
+
+func (x *Religion) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *Religion) GetHolySites() []string {
+	if x != nil {
+		return x.HolySites
+	}
+	return nil
+}
+
+func (x *Religion) GetRituals() []string {
+	if x != nil {
+		return x.Rituals
+	}
+	return nil
+}
+
+func (x *Religion) GetTaboos() []string {
+	if x != nil {
+		return x.Taboos
+	}
+	return nil
+}
+
+type Deity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Domains       []string               `protobuf:"bytes,3,rep,name=domains,proto3" json:"domains,omitempty"`
+	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deity) Reset() {
+	*x = Deity{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deity) ProtoMessage() {}
+
+func (x *Deity) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deity.ProtoReflect.Descriptor instead.
+func (*Deity) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Deity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Deity) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Deity) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Deity) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type PowerSystem struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Type    string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Summary string                 `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	// Only set in fantasy worlds
+	Magic *MagicSystem `protobuf:"bytes,4,opt,name=magic,proto3" json:"magic,omitempty"`
+	// Only set in sci-fi worlds
+	Technology *TechnologyLevel `protobuf:"bytes,5,opt,name=technology,proto3" json:"technology,omitempty"`
+	// Only set in post-apocalyptic worlds
+	Collapse      *Collapse `protobuf:"bytes,6,opt,name=collapse,proto3" json:"collapse,omitempty"`
+	Cultures      []string  `protobuf:"bytes,7,rep,name=cultures,proto3" json:"cultures,omitempty"`
+	Dangers       []string  `protobuf:"bytes,8,rep,name=dangers,proto3" json:"dangers,omitempty"`
+	Consequence   string    `protobuf:"bytes,9,opt,name=consequence,proto3" json:"consequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PowerSystem) Reset() {
+	*x = PowerSystem{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerSystem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerSystem) ProtoMessage() {}
+
+func (x *PowerSystem) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerSystem.ProtoReflect.Descriptor instead.
+func (*PowerSystem) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PowerSystem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PowerSystem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PowerSystem) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *PowerSystem) GetMagic() *MagicSystem {
+	if x != nil {
+		return x.Magic
+	}
+	return nil
+}
+
+func (x *PowerSystem) GetTechnology() *TechnologyLevel {
+	if x != nil {
+		return x.Technology
+	}
+	return nil
+}
+
+func (x *PowerSystem) GetCollapse() *Collapse {
+	if x != nil {
+		return x.Collapse
+	}
+	return nil
+}
+
+func (x *PowerSystem) GetCultures() []string {
+	if x != nil {
+		return x.Cultures
+	}
+	return nil
+}
+
+func (x *PowerSystem) GetDangers() []string {
+	if x != nil {
+		return x.Dangers
+	}
+	return nil
+}
+
+func (x *PowerSystem) GetConsequence() string {
+	if x != nil {
+		return x.Consequence
+	}
+	return ""
+}
+
+type MagicSystem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Cost          string                 `protobuf:"bytes,2,opt,name=cost,proto3" json:"cost,omitempty"`
+	Limitations   []string               `protobuf:"bytes,3,rep,name=limitations,proto3" json:"limitations,omitempty"`
+	Practitioners []string               `protobuf:"bytes,4,rep,name=practitioners,proto3" json:"practitioners,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MagicSystem) Reset() {
+	*x = MagicSystem{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MagicSystem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MagicSystem) ProtoMessage() {}
+
+func (x *MagicSystem) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MagicSystem.ProtoReflect.Descriptor instead.
+func (*MagicSystem) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MagicSystem) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MagicSystem) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+func (x *MagicSystem) GetLimitations() []string {
+	if x != nil {
+		return x.Limitations
+	}
+	return nil
+}
+
+func (x *MagicSystem) GetPractitioners() []string {
+	if x != nil {
+		return x.Practitioners
+	}
+	return nil
+}
+
+type TechnologyLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         string                 `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Ftl           string                 `protobuf:"bytes,2,opt,name=ftl,proto3" json:"ftl,omitempty"`
+	AiStatus      string                 `protobuf:"bytes,3,opt,name=ai_status,json=aiStatus,proto3" json:"ai_status,omitempty"`
+	EnergySource  string                 `protobuf:"bytes,4,opt,name=energy_source,json=energySource,proto3" json:"energy_source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TechnologyLevel) Reset() {
+	*x = TechnologyLevel{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TechnologyLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TechnologyLevel) ProtoMessage() {}
+
+func (x *TechnologyLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TechnologyLevel.ProtoReflect.Descriptor instead.
+func (*TechnologyLevel) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TechnologyLevel) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *TechnologyLevel) GetFtl() string {
+	if x != nil {
+		return x.Ftl
+	}
+	return ""
+}
+
+func (x *TechnologyLevel) GetAiStatus() string {
+	if x != nil {
+		return x.AiStatus
+	}
+	return ""
+}
+
+func (x *TechnologyLevel) GetEnergySource() string {
+	if x != nil {
+		return x.EnergySource
+	}
+	return ""
+}
+
+type Collapse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	YearsAgo      int32                  `protobuf:"varint,2,opt,name=years_ago,json=yearsAgo,proto3" json:"years_ago,omitempty"`
+	SurvivingTech []string               `protobuf:"bytes,3,rep,name=surviving_tech,json=survivingTech,proto3" json:"surviving_tech,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Collapse) Reset() {
+	*x = Collapse{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Collapse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Collapse) ProtoMessage() {}
+
+func (x *Collapse) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Collapse.ProtoReflect.Descriptor instead.
+func (*Collapse) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Collapse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Collapse) GetYearsAgo() int32 {
+	if x != nil {
+		return x.YearsAgo
+	}
+	return 0
+}
+
+func (x *Collapse) GetSurvivingTech() []string {
+	if x != nil {
+		return x.SurvivingTech
+	}
+	return nil
+}
+
+type GenerateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to fantasy
+	Theme string `protobuf:"bytes,1,opt,name=theme,proto3" json:"theme,omitempty"`
+	// Random when empty
+	Climate string `protobuf:"bytes,2,opt,name=climate,proto3" json:"climate,omitempty"`
+	// Makes generation reproducible
+	Seed          *int64 `protobuf:"varint,3,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GenerateRequest) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetClimate() string {
+	if x != nil {
+		return x.Climate
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SearchRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Query   string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Theme   string                 `protobuf:"bytes,2,opt,name=theme,proto3" json:"theme,omitempty"`
+	Climate string                 `protobuf:"bytes,3,opt,name=climate,proto3" json:"climate,omitempty"`
+	// Defaults to 10, at most 100
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetClimate() string {
+	if x != nil {
+		return x.Climate
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Worlds        []*World               `protobuf:"bytes,1,rep,name=worlds,proto3" json:"worlds,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchResponse) GetWorlds() []*World {
+	if x != nil {
+		return x.Worlds
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type StreamHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Theme         string                 `protobuf:"bytes,1,opt,name=theme,proto3" json:"theme,omitempty"`
+	Climate       string                 `protobuf:"bytes,2,opt,name=climate,proto3" json:"climate,omitempty"`
+	LastEventId   int64                  `protobuf:"varint,3,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamHistoryRequest) Reset() {
+	*x = StreamHistoryRequest{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHistoryRequest) ProtoMessage() {}
+
+func (x *StreamHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHistoryRequest.ProtoReflect.Descriptor instead.
+func (*StreamHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StreamHistoryRequest) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *StreamHistoryRequest) GetClimate() string {
+	if x != nil {
+		return x.Climate
+	}
+	return ""
+}
+
+func (x *StreamHistoryRequest) GetLastEventId() int64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type WorldEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	World         *World                 `protobuf:"bytes,3,opt,name=world,proto3" json:"world,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorldEvent) Reset() {
+	*x = WorldEvent{}
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorldEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorldEvent) ProtoMessage() {}
+
+func (x *WorldEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_worldgen_v1_world_generator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorldEvent.ProtoReflect.Descriptor instead.
+func (*WorldEvent) Descriptor() ([]byte, []int) {
+	return file_worldgen_v1_world_generator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WorldEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WorldEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WorldEvent) GetWorld() *World {
+	if x != nil {
+		return x.World
+	}
+	return nil
+}
+
+func (x *WorldEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_worldgen_v1_world_generator_proto protoreflect.FileDescriptor
+
+var file_worldgen_v1_world_generator_proto_rawDesc = string([]byte{
+	0x0a, 0x21, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xa5, 0x05, 0x0a, 0x05, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x70, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x68, 0x65, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x66, 0x61, 0x75, 0x6e, 0x61, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x61,
+	0x75, 0x6e, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6c, 0x6f, 0x72, 0x61, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x66, 0x6c, 0x6f, 0x72, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x6c,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x6e, 0x67, 0x65, 0x72, 0x73,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x12, 0x33, 0x0a,
+	0x09, 0x72, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x6c, 0x69, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x69, 0x67, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x3b, 0x0a, 0x0c, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64,
+	0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x52, 0x0b, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12,
+	0x20, 0x0a, 0x09, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x3c, 0x0a, 0x08, 0x72, 0x61, 0x72, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x2e, 0x52, 0x61, 0x72, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x72, 0x61, 0x72, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x1a, 0x3b,
+	0x0a, 0x0d, 0x52, 0x61, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x22, 0x9d, 0x02, 0x0a, 0x08, 0x52, 0x65,
+	0x6c, 0x69, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x6f, 0x63, 0x74, 0x72, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x64, 0x6f, 0x63, 0x74, 0x72, 0x69, 0x6e, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75,
+	0x6c, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73,
+	0x12, 0x2c, 0x0a, 0x07, 0x64, 0x65, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x65, 0x69, 0x74, 0x79, 0x52, 0x07, 0x64, 0x65, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x6c, 0x79,
+	0x5f, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f,
+	0x6c, 0x79, 0x53, 0x69, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x69, 0x74, 0x75, 0x61,
+	0x6c, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x69, 0x74, 0x75, 0x61, 0x6c,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6f, 0x6f, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x61, 0x62, 0x6f, 0x6f, 0x73, 0x22, 0x63, 0x0a, 0x05, 0x44, 0x65, 0x69,
+	0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x22, 0xc8,
+	0x02, 0x0a, 0x0b, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x2e, 0x0a, 0x05, 0x6d, 0x61, 0x67, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61,
+	0x67, 0x69, 0x63, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x6d, 0x61, 0x67, 0x69, 0x63,
+	0x12, 0x3c, 0x0a, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x12, 0x31,
+	0x0a, 0x08, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6c, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6c, 0x6c, 0x61, 0x70, 0x73,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x6c, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x64, 0x61, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x64, 0x61, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f,
+	0x6e, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x81, 0x01, 0x0a, 0x0b, 0x4d, 0x61,
+	0x67, 0x69, 0x63, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x63, 0x6f, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x70, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d,
+	0x70, 0x72, 0x61, 0x63, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x72, 0x73, 0x22, 0x7b, 0x0a,
+	0x0f, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x74, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x66, 0x74, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x69, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x69, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x65, 0x72, 0x67, 0x79, 0x5f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e,
+	0x65, 0x72, 0x67, 0x79, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x62, 0x0a, 0x08, 0x43, 0x6f,
+	0x6c, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x79, 0x65,
+	0x61, 0x72, 0x73, 0x5f, 0x61, 0x67, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x79,
+	0x65, 0x61, 0x72, 0x73, 0x41, 0x67, 0x6f, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x75, 0x72, 0x76, 0x69,
+	0x76, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x65, 0x63, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0d, 0x73, 0x75, 0x72, 0x76, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x54, 0x65, 0x63, 0x68, 0x22, 0x63,
+	0x0a, 0x0f, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73,
+	0x65, 0x65, 0x64, 0x22, 0x1c, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x83, 0x01, 0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68, 0x65,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77, 0x6f, 0x72,
+	0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x52, 0x06,
+	0x77, 0x6f, 0x72, 0x6c, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x6a, 0x0a, 0x14, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x95, 0x01, 0x0a, 0x0a, 0x57, 0x6f, 0x72, 0x6c, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x77, 0x6f, 0x72,
+	0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64,
+	0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x52, 0x05, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x32, 0x94,
+	0x02, 0x0a, 0x0e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x3c, 0x0a, 0x08, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e,
+	0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x12,
+	0x32, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x17, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f,
+	0x72, 0x6c, 0x64, 0x12, 0x41, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x1a, 0x2e,
+	0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x77, 0x6f, 0x72, 0x6c,
+	0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x77, 0x6f, 0x72,
+	0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x4b, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x65, 0x64, 0x69, 0x6e, 0x61, 0x70, 0x64, 0x72, 0x2f, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x2d, 0x67, 0x65, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x77, 0x6f,
+	0x72, 0x6c, 0x64, 0x67, 0x65, 0x6e, 0x2f, 0x76, 0x31, 0x3b, 0x77, 0x6f, 0x72, 0x6c, 0x64, 0x67,
+	0x65, 0x6e, 0x76, 0x31, 0xaa, 0x02, 0x0b, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x47, 0x65, 0x6e, 0x2e,
+	0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_worldgen_v1_world_generator_proto_rawDescOnce sync.Once
+	file_worldgen_v1_world_generator_proto_rawDescData []byte
+)
+
+func file_worldgen_v1_world_generator_proto_rawDescGZIP() []byte {
+	file_worldgen_v1_world_generator_proto_rawDescOnce.Do(func() {
+		file_worldgen_v1_world_generator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_worldgen_v1_world_generator_proto_rawDesc), len(file_worldgen_v1_world_generator_proto_rawDesc)))
+	})
+	return file_worldgen_v1_world_generator_proto_rawDescData
+}
+
+var file_worldgen_v1_world_generator_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_worldgen_v1_world_generator_proto_goTypes = []any{
+	(*World)(nil),                 // 0: worldgen.v1.World
+	(*Religion)(nil),              // 1: worldgen.v1.Religion
+	(*Deity)(nil),                 // 2: worldgen.v1.Deity
+	(*PowerSystem)(nil),           // 3: worldgen.v1.PowerSystem
+	(*MagicSystem)(nil),           // 4: worldgen.v1.MagicSystem
+	(*TechnologyLevel)(nil),       // 5: worldgen.v1.TechnologyLevel
+	(*Collapse)(nil),              // 6: worldgen.v1.Collapse
+	(*GenerateRequest)(nil),       // 7: worldgen.v1.GenerateRequest
+	(*GetRequest)(nil),            // 8: worldgen.v1.GetRequest
+	(*SearchRequest)(nil),         // 9: worldgen.v1.SearchRequest
+	(*SearchResponse)(nil),        // 10: worldgen.v1.SearchResponse
+	(*StreamHistoryRequest)(nil),  // 11: worldgen.v1.StreamHistoryRequest
+	(*WorldEvent)(nil),            // 12: worldgen.v1.WorldEvent
+	nil,                           // 13: worldgen.v1.World.RaritiesEntry
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_worldgen_v1_world_generator_proto_depIdxs = []int32{
+	1,  // 0: worldgen.v1.World.religions:type_name -> worldgen.v1.Religion
+	3,  // 1: worldgen.v1.World.power_system:type_name -> worldgen.v1.PowerSystem
+	13, // 2: worldgen.v1.World.rarities:type_name -> worldgen.v1.World.RaritiesEntry
+	14, // 3: worldgen.v1.World.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 4: worldgen.v1.Religion.deities:type_name -> worldgen.v1.Deity
+	4,  // 5: worldgen.v1.PowerSystem.magic:type_name -> worldgen.v1.MagicSystem
+	5,  // 6: worldgen.v1.PowerSystem.technology:type_name -> worldgen.v1.TechnologyLevel
+	6,  // 7: worldgen.v1.PowerSystem.collapse:type_name -> worldgen.v1.Collapse
+	0,  // 8: worldgen.v1.SearchResponse.worlds:type_name -> worldgen.v1.World
+	0,  // 9: worldgen.v1.WorldEvent.world:type_name -> worldgen.v1.World
+	14, // 10: worldgen.v1.WorldEvent.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 11: worldgen.v1.WorldGenerator.Generate:input_type -> worldgen.v1.GenerateRequest
+	8,  // 12: worldgen.v1.WorldGenerator.Get:input_type -> worldgen.v1.GetRequest
+	9,  // 13: worldgen.v1.WorldGenerator.Search:input_type -> worldgen.v1.SearchRequest
+	11, // 14: worldgen.v1.WorldGenerator.StreamHistory:input_type -> worldgen.v1.StreamHistoryRequest
+	0,  // 15: worldgen.v1.WorldGenerator.Generate:output_type -> worldgen.v1.World
+	0,  // 16: worldgen.v1.WorldGenerator.Get:output_type -> worldgen.v1.World
+	10, // 17: worldgen.v1.WorldGenerator.Search:output_type -> worldgen.v1.SearchResponse
+	12, // 18: worldgen.v1.WorldGenerator.StreamHistory:output_type -> worldgen.v1.WorldEvent
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_worldgen_v1_world_generator_proto_init() }
+func file_worldgen_v1_world_generator_proto_init() {
+	if File_worldgen_v1_world_generator_proto != nil {
+		return
+	}
+	file_worldgen_v1_world_generator_proto_msgTypes[0].OneofWrappers = []any{}
+	file_worldgen_v1_world_generator_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worldgen_v1_world_generator_proto_rawDesc), len(file_worldgen_v1_world_generator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_worldgen_v1_world_generator_proto_goTypes,
+		DependencyIndexes: file_worldgen_v1_world_generator_proto_depIdxs,
+		MessageInfos:      file_worldgen_v1_world_generator_proto_msgTypes,
+	}.Build()
+	File_worldgen_v1_world_generator_proto = out.File
+	file_worldgen_v1_world_generator_proto_goTypes = nil
+	file_worldgen_v1_world_generator_proto_depIdxs = nil
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for batch world generation
+
+// MaxBatchCount is the highest number of worlds generated in a single batch
+const MaxBatchCount = 1000
+
+// MaxPopulation is the highest population of a world
+const MaxPopulation = 10000000 - 1
+
+// Number of generated worlds saved to the database at once
+const batchSaveSize = 100
+
+// batchJob holds the settings of one world of a batch, decided up front so the batch can be reproduced
+type batchJob struct {
+	index   int
+	theme   string
+	options generateOptions
+}
+
+// batchWorld is a generated world with its position in the batch
+type batchWorld struct {
+	index int
+	world *models.World
+}
+
+// ValidateBatchRequest checks a batch request, filling in the default theme mix
+func (s *WorldService) ValidateBatchRequest(req *models.BatchGenerateRequest) error {
+	if req.Count <= 0 || req.Count > MaxBatchCount {
+		return newError(ErrValidation, "count must be between 1 and %d", MaxBatchCount)
+	}
+
+	if len(req.Themes) == 0 {
+		req.Themes = map[string]int{"fantasy": 1}
+	}
+	for theme, weight := range req.Themes {
+		if !validateTheme(theme) {
+			return newError(ErrValidation, "unknown theme %q", theme)
+		}
+		if weight <= 0 {
+			return newError(ErrValidation, "the weight of theme %q must be positive", theme)
+		}
+	}
+
+	for _, climate := range req.Constraints.Climates {
+		if !validateClimate(climate) {
+			return newError(ErrValidation, "unknown climate %q", climate)
+		}
+	}
+
+	c := req.Constraints
+	if c.MinPopulation < 0 || c.MaxPopulation < 0 || c.MaxPopulation > MaxPopulation {
+		return newError(ErrValidation, "populations must be between 0 and %d", MaxPopulation)
+	}
+	// The range is checked against the maximum it gets when none is given
+	if c.MinPopulation > populationRange(c)[1] {
+		return newError(ErrValidation, "invalid population range %d to %d", c.MinPopulation, populationRange(c)[1])
+	}
+
+	return nil
+}
+
+// GenerateWorldBatch generates the worlds of a batch on a bounded pool of workers,
+// saving them to the database in chunks and passing each saved world to emit.
+// Worlds are emitted in the order they finish, and generation stops at the first emit error.
+func (s *WorldService) GenerateWorldBatch(ctx context.Context, req models.BatchGenerateRequest, emit func(w *models.World) error) error {
+	return s.generateBatch(ctx, req, nil, func(chunk []batchWorld) error {
+		for _, bw := range chunk {
+			if err := emit(bw.world); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// generateBatch generates the worlds of a batch whose index is not in skip,
+// passing each chunk to emit once it has been saved
+func (s *WorldService) generateBatch(ctx context.Context, req models.BatchGenerateRequest, skip map[int]bool, emit func(chunk []batchWorld) error) error {
+	if err := s.ValidateBatchRequest(&req); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan batchJob)
+	go func() {
+		defer close(jobs)
+		for _, job := range batchJobs(req) {
+			if skip[job.index] {
+				continue
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := s.appConfig.BatchWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	results := make(chan batchWorld)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A world that cannot be generated fails the batch instead of the whole process
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Panic generating batch world: %v\n%s", r, debug.Stack())
+					cancel(fmt.Errorf("failed to generate world: %v", r))
+				}
+			}()
+			for job := range jobs {
+				w, _ := buildWorld(job.theme, &job.options)
+				select {
+				case results <- batchWorld{index: job.index, world: w}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	chunk := make([]batchWorld, 0, batchSaveSize)
+	worlds := make([]*models.World, 0, batchSaveSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		worlds = worlds[:0]
+		for _, bw := range chunk {
+			worlds = append(worlds, bw.world)
+		}
+		if err := s.storeWorlds(ctx, worlds); err != nil {
+			return err
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for bw := range results {
+		if chunk = append(chunk, bw); len(chunk) == batchSaveSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// batchJobs decides the theme, climate, population range and seed of every world of the batch
+func batchJobs(req models.BatchGenerateRequest) []batchJob {
+	rng := rand.New(rand.NewSource(batchSeed(req)))
+
+	themes := sortedKeys(req.Themes)
+	total := 0
+	for _, theme := range themes {
+		total += req.Themes[theme]
+	}
+
+	var population *[2]int
+	if c := req.Constraints; c.MinPopulation > 0 || c.MaxPopulation > 0 {
+		r := populationRange(c)
+		population = &r
+	}
+
+	jobs := make([]batchJob, req.Count)
+	for i := range jobs {
+		jobs[i].index = i
+		roll := rng.Intn(total)
+		for _, theme := range themes {
+			if roll -= req.Themes[theme]; roll < 0 {
+				jobs[i].theme = theme
+				break
+			}
+		}
+
+		worldSeed := rng.Int63()
+		jobs[i].options.seed = &worldSeed
+		jobs[i].options.population = population
+		if climates := req.Constraints.Climates; len(climates) > 0 {
+			jobs[i].options.climate = climates[rng.Intn(len(climates))]
+		}
+	}
+
+	return jobs
+}
+
+// batchSeed returns the seed of the batch, drawing one when the request has none
+func batchSeed(req models.BatchGenerateRequest) int64 {
+	if req.Seed != nil {
+		return *req.Seed
+	}
+	return rand.Int63()
+}
+
+// populationRange returns the population range of the constraints, up to MaxPopulation when they have no maximum
+func populationRange(c models.BatchConstraints) [2]int {
+	if c.MaxPopulation == 0 {
+		return [2]int{c.MinPopulation, MaxPopulation}
+	}
+	return [2]int{c.MinPopulation, c.MaxPopulation}
+}
+
+// storeWorlds saves, caches and announces a chunk of worlds, handling failures to save them like GenerateWorld does
+func (s *WorldService) storeWorlds(ctx context.Context, worlds []*models.World) error {
+	if err := persist(s.dbConfig, s.appConfig, "batch", func() error { return s.saveWorldsToDB(ctx, worlds) }); err != nil {
+		return err
+	}
+
+	if s.dbConfig.RedisClient != nil {
+		s.cacheWorlds(ctx, worlds)
+	}
+
+	for _, w := range worlds {
+		s.events.Publish(ctx, EventWorldGenerated, w)
+	}
+	return nil
+}
+
+// saveWorldsToDB reserves IDs for the worlds, then copies them to the database in a single round trip
+func (s *WorldService) saveWorldsToDB(ctx context.Context, worlds []*models.World) error {
+	rows, err := s.dbConfig.DB.Query(ctx,
+		`SELECT nextval(pg_get_serial_sequence('worlds', 'id')) FROM generate_series(1, $1)`, len(worlds))
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+
+	columns := []string{"id", "name", "description", "population", "climate", "features", "theme",
+		"fauna", "flora", "cultures", "dangers", "languages", "religions", "power_system", "system_id", "seed"}
+	_, err = s.dbConfig.DB.CopyFrom(ctx, pgx.Identifier{"worlds"}, columns,
+		pgx.CopyFromSlice(len(worlds), func(i int) ([]any, error) {
+			w := worlds[i]
+			return []any{ids[i], w.Name, w.Description, w.Population, w.Climate, w.Features, w.Theme,
+				w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages, w.Religions, w.PowerSystem, w.SystemID, w.Seed}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	for i, w := range worlds {
+		w.ID = ids[i]
+		// The version the column defaults to
+		w.Version = 1
+	}
+	return nil
+}
+
+// cacheWorlds stores a chunk of worlds in Redis with a single pipeline
+func (s *WorldService) cacheWorlds(ctx context.Context, worlds []*models.World) {
+	historyKey := "world-history"
+	pipe := s.dbConfig.RedisClient.Pipeline()
+	for _, w := range worlds {
+		worldJSON, err := json.Marshal(w)
+		if err != nil {
+			log.Printf("Error serializing world: %v", err)
+			continue
+		}
+		pipe.LPush(ctx, historyKey, string(worldJSON))
+		if w.ID > 0 {
+			pipe.Set(ctx, fmt.Sprintf("world:%d", w.ID), string(worldJSON), 0)
+		}
+	}
+	pipe.LTrim(ctx, historyKey, 0, int64(s.appConfig.HistoryLimit-1))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error caching batch: %v", err)
+	}
+}
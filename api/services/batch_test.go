@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+func newTestWorldService() *WorldService {
+	db := &config.DatabaseConfig{}
+	return NewWorldService(db, config.NewAppConfig(), NewEventBus(db))
+}
+
+func TestValidateBatchRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.BatchGenerateRequest
+		wantErr bool
+	}{
+		{"default theme", models.BatchGenerateRequest{Count: 2}, false},
+		{"zero count", models.BatchGenerateRequest{Count: 0}, true},
+		{"count above maximum", models.BatchGenerateRequest{Count: MaxBatchCount + 1}, true},
+		{"unknown theme", models.BatchGenerateRequest{Count: 1, Themes: map[string]int{"western": 1}}, true},
+		{"non positive weight", models.BatchGenerateRequest{Count: 1, Themes: map[string]int{"fantasy": 0}}, true},
+		{"unknown climate", models.BatchGenerateRequest{Count: 1, Constraints: models.BatchConstraints{Climates: []string{"Lava"}}}, true},
+		{"negative minimum", models.BatchGenerateRequest{Count: 1, Constraints: models.BatchConstraints{MinPopulation: -1}}, true},
+		{"minimum above maximum", models.BatchGenerateRequest{Count: 1, Constraints: models.BatchConstraints{MinPopulation: 10, MaxPopulation: 5}}, true},
+		{"minimum above default maximum", models.BatchGenerateRequest{Count: 2, Constraints: models.BatchConstraints{MinPopulation: 20000000}}, true},
+		{"maximum above default maximum", models.BatchGenerateRequest{Count: 2, Constraints: models.BatchConstraints{MaxPopulation: MaxPopulation + 1}}, true},
+		{"minimum at default maximum", models.BatchGenerateRequest{Count: 2, Constraints: models.BatchConstraints{MinPopulation: MaxPopulation}}, false},
+		{"single population", models.BatchGenerateRequest{Count: 2, Constraints: models.BatchConstraints{MinPopulation: 42, MaxPopulation: 42}}, false},
+	}
+
+	s := newTestWorldService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateBatchRequest(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBatchRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("ValidateBatchRequest() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestGenerateWorldBatchRejectsPopulationAboveMaximum(t *testing.T) {
+	s := newTestWorldService()
+	req := models.BatchGenerateRequest{Count: 2, Constraints: models.BatchConstraints{MinPopulation: 20000000}}
+
+	err := s.GenerateWorldBatch(context.Background(), req, func(w *models.World) error {
+		t.Fatalf("unexpected world %q", w.Name)
+		return nil
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("GenerateWorldBatch() error = %v, want a validation error", err)
+	}
+}
+
+func TestGenerateWorldBatchPopulationRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints models.BatchConstraints
+	}{
+		{"minimum only", models.BatchConstraints{MinPopulation: MaxPopulation - 10}},
+		{"minimum at maximum", models.BatchConstraints{MinPopulation: MaxPopulation}},
+		{"closed range", models.BatchConstraints{MinPopulation: 1000, MaxPopulation: 1000}},
+	}
+
+	s := newTestWorldService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed := int64(7)
+			req := models.BatchGenerateRequest{Count: 8, Constraints: tt.constraints, Seed: &seed}
+			r := populationRange(tt.constraints)
+
+			count := 0
+			err := s.GenerateWorldBatch(context.Background(), req, func(w *models.World) error {
+				count++
+				if w.Population < r[0] || w.Population > r[1] {
+					t.Errorf("population %d outside of %v", w.Population, r)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("GenerateWorldBatch() error = %v", err)
+			}
+			if count != req.Count {
+				t.Errorf("GenerateWorldBatch() emitted %d worlds, want %d", count, req.Count)
+			}
+		})
+	}
+}
+
+func TestBatchJobsAreSeeded(t *testing.T) {
+	seed := int64(99)
+	req := models.BatchGenerateRequest{
+		Count:       20,
+		Themes:      map[string]int{"fantasy": 2, "sci-fi": 1},
+		Constraints: models.BatchConstraints{Climates: []string{"Arid", "Polar"}},
+		Seed:        &seed,
+	}
+
+	first, second := batchJobs(req), batchJobs(req)
+	for i := range first {
+		if first[i].theme != second[i].theme || first[i].options.climate != second[i].options.climate ||
+			*first[i].options.seed != *second[i].options.seed {
+			t.Fatalf("job %d differs between runs with the same seed", i)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for calendar and weather generation
+
+// climateProfile describes the yearly weather of a climate
+type climateProfile struct {
+	MeanTemp   float64  // yearly average temperature, in °C
+	Swing      float64  // difference between the seasonal extremes and the mean, in °C
+	Rain       float64  // average precipitation of a rainy day, in mm
+	RainChance float64  // chance of precipitation on any day
+	Seasons    []string // seasons in calendar order, starting with the coldest
+	WetSeason  int      // index of the wettest season, -1 when rain is even
+}
+
+var fourSeasons = []string{"Winter", "Spring", "Summer", "Autumn"}
+var polarSeasons = []string{"Polar night", "Thaw", "Midnight sun", "Freeze"}
+
+var climateProfiles = map[string]climateProfile{
+	"Arid":              {24, 10, 4, 0.05, []string{"Cool season", "Hot season"}, -1},
+	"Temperate":         {11, 9, 8, 0.4, fourSeasons, -1},
+	"Tropical":          {27, 2, 20, 0.5, []string{"Dry season", "Wet season"}, 1},
+	"Arctic":            {-12, 15, 3, 0.3, polarSeasons, -1},
+	"Mediterranean":     {17, 8, 10, 0.25, fourSeasons, 0},
+	"Alpine":            {3, 10, 9, 0.45, fourSeasons, -1},
+	"Oceanic":           {10, 5, 7, 0.55, fourSeasons, 0},
+	"Continental":       {8, 15, 9, 0.35, fourSeasons, 2},
+	"Monsoonal":         {25, 5, 25, 0.45, []string{"Dry season", "Monsoon", "Retreating monsoon"}, 1},
+	"Polar":             {-25, 15, 2, 0.2, polarSeasons, -1},
+	"Desert":            {28, 10, 3, 0.03, []string{"Cool season", "Hot season"}, -1},
+	"Savanna":           {25, 4, 15, 0.35, []string{"Dry season", "Wet season"}, 1},
+	"Rainforest":        {26, 1, 18, 0.7, []string{"Drier season", "Wet season"}, 1},
+	"Tundra":            {-8, 14, 3, 0.3, []string{"Long winter", "Thaw", "Short summer", "Freeze"}, 2},
+	"Humid Subtropical": {19, 8, 12, 0.4, fourSeasons, 2},
+}
+
+var festivalNamesByTheme = map[string][]string{
+	"fantasy":          {"Feast of %s", "%s Night", "The %s Games", "Rite of %s"},
+	"sci-fi":           {"%s Day", "The %s Uplink", "%s Remembrance", "Launch of %s"},
+	"post-apocalyptic": {"%s Day", "The %s Market", "Night of %s", "The %s Burning"},
+}
+
+// weatherEvent is a weather event caused by a world feature or danger
+type weatherEvent struct {
+	Event   string
+	Seasons []string // season keywords in which the event can happen, any when empty
+	Needs   string   // "rain", "dry", "cold", "hot" or "" for any weather
+	Chance  float64
+}
+
+var weatherEventsBySource = map[string]weatherEvent{
+	// features
+	"Monsoon storms":       {"Monsoon storm", []string{"monsoon", "wet"}, "rain", 0.5},
+	"Dust storms":          {"Dust storm", nil, "dry", 0.25},
+	"Summer storms":        {"Summer storm", []string{"summer"}, "rain", 0.4},
+	"Summer thunderstorms": {"Thunderstorm", []string{"summer"}, "rain", 0.4},
+	"Rain showers":         {"Rain showers", nil, "rain", 0.5},
+	"Frequent showers":     {"Passing showers", nil, "rain", 0.6},
+	"Summer rains":         {"Summer downpour", []string{"summer"}, "rain", 0.5},
+	"Seasonal flooding":    {"Flooding", []string{"wet", "monsoon"}, "rain", 0.3},
+	"Aurora borealis":      {"Aurora borealis", nil, "cold", 0.3},
+	"Shimmering lights":    {"Shimmering lights", nil, "cold", 0.3},
+	"Snow drifts":          {"Snow drifts", nil, "cold", 0.4},
+	"Midnight sun":         {"Midnight sun", []string{"midnight sun", "summer"}, "", 1},
+	"Polar night":          {"Polar night", []string{"polar night", "winter"}, "", 1},
+	"Seasonal burns":       {"Wildfires", []string{"dry"}, "dry", 0.2},
+	"Avalanche paths":      {"Avalanche", []string{"winter", "thaw"}, "cold", 0.15},
+	"Fog banks":            {"Fog", nil, "", 0.3},
+	"Dense fogs":           {"Fog", nil, "", 0.35},
+	"Foggy mornings":       {"Morning fog", nil, "", 0.4},
+	"Morning mist":         {"Morning mist", nil, "", 0.4},
+	"Mist curtains":        {"Mist", nil, "", 0.5},
+	"Mirages":              {"Mirages", nil, "hot", 0.5},
+	// dangers
+	"Deadly blizzards":             {"Blizzard", nil, "cold", 0.35},
+	"Freezing fog":                 {"Freezing fog", nil, "cold", 0.3},
+	"Toxic rain":                   {"Toxic rain", nil, "rain", 0.5},
+	"Soul-freezing winds":          {"Soul-freezing winds", nil, "cold", 0.3},
+	"Living storms":                {"Living storm", nil, "rain", 0.3},
+	"Sandstorm elementals":         {"Sandstorm", nil, "dry", 0.2},
+	"Avalanche spirits":            {"Avalanche", nil, "cold", 0.2},
+	"Heat madness":                 {"Heat wave", nil, "hot", 0.4},
+	"White-out zones":              {"White-out", nil, "cold", 0.3},
+	"Thermal anomalies":            {"Thermal anomaly", nil, "", 0.1},
+	"Weather control malfunctions": {"Weather control malfunction", nil, "", 0.15},
+}
+
+// Adjustments applied to the climate for the dominant terrain of a region
+var terrainTemperatureOffsets = map[string]float64{
+	TerrainMountains: -9,
+	TerrainHills:     -4,
+	TerrainIce:       -6,
+	TerrainDesert:    3,
+	TerrainJungle:    1,
+}
+
+// GenerateCalendar builds the deterministic calendar of a world
+func (s *WorldService) GenerateCalendar(w *models.World) *models.Calendar {
+	rng := rand.New(rand.NewSource(worldSeed(w, "calendar")))
+	profile := profileForClimate(w.Climate)
+	language := pickLanguage(rng, w.Languages, w.Theme)
+
+	dayLength := 20 + rng.Intn(9) // 20-28 hours
+	switch w.Theme {
+	case "sci-fi":
+		dayLength = 10 + rng.Intn(31) // alien worlds spin at any speed
+	case "post-apocalyptic":
+		dayLength = 24 // the old world's days
+	}
+
+	monthCount := 10 + rng.Intn(5) // 10-14 months
+	baseDays := 24 + rng.Intn(9)
+	dayCounts := make([]int, monthCount)
+	yearLength := 0
+	for i := range dayCounts {
+		dayCounts[i] = baseDays + rng.Intn(3) - 1
+		yearLength += dayCounts[i]
+	}
+
+	names := make(map[string]bool, monthCount)
+	months := make([]models.Month, 0, monthCount)
+	elapsed := 0
+	for i, days := range dayCounts {
+		name := languageWord(rng, language)
+		for attempts := 0; names[name] && attempts < 10; attempts++ {
+			name = languageWord(rng, language)
+		}
+		names[name] = true
+
+		middle := float64(elapsed) + float64(days)/2
+		months = append(months, models.Month{
+			Number: i + 1,
+			Name:   name,
+			Days:   days,
+			Season: profile.Seasons[seasonIndex(middle, yearLength, len(profile.Seasons))],
+		})
+		elapsed += days
+	}
+
+	return &models.Calendar{
+		Language:   language,
+		DayLength:  dayLength,
+		YearLength: yearLength,
+		Months:     months,
+		Festivals:  randomFestivals(rng, w, months, language),
+	}
+}
+
+// randomFestivals places festivals at the start of seasons and on days honoring the world's faiths
+func randomFestivals(rng *rand.Rand, w *models.World, months []models.Month, language string) []models.Festival {
+	templates := festivalNamesByTheme[w.Theme]
+	if templates == nil {
+		templates = festivalNamesByTheme["fantasy"]
+	}
+
+	var deities []models.Deity
+	for _, religion := range w.Religions {
+		deities = append(deities, religion.Deities...)
+	}
+
+	festivals := []models.Festival{}
+	for i, month := range months {
+		if i > 0 && months[i-1].Season == month.Season {
+			continue
+		}
+		if rng.Intn(2) == 0 {
+			continue
+		}
+		festivals = append(festivals, models.Festival{
+			Name:        fmt.Sprintf(templates[rng.Intn(len(templates))], languageWord(rng, language)),
+			Month:       month.Number,
+			Day:         1,
+			Description: "Marks the beginning of the " + strings.ToLower(month.Season),
+		})
+	}
+
+	for i := 0; i < 1+rng.Intn(2); i++ {
+		month := months[rng.Intn(len(months))]
+		festival := models.Festival{
+			Month: month.Number,
+			Day:   1 + rng.Intn(month.Days),
+		}
+
+		if len(deities) > 0 {
+			deity := deities[rng.Intn(len(deities))]
+			festival.Name = fmt.Sprintf(templates[rng.Intn(len(templates))], deity.Name)
+			festival.Description = fmt.Sprintf("Honors %s, %s", deity.Name, strings.ToLower(deity.Title))
+		} else {
+			festival.Name = fmt.Sprintf(templates[rng.Intn(len(templates))], languageWord(rng, language))
+			festival.Description = "A day of celebration"
+			if len(w.Cultures) > 0 {
+				festival.Description = "Celebrated by the " + strings.ToLower(w.Cultures[rng.Intn(len(w.Cultures))])
+			}
+		}
+		festivals = append(festivals, festival)
+	}
+
+	return festivals
+}
+
+// GenerateWeather returns the deterministic weather of a world region on a calendar date.
+// The date uses the "year-month-day" format of the world calendar and defaults to the first day of year 1;
+// the region is a region or settlement name and defaults to the first region of the world map.
+func (s *WorldService) GenerateWeather(w *models.World, date, regionName string) (*models.Weather, error) {
+	calendar := s.GenerateCalendar(w)
+	d, err := parseCalendarDate(calendar, date)
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := generateWorldMap(w).regionOrFirst(regionName)
+	if err != nil {
+		return nil, err
+	}
+
+	dayOfYear := d.Day - 1
+	for _, month := range calendar.Months[:d.Month-1] {
+		dayOfYear += month.Days
+	}
+
+	seed := worldSeed(w, fmt.Sprintf("weather:%s:%d-%d-%d", region.Name, d.Year, d.Month, d.Day))
+	rng := rand.New(rand.NewSource(seed))
+	profile := profileForClimate(w.Climate)
+	season := seasonIndex(float64(dayOfYear), calendar.YearLength, len(profile.Seasons))
+
+	// Coasts soften the seasons while deserts sharpen them
+	swing := profile.Swing
+	if region.Coastal {
+		swing *= 0.7
+	}
+	if region.Terrain == TerrainDesert {
+		swing *= 1.3
+	}
+
+	// The coldest day falls in the middle of the first season
+	phase := float64(dayOfYear)/float64(calendar.YearLength) - 0.5/float64(len(profile.Seasons))
+	temperature := profile.MeanTemp - swing*math.Cos(2*math.Pi*phase) +
+		terrainTemperatureOffsets[region.Terrain] + rng.NormFloat64()*2.5
+
+	rainChance, rainFactor := profile.RainChance, 1.0
+	if profile.WetSeason >= 0 {
+		if season == profile.WetSeason {
+			rainChance, rainFactor = math.Min(rainChance*1.8, 0.95), 1.5
+		} else {
+			rainChance, rainFactor = rainChance*0.5, 0.6
+		}
+	}
+	if region.Coastal || region.Terrain == TerrainJungle || region.Terrain == TerrainSwamp {
+		rainChance = math.Min(rainChance*1.2, 0.95)
+	}
+
+	precipitation := 0.0
+	if rng.Float64() < rainChance {
+		precipitation = profile.Rain * rainFactor * (0.3 + rng.ExpFloat64())
+	}
+
+	weather := &models.Weather{
+		Date:          *d,
+		Region:        region.Name,
+		Season:        profile.Seasons[season],
+		Temperature:   math.Round(temperature*10) / 10,
+		Precipitation: math.Round(precipitation*10) / 10,
+		Events:        []string{},
+	}
+	weather.Conditions = weatherConditions(rng, weather.Temperature, weather.Precipitation)
+
+	sources := append(append([]string{}, w.Features...), w.Dangers...)
+	for _, source := range sources {
+		event, ok := weatherEventsBySource[source]
+		if !ok || !eventCanHappen(event, weather) || rng.Float64() >= event.Chance {
+			continue
+		}
+		if !containsString(weather.Events, event.Event) {
+			weather.Events = append(weather.Events, event.Event)
+		}
+		if strings.Contains(strings.ToLower(event.Event), "fog") && weather.Precipitation == 0 {
+			weather.Conditions = "Fog"
+		}
+	}
+
+	return weather, nil
+}
+
+// parseCalendarDate parses a "year-month-day" date and checks it against the calendar
+func parseCalendarDate(calendar *models.Calendar, date string) (*models.CalendarDate, error) {
+	year, month, day := 1, 1, 1
+	if date != "" {
+		parts := strings.Split(date, "-")
+		if len(parts) != 3 {
+			return nil, newError(ErrValidation, "invalid date %q, expected year-month-day", date)
+		}
+
+		values := make([]int, 3)
+		for i, part := range parts {
+			v, err := strconv.Atoi(part)
+			if err != nil || v < 1 {
+				return nil, newError(ErrValidation, "invalid date %q, expected year-month-day", date)
+			}
+			values[i] = v
+		}
+		year, month, day = values[0], values[1], values[2]
+	}
+
+	if month > len(calendar.Months) {
+		return nil, newError(ErrValidation, "invalid month %d, the calendar has %d months", month, len(calendar.Months))
+	}
+	m := calendar.Months[month-1]
+	if day > m.Days {
+		return nil, newError(ErrValidation, "invalid day %d, %s has %d days", day, m.Name, m.Days)
+	}
+
+	return &models.CalendarDate{Year: year, Month: month, MonthName: m.Name, Day: day}, nil
+}
+
+// weatherConditions summarizes the sky for a temperature and an amount of precipitation
+func weatherConditions(rng *rand.Rand, temperature, precipitation float64) string {
+	switch {
+	case precipitation > 0 && temperature <= 0 && precipitation > 10:
+		return "Heavy snow"
+	case precipitation > 0 && temperature <= 0:
+		return "Snow"
+	case precipitation > 15:
+		return "Heavy rain"
+	case precipitation > 4:
+		return "Rain"
+	case precipitation > 0:
+		return "Drizzle"
+	}
+
+	skies := []string{"Clear", "Partly cloudy", "Overcast"}
+	return skies[rng.Intn(len(skies))]
+}
+
+// eventCanHappen checks the season and weather requirements of an event
+func eventCanHappen(event weatherEvent, weather *models.Weather) bool {
+	if len(event.Seasons) > 0 && !containsAnyKeyword(weather.Season, event.Seasons) {
+		return false
+	}
+
+	switch event.Needs {
+	case "rain":
+		return weather.Precipitation > 0
+	case "dry":
+		return weather.Precipitation == 0
+	case "cold":
+		return weather.Temperature <= 0
+	case "hot":
+		return weather.Temperature >= 30
+	}
+	return true
+}
+
+// seasonIndex returns the season of a day of the year, seasons being of equal length
+func seasonIndex(dayOfYear float64, yearLength, seasons int) int {
+	idx := int(dayOfYear / float64(yearLength) * float64(seasons))
+	if idx >= seasons {
+		idx = seasons - 1
+	}
+	return idx
+}
+
+// profileForClimate returns the weather profile of a climate
+func profileForClimate(climate string) climateProfile {
+	profile, ok := climateProfiles[climate]
+	if !ok {
+		profile = climateProfiles["Temperate"]
+	}
+	return profile
+}
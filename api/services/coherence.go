@@ -0,0 +1,357 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for the coherence rules checked after world generation
+
+// Actions taken on the issues found by coherence rules
+const (
+	IssueRepaired = "repaired"
+	IssueFlagged  = "flagged"
+)
+
+// Attempts made to repair a world before an issue is flagged instead
+const maxRepairAttempts = 3
+
+// Pools only exist for a few climates; every climate borrows from its closest one
+var climateAffinity = map[string]string{
+	"Arid":              "Arid",
+	"Temperate":         "Temperate",
+	"Tropical":          "Tropical",
+	"Arctic":            "Arctic",
+	"Mediterranean":     "Mediterranean",
+	"Alpine":            "Arctic",
+	"Oceanic":           "Temperate",
+	"Continental":       "Temperate",
+	"Monsoonal":         "Tropical",
+	"Polar":             "Arctic",
+	"Desert":            "Arid",
+	"Savanna":           "Arid",
+	"Rainforest":        "Tropical",
+	"Tundra":            "Arctic",
+	"Humid Subtropical": "Tropical",
+}
+
+// Power system type expected for each theme
+var powerSystemByTheme = map[string]string{
+	"fantasy":          PowerSystemMagic,
+	"sci-fi":           PowerSystemTechnology,
+	"post-apocalyptic": PowerSystemRemnant,
+}
+
+// Largest population expected for worlds with a polar climate
+const polarPopulationLimit = 5000000
+
+var polarClimates = []string{"Arctic", "Polar", "Tundra"}
+
+// coherenceRule is a cross-field consistency check of a world.
+// Rules without a repair function only flag the issues they find.
+type coherenceRule struct {
+	Name   string
+	Field  string
+	Check  func(w *models.World) []string
+	Repair func(rng *rand.Rand, w *models.World)
+}
+
+// Rules run in order, so that repairs of earlier fields are seen by later rules
+var coherenceRules = []coherenceRule{
+	{
+		Name:  "features-match-climate",
+		Field: "features",
+		Check: func(w *models.World) []string {
+			return missingFromPool("feature", w.Features, featurePool(w.Climate), w.Climate)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Features = randomFeatures(rng, w.Climate)
+		},
+	},
+	{
+		Name:  "fauna-match-climate",
+		Field: "fauna",
+		Check: func(w *models.World) []string {
+			return missingFromPool("creature", w.Fauna, coherentPool(faunaByClimate, w.Theme, w.Climate), w.Climate)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Fauna = weightedSample(rng, coherentPool(faunaByClimate, w.Theme, w.Climate), 2+rng.Intn(3), w.Features)
+		},
+	},
+	{
+		Name:  "flora-match-climate",
+		Field: "flora",
+		Check: func(w *models.World) []string {
+			return missingFromPool("plant", w.Flora, coherentPool(floraByClimate, w.Theme, w.Climate), w.Climate)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Flora = weightedSample(rng, coherentPool(floraByClimate, w.Theme, w.Climate), 2+rng.Intn(3), w.Features)
+		},
+	},
+	{
+		Name:  "dangers-match-climate",
+		Field: "dangers",
+		Check: func(w *models.World) []string {
+			return missingFromPool("danger", w.Dangers, coherentPool(dangersByTheme, w.Theme, w.Climate), w.Climate)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Dangers = weightedSample(rng, coherentPool(dangersByTheme, w.Theme, w.Climate), 1+rng.Intn(2), w.Features)
+		},
+	},
+	{
+		Name:  "cultures-match-theme",
+		Field: "cultures",
+		Check: func(w *models.World) []string {
+			return missingFromPool("culture", w.Cultures, culturesByTheme[w.Theme], w.Theme)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Cultures = randomCultures(rng, w.Theme, w.Features)
+		},
+	},
+	{
+		Name:  "languages-match-theme",
+		Field: "languages",
+		Check: func(w *models.World) []string {
+			return missingFromPool("language", w.Languages, languagesByTheme[w.Theme], w.Theme)
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Languages = randomLanguages(rng, w.Theme)
+		},
+	},
+	{
+		Name:  "tag-rules",
+		Field: "world",
+		Check: brokenTagRules,
+		Repair: func(rng *rand.Rand, w *models.World) {
+			removeTagRuleBreakers(rng, w)
+		},
+	},
+	{
+		Name:  "religions-follow-cultures",
+		Field: "religions",
+		Check: func(w *models.World) []string {
+			var problems []string
+			for _, religion := range w.Religions {
+				for _, culture := range religion.Cultures {
+					if !containsString(w.Cultures, culture) {
+						problems = append(problems, fmt.Sprintf("%s is followed by %s, which is not a culture of the world", religion.Name, culture))
+					}
+				}
+			}
+			return problems
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.Religions = randomReligions(rng, w.Theme, w.Climate, w.Cultures, w.Features, w.Languages)
+		},
+	},
+	{
+		Name:  "power-system-matches-theme",
+		Field: "power_system",
+		Check: func(w *models.World) []string {
+			expected, ok := powerSystemByTheme[w.Theme]
+			if !ok || w.PowerSystem == nil || w.PowerSystem.Type == expected {
+				return nil
+			}
+			return []string{fmt.Sprintf("%s worlds use %s, not %s", w.Theme, expected, w.PowerSystem.Type)}
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			w.PowerSystem = randomPowerSystem(rng, w.Theme, w.Cultures, w.Dangers, w.Languages)
+		},
+	},
+	{
+		Name:  "description-matches-lists",
+		Field: "description",
+		Check: func(w *models.World) []string {
+			var problems []string
+			for _, mention := range descriptionMentions(w) {
+				if !strings.Contains(w.Description, mention) {
+					problems = append(problems, fmt.Sprintf("the description does not mention %s", mention))
+				}
+			}
+			return problems
+		},
+		Repair: func(rng *rand.Rand, w *models.World) {
+			if len(w.Features) >= 2 {
+				w.Description = generateDescription(rng, w.Theme, w.Climate, w.Features, w.Fauna, w.Flora)
+			}
+		},
+	},
+	{
+		Name:  "population-fits-climate",
+		Field: "population",
+		Check: func(w *models.World) []string {
+			if containsString(polarClimates, w.Climate) && w.Population > polarPopulationLimit {
+				return []string{fmt.Sprintf("a population of %d is unusually large for a %s climate", w.Population, w.Climate)}
+			}
+			return nil
+		},
+	},
+}
+
+// applyCoherenceRules checks every rule against the world, repairing it when allowed,
+// and reports the issues found
+func applyCoherenceRules(rng *rand.Rand, w *models.World, repair bool) *models.Diagnostics {
+	report := &models.Diagnostics{Valid: true, Issues: []models.CoherenceIssue{}}
+
+	for _, rule := range coherenceRules {
+		problems := rule.Check(w)
+		if len(problems) == 0 {
+			continue
+		}
+
+		remaining := problems
+		if repair && rule.Repair != nil {
+			for attempt := 0; attempt < maxRepairAttempts && len(remaining) > 0; attempt++ {
+				rule.Repair(rng, w)
+				remaining = rule.Check(w)
+			}
+		}
+
+		action := IssueRepaired
+		if len(remaining) > 0 {
+			action = IssueFlagged
+			problems = remaining
+			report.Valid = false
+		}
+		for _, problem := range problems {
+			report.Issues = append(report.Issues, models.CoherenceIssue{
+				Rule:    rule.Name,
+				Field:   rule.Field,
+				Message: problem,
+				Action:  action,
+			})
+		}
+	}
+
+	return report
+}
+
+// CheckWorld runs the coherence rules against a stored world without changing it
+func (s *WorldService) CheckWorld(w *models.World) *models.Diagnostics {
+	return applyCoherenceRules(nil, w, false)
+}
+
+// featurePool returns the features expected for a climate
+func featurePool(climate string) []string {
+	if feats := featuresByClimate[climate]; feats != nil {
+		return feats
+	}
+	return featuresByClimate["Temperate"]
+}
+
+// coherentPool returns the pool of a theme for the closest climate that has one
+func coherentPool(pools map[string]map[string][]string, theme, climate string) []string {
+	byClimate := pools[theme]
+	if byClimate == nil {
+		byClimate = pools["fantasy"]
+	}
+	if pool := byClimate[climate]; pool != nil {
+		return pool
+	}
+	if pool := byClimate[climateAffinity[climate]]; pool != nil {
+		return pool
+	}
+	return byClimate["Temperate"]
+}
+
+// missingFromPool lists the items that do not belong to the pool expected for the world
+func missingFromPool(kind string, items, pool []string, source string) []string {
+	var problems []string
+	for _, item := range items {
+		if !containsString(pool, item) {
+			problems = append(problems, fmt.Sprintf("%s %q does not belong to %s worlds", kind, item, source))
+		}
+	}
+	return problems
+}
+
+// worldTags gathers the tags brought by every element of the world
+func worldTags(w *models.World) map[string]bool {
+	tags := make(map[string]bool)
+	for _, pool := range [][]string{w.Features, w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages} {
+		for _, item := range pool {
+			for _, tag := range poolEntries[item].Tags {
+				tags[tag] = true
+			}
+		}
+	}
+	return tags
+}
+
+// brokenTagRules lists the elements whose required tags are missing or whose excluded tags are present
+func brokenTagRules(w *models.World) []string {
+	tags := worldTags(w)
+	var problems []string
+	for _, pool := range [][]string{w.Features, w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages} {
+		for _, item := range pool {
+			entry := poolEntries[item]
+			for _, tag := range entry.Requires {
+				if !tags[tag] {
+					problems = append(problems, fmt.Sprintf("%s requires a %s element", item, tag))
+				}
+			}
+			for _, tag := range entry.Excludes {
+				if tags[tag] {
+					problems = append(problems, fmt.Sprintf("%s cannot exist alongside a %s element", item, tag))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// removeTagRuleBreakers drops the elements that break tag rules, re-rolling fields left empty
+func removeTagRuleBreakers(rng *rand.Rand, w *models.World) {
+	tags := worldTags(w)
+	keep := func(items []string) []string {
+		kept := []string{}
+		for _, item := range items {
+			entry := poolEntries[item]
+			allowed := true
+			for _, tag := range entry.Requires {
+				allowed = allowed && tags[tag]
+			}
+			for _, tag := range entry.Excludes {
+				allowed = allowed && !tags[tag]
+			}
+			if allowed {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	}
+
+	if w.Features = keep(w.Features); len(w.Features) < 2 {
+		w.Features = randomFeatures(rng, w.Climate)
+	}
+	if w.Fauna = keep(w.Fauna); len(w.Fauna) == 0 {
+		w.Fauna = weightedSample(rng, coherentPool(faunaByClimate, w.Theme, w.Climate), 2, w.Features)
+	}
+	if w.Dangers = keep(w.Dangers); len(w.Dangers) == 0 {
+		w.Dangers = weightedSample(rng, coherentPool(dangersByTheme, w.Theme, w.Climate), 1, w.Features)
+	}
+	w.Flora = keep(w.Flora)
+	w.Cultures = keep(w.Cultures)
+	w.Languages = keep(w.Languages)
+}
+
+// descriptionMentions lists what generateDescription names: the climate, the first two
+// features and the first creature and plant
+func descriptionMentions(w *models.World) []string {
+	mentions := []string{w.Climate}
+	for i, feature := range w.Features {
+		if i < 2 {
+			mentions = append(mentions, feature)
+		}
+	}
+	if len(w.Fauna) > 0 {
+		mentions = append(mentions, w.Fauna[0])
+	}
+	if len(w.Flora) > 0 {
+		mentions = append(mentions, w.Flora[0])
+	}
+	return mentions
+}
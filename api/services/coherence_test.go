@@ -0,0 +1,160 @@
+package services
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// coherentTestWorld builds a world whose issues have all been repaired
+func coherentTestWorld(t *testing.T, theme, climate string) *models.World {
+	t.Helper()
+	for seed := int64(1); seed < 100; seed++ {
+		w, _ := buildWorld(theme, &generateOptions{seed: &seed, climate: climate, population: &[2]int{1000, 1000}})
+		if report := applyCoherenceRules(nil, w, false); report.Valid {
+			return w
+		}
+	}
+	t.Fatalf("no coherent %s world with a %s climate", theme, climate)
+	return nil
+}
+
+// outsidePool returns an item of a pool that the other pool does not hold
+func outsidePool(t *testing.T, pool, other []string) string {
+	t.Helper()
+	for _, item := range pool {
+		if !containsString(other, item) {
+			return item
+		}
+	}
+	t.Fatal("every item of the pool is shared")
+	return ""
+}
+
+func TestCoherenceRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		climate string
+		// breaks the rule in a coherent world
+		breakRule func(t *testing.T, w *models.World)
+		rule      string
+		// repairable rules are fixed when repairs are allowed, the others stay flagged
+		repairable bool
+	}{
+		{
+			name: "features of another climate", theme: "fantasy", climate: "Arctic",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Features[0] = outsidePool(t, featuresByClimate["Tropical"], featuresByClimate["Arctic"])
+			},
+			rule: "features-match-climate", repairable: true,
+		},
+		{
+			name: "fauna of another climate", theme: "fantasy", climate: "Arid",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Fauna[0] = outsidePool(t, faunaByClimate["fantasy"]["Arctic"], faunaByClimate["fantasy"]["Arid"])
+			},
+			rule: "fauna-match-climate", repairable: true,
+		},
+		{
+			name: "culture of another theme", theme: "fantasy", climate: "Temperate",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Cultures[0] = outsidePool(t, culturesByTheme["sci-fi"], culturesByTheme["fantasy"])
+			},
+			rule: "cultures-match-theme", repairable: true,
+		},
+		{
+			name: "religion of a missing culture", theme: "fantasy", climate: "Temperate",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Religions = append(w.Religions, models.Religion{Name: "Cult of Nobody", Cultures: []string{"Nobody"}})
+			},
+			rule: "religions-follow-cultures", repairable: true,
+		},
+		{
+			name: "power system of another theme", theme: "sci-fi", climate: "Temperate",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.PowerSystem = &models.PowerSystem{Type: PowerSystemMagic}
+			},
+			rule: "power-system-matches-theme", repairable: true,
+		},
+		{
+			name: "description without the climate", theme: "fantasy", climate: "Temperate",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Description = "A world."
+			},
+			rule: "description-matches-lists", repairable: true,
+		},
+		{
+			name: "crowded polar world", theme: "fantasy", climate: "Polar",
+			breakRule: func(t *testing.T, w *models.World) {
+				w.Population = polarPopulationLimit + 1
+			},
+			rule: "population-fits-climate", repairable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := coherentTestWorld(t, tt.theme, tt.climate)
+			tt.breakRule(t, w)
+
+			checked := applyCoherenceRules(nil, w, false)
+			if checked.Valid || !hasIssue(checked, tt.rule, IssueFlagged) {
+				t.Fatalf("checking flagged %+v, want an issue of %s", checked.Issues, tt.rule)
+			}
+
+			repaired := applyCoherenceRules(rand.New(rand.NewSource(1)), w, true)
+			if tt.repairable {
+				if !hasIssue(repaired, tt.rule, IssueRepaired) {
+					t.Errorf("repairing reported %+v, want %s repaired", repaired.Issues, tt.rule)
+				}
+				if after := applyCoherenceRules(nil, w, false); hasIssue(after, tt.rule, IssueFlagged) {
+					t.Errorf("the repaired world still breaks %s: %+v", tt.rule, after.Issues)
+				}
+			} else if repaired.Valid || !hasIssue(repaired, tt.rule, IssueFlagged) {
+				t.Errorf("repairing reported %+v, want %s flagged", repaired.Issues, tt.rule)
+			}
+		})
+	}
+}
+
+func TestCheckWorldLeavesWorldUnchanged(t *testing.T) {
+	w := coherentTestWorld(t, "fantasy", "Temperate")
+	w.Description = "A world."
+
+	s := newTestWorldService()
+	if report := s.CheckWorld(w); report.Valid {
+		t.Fatal("CheckWorld() reported a valid world")
+	}
+	if w.Description != "A world." {
+		t.Errorf("CheckWorld() changed the description to %q", w.Description)
+	}
+}
+
+func TestGeneratedWorldsAreCoherent(t *testing.T) {
+	for _, theme := range []string{"fantasy", "sci-fi", "post-apocalyptic"} {
+		for _, climate := range []string{"Arid", "Temperate", "Tropical", "Arctic", "Oceanic", "Humid Subtropical"} {
+			for seed := int64(1); seed <= 20; seed++ {
+				w, report := buildWorld(theme, &generateOptions{seed: &seed, climate: climate, population: &[2]int{1000, 1000}})
+				for _, issue := range report.Issues {
+					if issue.Action == IssueFlagged {
+						t.Errorf("%s %s world %d flagged by %s: %s", theme, climate, seed, issue.Rule, issue.Message)
+					}
+				}
+				if after := applyCoherenceRules(nil, w, false); !after.Valid {
+					t.Errorf("%s %s world %d breaks rules after generation: %+v", theme, climate, seed, after.Issues)
+				}
+			}
+		}
+	}
+}
+
+func hasIssue(report *models.Diagnostics, rule, action string) bool {
+	for _, issue := range report.Issues {
+		if issue.Rule == rule && issue.Action == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Formats of the datasets worlds are exported to for analysis
+const (
+	DatasetFormatCSV     = "csv"
+	DatasetFormatParquet = "parquet"
+)
+
+// DatasetFormats lists the formats of the datasets
+var DatasetFormats = []string{DatasetFormatCSV, DatasetFormatParquet}
+
+// Layouts of the lists of the worlds in a dataset
+const (
+	// DatasetArraysJSON writes a row per world, with every list encoded as a JSON array
+	DatasetArraysJSON = "json"
+	// DatasetArraysExplode writes a row per item of the lists of a world, naming the list it belongs to
+	DatasetArraysExplode = "explode"
+)
+
+// DatasetArrays lists the layouts of the lists of the worlds in a dataset
+var DatasetArrays = []string{DatasetArraysJSON, DatasetArraysExplode}
+
+// Parquet rows are buffered until a row group is this large
+const datasetRowGroupSize = 8 << 20
+
+// Kinds of the values of the dataset columns
+const (
+	columnString = iota
+	columnInt
+	columnTime
+)
+
+// datasetColumn is a column of a dataset
+type datasetColumn struct {
+	name string
+	kind int
+	// optional columns are empty when the value is nil
+	optional bool
+}
+
+// Columns describing a world, first in every dataset
+var worldDatasetColumns = []datasetColumn{
+	{name: "id", kind: columnInt},
+	{name: "name", kind: columnString},
+	{name: "description", kind: columnString},
+	{name: "theme", kind: columnString},
+	{name: "climate", kind: columnString},
+	{name: "population", kind: columnInt},
+	{name: "seed", kind: columnInt},
+	{name: "system_id", kind: columnInt, optional: true},
+	{name: "created_at", kind: columnTime},
+}
+
+// Columns of the lists of a world in the JSON layout
+var jsonDatasetColumns = []datasetColumn{
+	{name: "features", kind: columnString},
+	{name: "fauna", kind: columnString},
+	{name: "flora", kind: columnString},
+	{name: "cultures", kind: columnString},
+	{name: "dangers", kind: columnString},
+	{name: "languages", kind: columnString},
+	{name: "religions", kind: columnString},
+	{name: "power_system", kind: columnString, optional: true},
+}
+
+// Columns of an item of a list in the exploded layout
+var explodedDatasetColumns = []datasetColumn{
+	{name: "list", kind: columnString},
+	{name: "position", kind: columnInt},
+	{name: "item", kind: columnString},
+	{name: "rarity", kind: columnString, optional: true},
+}
+
+// datasetWriter writes the rows of a dataset, one value per column
+type datasetWriter interface {
+	write(values []any) error
+	close() error
+}
+
+// DatasetWriter writes worlds to a dataset, streaming its rows to a writer
+type DatasetWriter struct {
+	rows   datasetWriter
+	arrays string
+}
+
+// ValidateDataset checks that worlds can be exported to a dataset format and layout
+func ValidateDataset(format, arrays string) error {
+	if !containsString(DatasetFormats, format) {
+		return newError(ErrValidation, "unknown dataset format %q, expected one of %s", format, strings.Join(DatasetFormats, ", "))
+	}
+	if !containsString(DatasetArrays, arrays) {
+		return newError(ErrValidation, "unknown arrays layout %q, expected one of %s", arrays, strings.Join(DatasetArrays, ", "))
+	}
+	return nil
+}
+
+// DatasetContentType returns the media type of a dataset format
+func DatasetContentType(format string) string {
+	if format == DatasetFormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=UTF-8"
+}
+
+// NewDatasetWriter starts a dataset in a format, laying out the lists of the worlds as JSON arrays or exploded rows.
+// The dataset is complete once the writer is closed.
+func NewDatasetWriter(out io.Writer, format, arrays string) (*DatasetWriter, error) {
+	if err := ValidateDataset(format, arrays); err != nil {
+		return nil, err
+	}
+
+	columns := append([]datasetColumn{}, worldDatasetColumns...)
+	if arrays == DatasetArraysExplode {
+		columns = append(columns, explodedDatasetColumns...)
+	} else {
+		columns = append(columns, jsonDatasetColumns...)
+	}
+
+	var rows datasetWriter
+	var err error
+	if format == DatasetFormatParquet {
+		rows, err = newParquetDataset(out, columns)
+	} else {
+		rows, err = newCSVDataset(out, columns)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DatasetWriter{rows: rows, arrays: arrays}, nil
+}
+
+// Write adds the rows of a world to the dataset. In the exploded layout, religions are listed by name
+// and a world without any list item still has a row, with empty list columns.
+func (d *DatasetWriter) Write(w *models.World) error {
+	var systemID any
+	if w.SystemID != nil {
+		systemID = int64(*w.SystemID)
+	}
+	world := []any{int64(w.ID), w.Name, w.Description, w.Theme, w.Climate, int64(w.Population), w.Seed, systemID, w.CreatedAt}
+
+	lists := worldDatasetLists(w)
+	if d.arrays == DatasetArraysJSON {
+		row := world
+		for _, list := range lists {
+			row = append(row, jsonArray(list.items))
+		}
+		powerSystem := any(nil)
+		if w.PowerSystem != nil {
+			encoded, err := json.Marshal(w.PowerSystem)
+			if err != nil {
+				return err
+			}
+			powerSystem = string(encoded)
+		}
+		return d.rows.write(append(row, jsonArray(w.Religions), powerSystem))
+	}
+
+	religions := make([]string, len(w.Religions))
+	for i, religion := range w.Religions {
+		religions[i] = religion.Name
+	}
+	lists = append(lists, datasetList{"religions", religions})
+
+	written := false
+	for _, list := range lists {
+		for i, item := range list.items {
+			rarity := any(nil)
+			if tier, ok := w.Rarities[item]; ok {
+				rarity = tier
+			}
+			if err := d.rows.write(append(world[:len(world):len(world)], list.name, int64(i), item, rarity)); err != nil {
+				return err
+			}
+			written = true
+		}
+	}
+	if !written {
+		return d.rows.write(append(world, "", int64(0), "", nil))
+	}
+	return nil
+}
+
+// Close completes the dataset
+func (d *DatasetWriter) Close() error {
+	return d.rows.close()
+}
+
+// datasetList is a named list of a world
+type datasetList struct {
+	name  string
+	items []string
+}
+
+// worldDatasetLists returns the lists of names of a world, in the order of the dataset columns
+func worldDatasetLists(w *models.World) []datasetList {
+	return []datasetList{
+		{"features", w.Features},
+		{"fauna", w.Fauna},
+		{"flora", w.Flora},
+		{"cultures", w.Cultures},
+		{"dangers", w.Dangers},
+		{"languages", w.Languages},
+	}
+}
+
+// jsonArray encodes a list as a JSON array, empty rather than null when the list is
+func jsonArray[T any](items []T) string {
+	if items == nil {
+		items = []T{}
+	}
+	encoded, _ := json.Marshal(items)
+	return string(encoded)
+}
+
+// csvDataset writes a dataset as CSV, with a header row
+type csvDataset struct {
+	out     *csv.Writer
+	columns []datasetColumn
+	record  []string
+}
+
+func newCSVDataset(out io.Writer, columns []datasetColumn) (*csvDataset, error) {
+	d := &csvDataset{out: csv.NewWriter(out), columns: columns, record: make([]string, len(columns))}
+	for i, column := range columns {
+		d.record[i] = column.name
+	}
+	return d, d.out.Write(d.record)
+}
+
+func (d *csvDataset) write(values []any) error {
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			d.record[i] = ""
+		case int64:
+			d.record[i] = strconv.FormatInt(v, 10)
+		case time.Time:
+			d.record[i] = v.UTC().Format(time.RFC3339)
+		default:
+			d.record[i] = fmt.Sprint(v)
+		}
+	}
+	return d.out.Write(d.record)
+}
+
+func (d *csvDataset) close() error {
+	d.out.Flush()
+	return d.out.Error()
+}
+
+// parquetDataset writes a dataset as a Parquet file, storing times as milliseconds
+type parquetDataset struct {
+	out *parquetWriter
+}
+
+func newParquetDataset(out io.Writer, columns []datasetColumn) (*parquetDataset, error) {
+	pw, err := newParquetWriter(out, columns, datasetRowGroupSize)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetDataset{out: pw}, nil
+}
+
+func (d *parquetDataset) write(values []any) error {
+	for i, value := range values {
+		if t, ok := value.(time.Time); ok {
+			values[i] = t.UnixMilli()
+		}
+	}
+	return d.out.writeRow(values)
+}
+
+func (d *parquetDataset) close() error {
+	return d.out.close()
+}
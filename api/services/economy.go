@@ -0,0 +1,550 @@
+package services
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for economy generation and simulation
+
+// MaxEconomyTurns caps the number of turns of a market simulation
+const MaxEconomyTurns = 100
+
+// priceShockThreshold is the relative price change reported as a shock
+const priceShockThreshold = 0.2
+
+// goodInfo describes a good that can be produced and traded
+type goodInfo struct {
+	Name      string
+	BasePrice float64
+	Staple    bool
+}
+
+// Goods yielded by each terrain, per theme
+var goodsByTerrain = map[string]map[string]goodInfo{
+	"fantasy": {
+		TerrainWater:     {"Fish", 4, true},
+		TerrainPlains:    {"Grain", 3, true},
+		TerrainForest:    {"Timber", 5, false},
+		TerrainHills:     {"Stone", 4, false},
+		TerrainMountains: {"Iron ore", 9, false},
+		TerrainDesert:    {"Salt", 7, false},
+		TerrainIce:       {"Furs", 12, false},
+		TerrainSwamp:     {"Alchemical herbs", 14, false},
+		TerrainJungle:    {"Spices", 16, false},
+	},
+	"sci-fi": {
+		TerrainWater:     {"Hydrogen", 6, false},
+		TerrainPlains:    {"Protein crops", 4, true},
+		TerrainForest:    {"Biopolymers", 8, false},
+		TerrainHills:     {"Silicates", 5, false},
+		TerrainMountains: {"Rare metals", 18, false},
+		TerrainDesert:    {"Solar cells", 10, false},
+		TerrainIce:       {"Deuterium", 20, false},
+		TerrainSwamp:     {"Biomass", 3, true},
+		TerrainJungle:    {"Gene samples", 22, false},
+	},
+	"post-apocalyptic": {
+		TerrainWater:     {"Clean water", 8, true},
+		TerrainPlains:    {"Corn", 4, true},
+		TerrainForest:    {"Firewood", 3, false},
+		TerrainHills:     {"Scrap metal", 6, false},
+		TerrainMountains: {"Fuel", 15, false},
+		TerrainDesert:    {"Salvage", 9, false},
+		TerrainIce:       {"Preserved food", 10, true},
+		TerrainSwamp:     {"Medicine", 20, false},
+		TerrainJungle:    {"Fruit", 5, true},
+	},
+}
+
+// Goods found near specific world features
+var goodsByFeature = map[string]goodInfo{
+	"Vineyards":                 {"Wine", 14, false},
+	"Century-old olive trees":   {"Olive oil", 12, false},
+	"Tea plantations":           {"Tea", 13, false},
+	"Rice terraces":             {"Rice", 4, true},
+	"Salt flats":                {"Salt", 7, false},
+	"Coral reefs":               {"Pearls", 25, false},
+	"Hot springs":               {"Sulfur", 8, false},
+	"Obsidian fields":           {"Obsidian", 11, false},
+	"Fossil beds":               {"Fossils", 18, false},
+	"Peat bogs":                 {"Peat", 3, false},
+	"Bamboo groves":             {"Bamboo", 4, false},
+	"Medicinal plants":          {"Medicinal herbs", 15, false},
+	"Ancient meteorite craters": {"Meteoric iron", 30, false},
+	"Rich farmland":             {"Grain", 3, true},
+	"Lianas":                    {"Rope fiber", 6, false},
+	"Isolated oases":            {"Dates", 6, true},
+}
+
+// Size of the workforce of each kind of settlement
+var settlementWorkforce = map[string]int{
+	"village": 1,
+	"town":    2,
+	"city":    3,
+}
+
+// economyModel holds the state used to price goods and simulate the market
+type economyModel struct {
+	goods       []goodInfo
+	settlements []models.Settlement
+	production  []map[string]int
+	demand      []map[string]int
+}
+
+// GenerateEconomy builds the economy of a world and, when turns > 0, simulates its market
+func (s *WorldService) GenerateEconomy(w *models.World, turns int) *models.Economy {
+	m := generateWorldMap(w)
+	rng := rand.New(rand.NewSource(worldSeed(w, "economy")))
+
+	deposits := placeDeposits(rng, m, w)
+	model := newEconomyModel(m, w, deposits)
+	prices := model.marketPrices(nil)
+
+	economy := &models.Economy{
+		Map:         m.toModel(),
+		Resources:   deposits,
+		Production:  model.settlementEconomies(prices),
+		Goods:       model.goodsReport(prices, nil),
+		TradeRoutes: model.tradeRoutes(m),
+	}
+
+	if turns > 0 {
+		if turns > MaxEconomyTurns {
+			turns = MaxEconomyTurns
+		}
+		economy.Simulation = model.simulate(rng, turns, prices)
+	}
+
+	return economy
+}
+
+// placeDeposits scatters terrain resources on the map and adds the goods of the world's features
+func placeDeposits(rng *rand.Rand, m *worldMap, w *models.World) []models.ResourceDeposit {
+	themeGoods := themeGoodsTable(w.Theme)
+
+	var deposits []models.ResourceDeposit
+	var land []models.Point
+	for y, row := range m.tiles {
+		for x, t := range row {
+			p := models.Point{X: x, Y: y}
+			if t != TerrainWater {
+				land = append(land, p)
+			}
+			if rng.Float64() < 0.12 {
+				deposits = append(deposits, models.ResourceDeposit{
+					Good:     themeGoods[t].Name,
+					Position: p,
+					Richness: 1 + rng.Intn(3),
+				})
+			}
+		}
+	}
+
+	for _, feature := range w.Features {
+		good, ok := goodsByFeature[feature]
+		if !ok || len(land) == 0 {
+			continue
+		}
+		for i := 0; i < 2+rng.Intn(2); i++ {
+			deposits = append(deposits, models.ResourceDeposit{
+				Good:     good.Name,
+				Position: land[rng.Intn(len(land))],
+				Richness: 2 + rng.Intn(2),
+			})
+		}
+	}
+
+	return deposits
+}
+
+// newEconomyModel computes the production and demand of every settlement
+func newEconomyModel(m *worldMap, w *models.World, deposits []models.ResourceDeposit) *economyModel {
+	model := &economyModel{settlements: m.settlements}
+
+	themeGoods := themeGoodsTable(w.Theme)
+
+	// Only goods found in this world are traded, apart from staples that are always in demand
+	present := make(map[string]bool)
+	for _, d := range deposits {
+		present[d.Good] = true
+	}
+	for _, st := range m.settlements {
+		present[themeGoods[st.Terrain].Name] = true
+	}
+
+	// Collect the goods traded in this world, keeping a stable order
+	seen := make(map[string]bool)
+	addGood := func(g goodInfo) {
+		if !seen[g.Name] && (present[g.Name] || g.Staple) {
+			seen[g.Name] = true
+			model.goods = append(model.goods, g)
+		}
+	}
+	for _, t := range sortedKeys(themeGoods) {
+		addGood(themeGoods[t])
+	}
+	for _, feature := range w.Features {
+		if g, ok := goodsByFeature[feature]; ok {
+			addGood(g)
+		}
+	}
+
+	for _, st := range m.settlements {
+		workforce := settlementWorkforce[st.Size]
+		production := make(map[string]int)
+		production[themeGoods[st.Terrain].Name] += workforce
+		for _, d := range deposits {
+			if chebyshevDistance(st.Position, d.Position) <= 3 {
+				production[d.Good] += d.Richness * workforce
+			}
+		}
+
+		demand := make(map[string]int)
+		for _, g := range model.goods {
+			need := 1
+			if g.Staple {
+				need = 3
+			}
+			demand[g.Name] = need * workforce
+		}
+
+		model.production = append(model.production, production)
+		model.demand = append(model.demand, demand)
+	}
+
+	return model
+}
+
+// marketPrices computes the price of every good from its global supply and demand,
+// applying optional production modifiers per settlement and good
+func (e *economyModel) marketPrices(modifiers []map[string]float64) map[string]float64 {
+	prices := make(map[string]float64, len(e.goods))
+	for _, g := range e.goods {
+		supply, demand := e.totals(g.Name, modifiers)
+		prices[g.Name] = scarcityPrice(g.BasePrice, supply, demand, 0.8, 0.25, 5)
+	}
+	return prices
+}
+
+// totals returns the global supply and demand of a good
+func (e *economyModel) totals(good string, modifiers []map[string]float64) (int, int) {
+	supply, demand := 0, 0
+	for i := range e.settlements {
+		produced := float64(e.production[i][good])
+		if modifiers != nil {
+			produced *= modifiers[i][good]
+		}
+		supply += int(math.Round(produced))
+		demand += e.demand[i][good]
+	}
+	return supply, demand
+}
+
+// goodsReport lists the goods with their current prices and scarcity
+func (e *economyModel) goodsReport(prices map[string]float64, modifiers []map[string]float64) []models.Good {
+	goods := make([]models.Good, 0, len(e.goods))
+	for _, g := range e.goods {
+		supply, demand := e.totals(g.Name, modifiers)
+
+		scarcity := "balanced"
+		ratio := float64(demand+1) / float64(supply+1)
+		if ratio > 1.5 {
+			scarcity = "scarce"
+		} else if ratio < 0.67 {
+			scarcity = "abundant"
+		}
+
+		goods = append(goods, models.Good{
+			Name:      g.Name,
+			BasePrice: g.BasePrice,
+			Price:     prices[g.Name],
+			Supply:    supply,
+			Demand:    demand,
+			Scarcity:  scarcity,
+		})
+	}
+	return goods
+}
+
+// settlementEconomies reports production, demand and local prices per settlement
+func (e *economyModel) settlementEconomies(prices map[string]float64) []models.SettlementEconomy {
+	result := make([]models.SettlementEconomy, 0, len(e.settlements))
+	for i, st := range e.settlements {
+		local := make(map[string]float64, len(e.goods))
+		for _, g := range e.goods {
+			// Local scarcity moves prices around the market price, within a narrower band
+			local[g.Name] = scarcityPrice(prices[g.Name], e.production[i][g.Name], e.demand[i][g.Name], 0.3, 0.5, 2)
+		}
+
+		result = append(result, models.SettlementEconomy{
+			Settlement: st.Name,
+			Production: e.production[i],
+			Demand:     e.demand[i],
+			Prices:     local,
+		})
+	}
+	return result
+}
+
+// tradeRoutes connects settlements whose surpluses cover each other's shortages
+func (e *economyModel) tradeRoutes(m *worldMap) []models.TradeRoute {
+	routes := []models.TradeRoute{}
+	for i := 0; i < len(e.settlements); i++ {
+		for j := i + 1; j < len(e.settlements); j++ {
+			outbound := e.tradeableGoods(i, j)
+			inbound := e.tradeableGoods(j, i)
+			if len(outbound) == 0 && len(inbound) == 0 {
+				continue
+			}
+
+			path, cost := leastCostPath(m, e.settlements[i].Position, e.settlements[j].Position)
+			if path == nil {
+				continue
+			}
+
+			routes = append(routes, models.TradeRoute{
+				From:     e.settlements[i].Name,
+				To:       e.settlements[j].Name,
+				Outbound: outbound,
+				Inbound:  inbound,
+				Cost:     cost,
+				Path:     path,
+			})
+		}
+	}
+	return routes
+}
+
+// tradeableGoods lists the goods settlement from has in surplus and settlement to lacks
+func (e *economyModel) tradeableGoods(from, to int) []string {
+	goods := []string{}
+	for _, g := range e.goods {
+		surplus := e.production[from][g.Name] - e.demand[from][g.Name]
+		shortage := e.demand[to][g.Name] - e.production[to][g.Name]
+		if surplus > 0 && shortage > 0 {
+			goods = append(goods, g.Name)
+		}
+	}
+	return goods
+}
+
+// marketEventKinds are the events that can disturb production during a simulation
+var marketEventKinds = []struct {
+	Type       string
+	Staples    bool
+	Multiplier float64
+	Template   string
+}{
+	{"drought", true, 0.4, "A drought ruins the harvests of %s"},
+	{"bumper harvest", true, 1.6, "%s brings in a bumper harvest"},
+	{"mine collapse", false, 0.3, "A collapse halts the workings near %s"},
+	{"new deposit", false, 1.5, "Prospectors find a rich new deposit near %s"},
+	{"raid", false, 0.5, "Raiders sack the stores of %s"},
+}
+
+// simulate advances the market turn by turn, reporting events and price shocks
+func (e *economyModel) simulate(rng *rand.Rand, turns int, prices map[string]float64) *models.MarketSimulation {
+	sim := &models.MarketSimulation{
+		Turns:  turns,
+		Events: []models.MarketEvent{},
+		Shocks: []models.PriceShock{},
+	}
+	if len(e.settlements) == 0 {
+		sim.FinalPrices = prices
+		return sim
+	}
+
+	modifiers := make([]map[string]float64, len(e.settlements))
+	for i := range modifiers {
+		modifiers[i] = make(map[string]float64, len(e.goods))
+		for _, g := range e.goods {
+			modifiers[i][g.Name] = 1
+		}
+	}
+
+	for turn := 1; turn <= turns; turn++ {
+		// Production slowly recovers from past events
+		for i := range modifiers {
+			for good, m := range modifiers[i] {
+				modifiers[i][good] = m + (1-m)*0.3
+			}
+		}
+
+		causes := make(map[string]string)
+		if rng.Float64() < 0.35 {
+			event := e.randomEvent(rng, turn, modifiers)
+			sim.Events = append(sim.Events, event)
+			for _, good := range event.Goods {
+				causes[good] = event.Description
+			}
+		}
+
+		next := e.marketPrices(modifiers)
+		for _, g := range e.goods {
+			old, current := prices[g.Name], next[g.Name]
+			if old == 0 {
+				continue
+			}
+			change := (current - old) / old
+			if math.Abs(change) < priceShockThreshold {
+				continue
+			}
+
+			cause, ok := causes[g.Name]
+			if !ok {
+				cause = "Market correction"
+			}
+			sim.Shocks = append(sim.Shocks, models.PriceShock{
+				Turn:     turn,
+				Good:     g.Name,
+				OldPrice: old,
+				NewPrice: current,
+				Change:   math.Round(change*1000) / 10,
+				Cause:    cause,
+			})
+		}
+		prices = next
+	}
+
+	sim.FinalPrices = prices
+	return sim
+}
+
+// randomEvent applies a random production event to one settlement
+func (e *economyModel) randomEvent(rng *rand.Rand, turn int, modifiers []map[string]float64) models.MarketEvent {
+	kind := marketEventKinds[rng.Intn(len(marketEventKinds))]
+	idx := rng.Intn(len(e.settlements))
+
+	var affected []string
+	for _, g := range e.goods {
+		if e.production[idx][g.Name] == 0 {
+			continue
+		}
+		// Raids hit everything, the other events only their kind of goods
+		if kind.Type == "raid" || g.Staple == kind.Staples {
+			modifiers[idx][g.Name] *= kind.Multiplier
+			affected = append(affected, g.Name)
+		}
+	}
+	if affected == nil {
+		affected = []string{}
+	}
+
+	name := e.settlements[idx].Name
+	return models.MarketEvent{
+		Turn:        turn,
+		Type:        kind.Type,
+		Settlement:  name,
+		Goods:       affected,
+		Description: fmt.Sprintf(kind.Template, name),
+	}
+}
+
+// scarcityPrice scales a base price by the demand to supply ratio, within the given bounds
+func scarcityPrice(base float64, supply, demand int, elasticity, minFactor, maxFactor float64) float64 {
+	ratio := float64(demand+1) / float64(supply+1)
+	factor := math.Max(minFactor, math.Min(maxFactor, math.Pow(ratio, elasticity)))
+	return math.Round(base*factor*100) / 100
+}
+
+// themeGoodsTable returns the terrain goods of a theme
+func themeGoodsTable(theme string) map[string]goodInfo {
+	goods := goodsByTerrain[theme]
+	if goods == nil {
+		goods = goodsByTerrain["fantasy"]
+	}
+	return goods
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pathNode is an entry of the least-cost path priority queue
+type pathNode struct {
+	point models.Point
+	cost  int
+}
+
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// leastCostPath finds the cheapest path between two tiles over the terrain using Dijkstra's algorithm
+func leastCostPath(m *worldMap, from, to models.Point) ([]models.Point, int) {
+	const unvisited = math.MaxInt32
+
+	costs := make([][]int, mapHeight)
+	previous := make([][]models.Point, mapHeight)
+	for y := range costs {
+		costs[y] = make([]int, mapWidth)
+		previous[y] = make([]models.Point, mapWidth)
+		for x := range costs[y] {
+			costs[y][x] = unvisited
+		}
+	}
+
+	costs[from.Y][from.X] = 0
+	queue := &pathQueue{{point: from}}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(pathNode)
+		if node.point == to {
+			break
+		}
+		if node.cost > costs[node.point.Y][node.point.X] {
+			continue
+		}
+
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				next := models.Point{X: node.point.X + dx, Y: node.point.Y + dy}
+				if (dx == 0 && dy == 0) || next.X < 0 || next.Y < 0 || next.X >= mapWidth || next.Y >= mapHeight {
+					continue
+				}
+
+				cost := node.cost + terrainCosts[m.tiles[next.Y][next.X]]
+				if cost < costs[next.Y][next.X] {
+					costs[next.Y][next.X] = cost
+					previous[next.Y][next.X] = node.point
+					heap.Push(queue, pathNode{point: next, cost: cost})
+				}
+			}
+		}
+	}
+
+	if costs[to.Y][to.X] == unvisited {
+		return nil, 0
+	}
+
+	path := []models.Point{to}
+	for p := to; p != from; {
+		p = previous[p.Y][p.X]
+		path = append(path, p)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, costs[to.Y][to.X]
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// newTestMap returns a map of plains, with the terrain of some tiles replaced
+func newTestMap(terrain map[models.Point]string) *worldMap {
+	m := &worldMap{tiles: make([][]string, mapHeight)}
+	for y := range m.tiles {
+		m.tiles[y] = make([]string, mapWidth)
+		for x := range m.tiles[y] {
+			m.tiles[y][x] = TerrainPlains
+		}
+	}
+	for p, t := range terrain {
+		m.tiles[p.Y][p.X] = t
+	}
+	return m
+}
+
+// column returns the tiles of a column between two rows, inclusive
+func column(x, fromY, toY int, terrain string) map[models.Point]string {
+	tiles := make(map[models.Point]string)
+	for y := fromY; y <= toY; y++ {
+		tiles[models.Point{X: x, Y: y}] = terrain
+	}
+	return tiles
+}
+
+func TestLeastCostPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		terrain  map[models.Point]string
+		from, to models.Point
+		wantCost int
+	}{
+		{"same tile", nil, models.Point{X: 3, Y: 3}, models.Point{X: 3, Y: 3}, 0},
+		{"straight line", nil, models.Point{X: 0, Y: 0}, models.Point{X: 6, Y: 0}, 6},
+		{"diagonal steps cost one", nil, models.Point{X: 0, Y: 0}, models.Point{X: 5, Y: 3}, 5},
+		{"crosses a wall it cannot avoid", column(2, 0, mapHeight-1, TerrainMountains), models.Point{X: 0, Y: 0}, models.Point{X: 4, Y: 0}, 11},
+		// Going around the wall through the gap at the bottom costs less than the 8 of a mountain
+		{"goes around a wall", column(2, 0, 3, TerrainMountains), models.Point{X: 0, Y: 0}, models.Point{X: 4, Y: 0}, 8},
+		{"crosses a swamp column once", column(3, 0, mapHeight-1, TerrainSwamp), models.Point{X: 0, Y: 5}, models.Point{X: 6, Y: 5}, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMap(tt.terrain)
+			path, cost := leastCostPath(m, tt.from, tt.to)
+			if cost != tt.wantCost {
+				t.Errorf("leastCostPath() cost = %d, want %d", cost, tt.wantCost)
+			}
+			checkPath(t, m, path, tt.from, tt.to, cost)
+		})
+	}
+}
+
+// The costs match an exhaustive relaxation of every tile on random terrain
+func TestLeastCostPathIsOptimal(t *testing.T) {
+	terrains := sortedKeys(terrainCosts)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		m := newTestMap(nil)
+		for y := range m.tiles {
+			for x := range m.tiles[y] {
+				m.tiles[y][x] = terrains[rng.Intn(len(terrains))]
+			}
+		}
+		from := models.Point{X: rng.Intn(mapWidth), Y: rng.Intn(mapHeight)}
+		to := models.Point{X: rng.Intn(mapWidth), Y: rng.Intn(mapHeight)}
+
+		path, cost := leastCostPath(m, from, to)
+		if want := relaxedCost(m, from, to); cost != want {
+			t.Errorf("map %d: leastCostPath() cost = %d, want %d", i, cost, want)
+		}
+		checkPath(t, m, path, from, to, cost)
+	}
+}
+
+// checkPath verifies that the path joins the tiles one step at a time and costs what was reported
+func checkPath(t *testing.T, m *worldMap, path []models.Point, from, to models.Point, cost int) {
+	t.Helper()
+	if len(path) == 0 || path[0] != from || path[len(path)-1] != to {
+		t.Fatalf("path %v does not join %v to %v", path, from, to)
+	}
+	total := 0
+	for i := 1; i < len(path); i++ {
+		if chebyshevDistance(path[i-1], path[i]) != 1 {
+			t.Fatalf("path jumps from %v to %v", path[i-1], path[i])
+		}
+		total += terrainCosts[m.tiles[path[i].Y][path[i].X]]
+	}
+	if total != cost {
+		t.Errorf("path costs %d, reported %d", total, cost)
+	}
+}
+
+// relaxedCost computes the cheapest cost by relaxing every tile until nothing changes
+func relaxedCost(m *worldMap, from, to models.Point) int {
+	const unknown = 1 << 30
+	costs := make([][]int, mapHeight)
+	for y := range costs {
+		costs[y] = make([]int, mapWidth)
+		for x := range costs[y] {
+			costs[y][x] = unknown
+		}
+	}
+	costs[from.Y][from.X] = 0
+
+	for changed := true; changed; {
+		changed = false
+		for y := 0; y < mapHeight; y++ {
+			for x := 0; x < mapWidth; x++ {
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						px, py := x+dx, y+dy
+						if (dx == 0 && dy == 0) || px < 0 || py < 0 || px >= mapWidth || py >= mapHeight || costs[py][px] == unknown {
+							continue
+						}
+						if c := costs[py][px] + terrainCosts[m.tiles[y][x]]; c < costs[y][x] {
+							costs[y][x] = c
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return costs[to.Y][to.X]
+}
+
+func TestGenerateEconomyIsSeeded(t *testing.T) {
+	s := newTestWorldService()
+	for i, theme := range []string{"fantasy", "sci-fi", "post-apocalyptic"} {
+		seed := int64(i + 1)
+		w, _ := buildWorld(theme, &generateOptions{seed: &seed})
+		w.ID = i + 1
+
+		for _, turns := range []int{0, 12} {
+			first, second := s.GenerateEconomy(w, turns), s.GenerateEconomy(w, turns)
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("%s world over %d turns yields different economies", theme, turns)
+			}
+		}
+
+		// The economy follows the world it belongs to
+		other := *w
+		other.ID += 100
+		if reflect.DeepEqual(s.GenerateEconomy(w, 12), s.GenerateEconomy(&other, 12)) {
+			t.Errorf("%s worlds %d and %d share an economy", theme, w.ID, other.ID)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder writes resources in a media type
+type Encoder interface {
+	// MediaType is the media type the encoder writes, without parameters
+	MediaType() string
+	// ContentType is the value of the Content-Type header of the encoded resources
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+// encoder is an Encoder made of its media type and encoding function
+type encoder struct {
+	mediaType   string
+	contentType string
+	encode      func(w io.Writer, v any) error
+}
+
+func (e encoder) MediaType() string               { return e.mediaType }
+func (e encoder) ContentType() string             { return e.contentType }
+func (e encoder) Encode(w io.Writer, v any) error { return e.encode(w, v) }
+
+// Encoders of the resources, in order of preference. JSON comes first, answering clients that accept anything.
+var encoders []Encoder
+
+func init() {
+	RegisterEncoder(encoder{"application/json", "application/json; charset=UTF-8", encodeJSON})
+	RegisterEncoder(encoder{"application/yaml", "application/yaml; charset=UTF-8", encodeYAML})
+	RegisterEncoder(encoder{"application/x-ndjson", "application/x-ndjson; charset=UTF-8", encodeNDJSON})
+	RegisterEncoder(encoder{"text/markdown", "text/markdown; charset=UTF-8", encodeMarkdown})
+	RegisterEncoder(encoder{"application/xml", "application/xml; charset=UTF-8", encodeXML})
+	RegisterEncoder(encoder{"application/msgpack", "application/msgpack", encodeMsgpack})
+}
+
+// RegisterEncoder makes an encoder available to the negotiation of resources, replacing the encoder of the same media type
+func RegisterEncoder(e Encoder) {
+	for i, registered := range encoders {
+		if registered.MediaType() == e.MediaType() {
+			encoders[i] = e
+			return
+		}
+	}
+	encoders = append(encoders, e)
+}
+
+// EncoderMediaTypes lists the media types resources can be encoded to
+func EncoderMediaTypes() []string {
+	types := make([]string, len(encoders))
+	for i, e := range encoders {
+		types[i] = e.MediaType()
+	}
+	return types
+}
+
+// NegotiateEncoder picks the encoder of the media type an Accept header prefers, JSON when the header is empty.
+// It reports false when no encoder matches the media types accepted.
+func NegotiateEncoder(accept string) (Encoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0], true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []mediaRange
+	// Media types refused with a quality of 0 are not picked through a wildcard either
+	refused := make(map[string]bool)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		r := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(mediaType)), quality: 1}
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.EqualFold(strings.TrimSpace(name), "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					r.quality = q
+				}
+			}
+		}
+		if r.quality <= 0 {
+			refused[r.mediaType] = true
+		} else if r.mediaType != "" {
+			ranges = append(ranges, r)
+		}
+	}
+	// Ranges of the same quality keep the order of the header
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].quality > ranges[j].quality })
+
+	for _, r := range ranges {
+		for _, e := range encoders {
+			if mediaTypeMatches(r.mediaType, e.MediaType()) && !refused[e.MediaType()] {
+				return e, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// mediaTypeMatches reports whether a media range such as text/* covers a media type
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	kind, ok := strings.CutSuffix(mediaRange, "/*")
+	return ok && strings.HasPrefix(mediaType, kind+"/")
+}
+
+// errUnencodable is returned by the encoders that cannot represent a kind of resource
+var errUnencodable = errors.New("resource cannot be encoded to this media type")
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// encodeYAML writes the JSON representation of a resource as YAML, so that its fields keep their JSON names and order
+func encodeYAML(w io.Writer, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	clearYAMLStyle(&doc)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearYAMLStyle drops the flow style and quotes read from JSON, so that the document is written in block style
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// encodeNDJSON writes a line per world of a page, or a single line for any other resource
+func encodeNDJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	if page, ok := v.(models.PaginatedWorldsResponse); ok {
+		for _, world := range page.Data {
+			if err := encoder.Encode(world); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return encoder.Encode(v)
+}
+
+// Dossier template of the Markdown representation of worlds
+var markdownWorldTemplate = template.Must(template.New(markdownTemplateName).Funcs(dossierFuncs).Parse(readTemplate("", markdownTemplateName)))
+
+// encodeMarkdown writes worlds as their built-in Markdown dossiers, without their locations
+func encodeMarkdown(w io.Writer, v any) error {
+	var worlds []models.World
+	switch resource := v.(type) {
+	case *models.World:
+		worlds = []models.World{*resource}
+	case models.PaginatedWorldsResponse:
+		worlds = resource.Data
+		fmt.Fprintf(w, "*%d of %d worlds, from offset %d*\n\n", len(worlds), resource.Total, resource.Offset)
+	default:
+		return errUnencodable
+	}
+
+	exportedAt := time.Now()
+	for i := range worlds {
+		if err := markdownWorldTemplate.Execute(w, Dossier{World: &worlds[i], ExportedAt: exportedAt}); err != nil {
+			return err
+		}
+		if i < len(worlds)-1 {
+			io.WriteString(w, "\n")
+		}
+	}
+	return nil
+}
+
+func encodeMsgpack(w io.Writer, v any) error {
+	encoder := msgpack.NewEncoder(w)
+	// Fields keep their JSON names, like the other encodings
+	encoder.SetCustomStructTag("json")
+	return encoder.Encode(v)
+}
+
+// Names of XML elements that can be written as is
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// encodeXML writes the JSON representation of a resource as XML. Fields become elements named like the JSON fields,
+// items of lists become item elements and keys that are not valid names become entry elements with a key attribute.
+func encodeXML(w io.Writer, v any) error {
+	root := "response"
+	switch v.(type) {
+	case *models.World:
+		root = "world"
+	case models.PaginatedWorldsResponse:
+		root = "worlds"
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := writeXMLValue(&buf, decoder, xml.StartElement{Name: xml.Name{Local: root}}); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// writeXMLValue writes the next JSON value of the decoder as an element
+func writeXMLValue(buf *bytes.Buffer, decoder *json.Decoder, start xml.StartElement) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	writeXMLStart(buf, start)
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			for decoder.More() {
+				if err := writeXMLValue(buf, decoder, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+					return err
+				}
+			}
+		} else {
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key := keyToken.(string)
+				child := xml.StartElement{Name: xml.Name{Local: key}}
+				if !xmlName.MatchString(key) || strings.HasPrefix(strings.ToLower(key), "xml") {
+					child = xml.StartElement{Name: xml.Name{Local: "entry"}, Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}}}
+				}
+				if err := writeXMLValue(buf, decoder, child); err != nil {
+					return err
+				}
+			}
+		}
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		xml.EscapeText(buf, []byte(fmt.Sprint(t)))
+	}
+	fmt.Fprintf(buf, "</%s>", start.Name.Local)
+	return nil
+}
+
+func writeXMLStart(buf *bytes.Buffer, start xml.StartElement) {
+	buf.WriteString("<" + start.Name.Local)
+	for _, attr := range start.Attr {
+		buf.WriteString(" " + attr.Name.Local + `="`)
+		xml.EscapeText(buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+func TestNegotiateEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		// empty when no encoder should match
+		want string
+	}{
+		{"empty header", "", "application/json"},
+		{"blank header", "  ", "application/json"},
+		{"anything", "*/*", "application/json"},
+		{"exact type", "application/yaml", "application/yaml"},
+		{"case and spaces", " Application/YAML ", "application/yaml"},
+		{"parameters", "application/xml; charset=UTF-8", "application/xml"},
+		{"first of equal qualities", "text/markdown, application/yaml", "text/markdown"},
+		{"highest quality", "application/yaml;q=0.5, application/msgpack;q=0.8", "application/msgpack"},
+		{"quality with spaces", "application/yaml ; q = 0.9, text/markdown; q=0.1", "application/yaml"},
+		{"uppercase quality", "application/yaml;Q=0.1, application/xml", "application/xml"},
+		{"default quality is 1", "application/yaml;q=0.9, application/x-ndjson", "application/x-ndjson"},
+		{"invalid quality is ignored", "application/yaml;q=high, application/xml;q=0.9", "application/yaml"},
+		{"subtype wildcard", "text/*", "text/markdown"},
+		{"wildcard below a type", "*/*;q=0.1, application/msgpack", "application/msgpack"},
+		{"refused type is skipped by wildcards", "application/json;q=0, */*", "application/yaml"},
+		{"refused type is skipped by subtype wildcards", "application/*, application/json;q=0", "application/yaml"},
+		{"only refused types", "application/json;q=0", ""},
+		{"unknown type", "image/png", ""},
+		{"unknown type with fallback", "image/png, application/xml;q=0.2", "application/xml"},
+		{"empty ranges", ",;q=1,", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := NegotiateEncoder(tt.accept)
+			if ok != (tt.want != "") {
+				t.Fatalf("NegotiateEncoder(%q) ok = %v, want a match %v", tt.accept, ok, tt.want != "")
+			}
+			if ok && e.MediaType() != tt.want {
+				t.Errorf("NegotiateEncoder(%q) = %s, want %s", tt.accept, e.MediaType(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	w := &models.World{ID: 3, Name: "Aster & Brin", Theme: "fantasy", Rarities: map[string]string{"1st tower": "rare"}}
+	page := models.PaginatedWorldsResponse{Data: []models.World{*w, *w}, Total: 2}
+
+	tests := []struct {
+		mediaType string
+		resource  any
+		// fragments the output should hold
+		want []string
+	}{
+		{"application/json", w, []string{`"id":3`, `"1st tower":"rare"`}},
+		{"application/yaml", w, []string{"name: Aster & Brin\n", "rarities:\n  1st tower: rare\n"}},
+		{"application/x-ndjson", page, []string{`"id":3`}},
+		{"text/markdown", page, []string{"*2 of 2 worlds, from offset 0*", "Aster & Brin"}},
+		{"application/xml", w, []string{"<world>", "<name>Aster &amp; Brin</name>", `<entry key="1st tower">rare</entry>`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mediaType, func(t *testing.T) {
+			e, ok := NegotiateEncoder(tt.mediaType)
+			if !ok {
+				t.Fatalf("no encoder of %s", tt.mediaType)
+			}
+			var out bytes.Buffer
+			if err := e.Encode(&out, tt.resource); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("Encode() = %s, want it to hold %q", out.String(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("ndjson lines", func(t *testing.T) {
+		e, _ := NegotiateEncoder("application/x-ndjson")
+		var out bytes.Buffer
+		if err := e.Encode(&out, page); err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(out.String(), "\n"); lines != len(page.Data) {
+			t.Errorf("Encode() wrote %d lines, want %d", lines, len(page.Data))
+		}
+	})
+
+	t.Run("markdown of other resources", func(t *testing.T) {
+		e, _ := NegotiateEncoder("text/markdown")
+		if err := e.Encode(&bytes.Buffer{}, map[string]string{"error": "not found"}); err != errUnencodable {
+			t.Errorf("Encode() error = %v, want errUnencodable", err)
+		}
+	})
+}
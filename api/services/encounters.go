@@ -0,0 +1,283 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for encounter table generation
+
+const encounterDice = "d100"
+
+// How plentiful wildlife is in each climate, as the base weight of a fauna entry
+var faunaAbundanceByClimate = map[string]int{
+	"Arid": 6, "Temperate": 10, "Tropical": 12, "Arctic": 5, "Mediterranean": 9,
+	"Alpine": 7, "Oceanic": 9, "Continental": 9, "Monsoonal": 11, "Polar": 4,
+	"Desert": 5, "Savanna": 11, "Rainforest": 13, "Tundra": 5, "Humid Subtropical": 11,
+}
+
+// Base weight of a danger entry; wastelands are more dangerous than the other themes
+var dangerWeightByTheme = map[string]int{
+	"fantasy":          3,
+	"sci-fi":           3,
+	"post-apocalyptic": 5,
+}
+
+// Climates where survival is harder, making dangers more common
+var harshClimates = []string{"Arid", "Arctic", "Polar", "Desert", "Tundra"}
+
+var terrainEncounters = map[string][]string{
+	TerrainWater:     {"Sudden squall", "Drifting wreckage"},
+	TerrainPlains:    {"Stampede in the distance", "Abandoned campsite"},
+	TerrainForest:    {"Lost trail", "Fallen tree blocking the path"},
+	TerrainHills:     {"Hidden cave entrance", "Rockfall"},
+	TerrainMountains: {"Rockslide", "Narrow ledge over a chasm"},
+	TerrainDesert:    {"Sandstorm", "Bleached remains of a caravan"},
+	TerrainIce:       {"Thin ice", "Whiteout"},
+	TerrainSwamp:     {"Sinking ground", "Swarm of biting insects"},
+	TerrainJungle:    {"Impassable undergrowth", "Collapsed rope bridge"},
+}
+
+var faunaQuantities = []string{"1", "1d4", "1d6", "2d6"}
+
+// GenerateEncounterTables builds the d100 encounter table of a region of the world,
+// or of every region when no region is given
+func (s *WorldService) GenerateEncounterTables(w *models.World, regionName string) ([]models.EncounterTable, error) {
+	m := generateWorldMap(w)
+	if regionName != "" {
+		region, err := m.regionOrFirst(regionName)
+		if err != nil {
+			return nil, err
+		}
+		return []models.EncounterTable{encounterTable(w, region)}, nil
+	}
+
+	tables := make([]models.EncounterTable, 0, len(m.regions))
+	for _, region := range m.regions {
+		tables = append(tables, encounterTable(w, region))
+	}
+	return tables, nil
+}
+
+// RollEncounter rolls on the encounter table of a region, defaulting to the first one.
+// A random seed is chosen and reported when none is given, so any roll can be repeated.
+func (s *WorldService) RollEncounter(w *models.World, req models.RollEncounterRequest) (*models.EncounterRoll, error) {
+	region, err := generateWorldMap(w).regionOrFirst(req.Region)
+	if err != nil {
+		return nil, err
+	}
+	table := encounterTable(w, region)
+
+	seed := rand.Int63()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	roll := 1 + rng.Intn(100)
+	result := &models.EncounterRoll{Region: region.Name, Seed: seed, Roll: roll}
+	for _, entry := range table.Entries {
+		if roll >= entry.Min && roll <= entry.Max {
+			result.Entry = entry
+			result.Quantity = rollDice(rng, entry.Quantity)
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// ExportEncounterTable converts the encounter table of a region, defaulting to the first one,
+// to a Foundry VTT RollTable
+func (s *WorldService) ExportEncounterTable(w *models.World, regionName string) (*models.FoundryRollTable, error) {
+	region, err := generateWorldMap(w).regionOrFirst(regionName)
+	if err != nil {
+		return nil, err
+	}
+	table := encounterTable(w, region)
+
+	export := &models.FoundryRollTable{
+		Name:        table.Name,
+		Description: fmt.Sprintf("Random encounters in the %s of %s.", table.Region, w.Name),
+		Formula:     "1" + encounterDice,
+		Replacement: true,
+		DisplayRoll: true,
+		Results:     make([]models.FoundryRollResult, 0, len(table.Entries)),
+	}
+
+	for _, entry := range table.Entries {
+		// Foundry rolls inline [[...]] formulas when the result is drawn
+		text := entry.Name
+		if strings.Contains(entry.Quantity, "d") {
+			text = fmt.Sprintf("[[%s]] %s", entry.Quantity, entry.Name)
+		}
+
+		export.Results = append(export.Results, models.FoundryRollResult{
+			Type:   0, // text result
+			Text:   text,
+			Img:    "icons/svg/d20-black.svg",
+			Weight: entry.Max - entry.Min + 1,
+			Range:  [2]int{entry.Min, entry.Max},
+		})
+	}
+
+	return export, nil
+}
+
+// encounterTable deterministically builds the encounter table of a region,
+// weighting entries by the climate and theme of the world
+func encounterTable(w *models.World, region models.Region) models.EncounterTable {
+	rng := rand.New(rand.NewSource(worldSeed(w, "encounters:"+region.Name)))
+
+	faunaWeight, ok := faunaAbundanceByClimate[w.Climate]
+	if !ok {
+		faunaWeight = 8
+	}
+	dangerWeight, ok := dangerWeightByTheme[w.Theme]
+	if !ok {
+		dangerWeight = 3
+	}
+	if containsString(harshClimates, w.Climate) {
+		dangerWeight += 2
+	}
+
+	type weighted struct {
+		entry  models.EncounterEntry
+		weight int
+	}
+	var rows []weighted
+	add := func(name, kind, quantity string, weight int) {
+		// Vary weights a little so that every world feels different
+		weight = max(1, weight+rng.Intn(3)-1)
+		rows = append(rows, weighted{models.EncounterEntry{Name: name, Kind: kind, Quantity: quantity}, weight})
+	}
+
+	add("Nothing stirs", "quiet", "", 15)
+	for _, creature := range w.Fauna {
+		// Rare creatures of the world are met less often
+		weight := faunaWeight * poolWeight(creature) / rarityWeights[RarityCommon]
+		add(creature, "fauna", faunaQuantities[rng.Intn(len(faunaQuantities))], weight)
+	}
+	if len(w.Fauna) > 0 {
+		add("Tracks of "+strings.ToLower(w.Fauna[rng.Intn(len(w.Fauna))]), "sign", "", 5)
+	}
+	for _, culture := range w.Cultures {
+		add("Travellers of the "+strings.ToLower(culture), "travellers", "2d4", 5)
+	}
+	if region.Settlement != "" {
+		add("Patrol from "+region.Settlement, "travellers", "1d4+1", 4)
+	}
+	for _, hazard := range terrainEncounters[region.Terrain] {
+		add(hazard, "hazard", "1", 5)
+	}
+	for _, danger := range w.Dangers {
+		add(danger, "danger", "1", dangerWeight)
+	}
+
+	total := 0
+	for _, row := range rows {
+		total += row.weight
+	}
+	weights := make([]int, len(rows))
+	for i, row := range rows {
+		weights[i] = row.weight
+	}
+	ranges := scaleWeights(weights, total, 100)
+
+	entries := make([]models.EncounterEntry, 0, len(rows))
+	next := 1
+	for i, row := range rows {
+		entry := row.entry
+		entry.Min, entry.Max = next, next+ranges[i]-1
+		entry.Rarity = rarityOfChance(ranges[i])
+		next += ranges[i]
+		entries = append(entries, entry)
+	}
+
+	return models.EncounterTable{
+		WorldID: w.ID,
+		Name:    region.Name + " encounters",
+		Region:  region.Name,
+		Terrain: region.Terrain,
+		Dice:    encounterDice,
+		Entries: entries,
+	}
+}
+
+// scaleWeights spreads size slots between the weights proportionally, giving each weight at least one slot,
+// so there are more than size slots when there are more weights than slots
+func scaleWeights(weights []int, total, size int) []int {
+	scaled := make([]int, len(weights))
+	remainders := make([]int, len(weights))
+	used := 0
+	for i, weight := range weights {
+		scaled[i] = max(1, weight*size/total)
+		remainders[i] = weight * size % total
+		used += scaled[i]
+	}
+
+	// Hand out leftover slots by largest remainder, or take back slots from the largest entries
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	if used < size {
+		sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	} else {
+		sort.SliceStable(order, func(a, b int) bool { return scaled[order[a]] > scaled[order[b]] })
+	}
+	// Every weight keeps its slot, so none can be taken back once each is down to one
+	for i := 0; len(order) > 0 && (used < size || (used > size && used > len(weights))); i = (i + 1) % len(order) {
+		if used < size {
+			scaled[order[i]]++
+			used++
+		} else if scaled[order[i]] > 1 {
+			scaled[order[i]]--
+			used--
+		}
+	}
+
+	return scaled
+}
+
+// rarityOfChance labels an entry by its chance out of 100
+func rarityOfChance(chance int) string {
+	switch {
+	case chance >= 10:
+		return RarityCommon
+	case chance >= 5:
+		return RarityUncommon
+	case chance >= 2:
+		return RarityRare
+	default:
+		return RarityLegendary
+	}
+}
+
+// rollDice rolls a quantity such as "3", "2d6" or "1d4+1"
+func rollDice(rng *rand.Rand, dice string) int {
+	bonus := 0
+	if base, extra, ok := strings.Cut(dice, "+"); ok {
+		bonus, _ = strconv.Atoi(extra)
+		dice = base
+	}
+
+	count, sides, ok := strings.Cut(dice, "d")
+	if !ok {
+		n, _ := strconv.Atoi(dice)
+		return n + bonus
+	}
+
+	n, _ := strconv.Atoi(count)
+	faces, _ := strconv.Atoi(sides)
+	total := bonus
+	for i := 0; i < n && faces > 0; i++ {
+		total += 1 + rng.Intn(faces)
+	}
+	return total
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+func TestScaleWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []int
+		size    int
+		want    []int
+	}{
+		{"exact shares", []int{1, 1, 2}, 100, []int{25, 25, 50}},
+		{"largest remainders get the leftover", []int{1, 1, 1}, 100, []int{34, 33, 33}},
+		{"few slots per weight", []int{1, 1, 1}, 5, []int{2, 2, 1}},
+		{"tiny weights keep a slot", []int{1000, 1}, 100, []int{99, 1}},
+		{"more weights than slots keep one each", []int{1, 1, 1}, 2, []int{1, 1, 1}},
+		{"single weight", []int{7}, 100, []int{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 0
+			for _, w := range tt.weights {
+				total += w
+			}
+			got := scaleWeights(tt.weights, total, tt.size)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("scaleWeights() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRollDice(t *testing.T) {
+	tests := []struct {
+		dice     string
+		min, max int
+	}{
+		{"3", 3, 3},
+		{"1d4", 1, 4},
+		{"2d6", 2, 12},
+		{"1d4+1", 2, 5},
+		{"0d6", 0, 0},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.dice, func(t *testing.T) {
+			seen := make(map[int]bool)
+			for i := 0; i < 500; i++ {
+				n := rollDice(rng, tt.dice)
+				if n < tt.min || n > tt.max {
+					t.Fatalf("rollDice(%q) = %d, want between %d and %d", tt.dice, n, tt.min, tt.max)
+				}
+				seen[n] = true
+			}
+			if len(seen) != tt.max-tt.min+1 {
+				t.Errorf("rollDice(%q) rolled %d distinct values, want %d", tt.dice, len(seen), tt.max-tt.min+1)
+			}
+		})
+	}
+}
+
+func TestEncounterTablesCoverTheDice(t *testing.T) {
+	s := newTestWorldService()
+	for seed := int64(1); seed <= 10; seed++ {
+		w, _ := buildWorld([]string{"fantasy", "sci-fi", "post-apocalyptic"}[seed%3], &generateOptions{seed: &seed})
+		tables, err := s.GenerateEncounterTables(w, "")
+		if err != nil {
+			t.Fatalf("GenerateEncounterTables() error = %v", err)
+		}
+
+		for _, table := range tables {
+			next := 1
+			for _, entry := range table.Entries {
+				if entry.Min != next || entry.Max < entry.Min {
+					t.Fatalf("world %d, %s: entry %q covers %d-%d after %d", seed, table.Region, entry.Name, entry.Min, entry.Max, next-1)
+				}
+				next = entry.Max + 1
+			}
+			if next != 101 {
+				t.Errorf("world %d, %s: entries end at %d, want 100", seed, table.Region, next-1)
+			}
+		}
+	}
+}
+
+func TestRollEncounterIsSeeded(t *testing.T) {
+	s := newTestWorldService()
+	seed := int64(42)
+	w, _ := buildWorld("fantasy", &generateOptions{seed: &seed})
+
+	for rollSeed := int64(1); rollSeed <= 20; rollSeed++ {
+		req := models.RollEncounterRequest{Seed: &rollSeed}
+		first, err := s.RollEncounter(w, req)
+		if err != nil {
+			t.Fatalf("RollEncounter() error = %v", err)
+		}
+		second, _ := s.RollEncounter(w, req)
+		if *first != *second {
+			t.Fatalf("rolls with seed %d differ: %+v and %+v", rollSeed, first, second)
+		}
+		if first.Roll < first.Entry.Min || first.Roll > first.Entry.Max {
+			t.Errorf("roll %d drew entry %q covering %d-%d", first.Roll, first.Entry.Name, first.Entry.Min, first.Entry.Max)
+		}
+	}
+}
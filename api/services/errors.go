@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/medinapdr/world-gen/config"
+)
+
+// Kinds of the errors returned by the services. Every error of a kind matches it with errors.Is,
+// so that callers choose a response without comparing messages.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrUnavailable = errors.New("unavailable")
+	ErrValidation  = errors.New("invalid request")
+	ErrConflict    = errors.New("conflict")
+)
+
+// ErrNoDatabase is returned when PostgreSQL or Redis is not connected
+var ErrNoDatabase = newError(ErrUnavailable, "no database connection available")
+
+// kindError is an error of one of the kinds above with its own message
+type kindError struct {
+	kind error
+	err  error
+}
+
+// newError formats an error of a kind; errors wrapped with %w still match
+func newError(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+// storageError marks the failures of PostgreSQL caused by an outage as Unavailable, keeping the others as they are.
+// The driver error is logged rather than returned, since the message reaches the clients.
+func storageError(err error) error {
+	var netErr net.Error
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &netErr) || errors.As(err, &connectErr) || pgconn.Timeout(err) {
+		log.Printf("Database unavailable: %v", err)
+		return newError(ErrUnavailable, "database unavailable")
+	}
+	return err
+}
+
+// persist stores a generated resource with save. When there is no database or the save fails,
+// strict mode fails the generation with an Unavailable error; otherwise the failure is logged
+// and the resource is returned unsaved.
+func persist(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, resource string, save func() error) error {
+	if dbConfig.DB == nil {
+		if appConfig.StrictPersistence {
+			return ErrNoDatabase
+		}
+		return nil
+	}
+
+	if err := save(); err != nil {
+		log.Printf("Error inserting %s into DB: %v", resource, err)
+		if appConfig.StrictPersistence {
+			return newError(ErrUnavailable, "failed to save %s", resource)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestStorageErrorHidesDriverErrors(t *testing.T) {
+	outage := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused 10.0.0.5:5432")}
+
+	err := storageError(outage)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("storageError() = %v, want an unavailable error", err)
+	}
+	if strings.Contains(err.Error(), "10.0.0.5") {
+		t.Errorf("storageError() message %q leaks the driver error", err.Error())
+	}
+
+	other := errors.New("syntax error")
+	if err := storageError(other); err != other {
+		t.Errorf("storageError() = %v, want the error unchanged", err)
+	}
+}
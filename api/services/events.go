@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Event types
+const (
+	EventWorldGenerated = "world.generated"
+	EventWorldUpdated   = "world.updated"
+	EventWorldDeleted   = "world.deleted"
+	EventWorldImported  = "world.imported"
+)
+
+const (
+	eventChannel = "world-events"
+	eventLogKey  = "world-events:log"
+	eventSeqKey  = "world-events:seq"
+	// Number of past events kept to resume interrupted subscriptions
+	eventReplayLimit = 1000
+	// Events buffered for a subscriber before it is dropped as too slow
+	eventSubscriberBuffer = 64
+)
+
+// EventBus fans out world events to the subscribers of every API instance.
+// Events go through Redis pub/sub, and the latest ones are kept in a sorted set
+// so that subscribers can resume from the last event they received. Without Redis,
+// events only reach the subscribers of this instance.
+type EventBus struct {
+	dbConfig *config.DatabaseConfig
+
+	mu          sync.Mutex
+	subscribers map[*EventSubscription]bool
+	// Functions called with the events published by this instance
+	hooks []func(ctx context.Context, event models.WorldEvent)
+	// Events kept in process when Redis is not available
+	lastID int64
+	recent []models.WorldEvent
+}
+
+// EventSubscription receives the events published after it was opened
+type EventSubscription struct {
+	// Replay holds the events missed since the last event ID given to Subscribe
+	Replay []models.WorldEvent
+	// Events is closed when the subscription is closed or falls too far behind
+	Events <-chan models.WorldEvent
+
+	events chan models.WorldEvent
+	bus    *EventBus
+}
+
+// NewEventBus creates a new event bus
+func NewEventBus(dbConfig *config.DatabaseConfig) *EventBus {
+	return &EventBus{
+		dbConfig:    dbConfig,
+		subscribers: make(map[*EventSubscription]bool),
+	}
+}
+
+// Start relays the events published by every instance until the context is done
+func (b *EventBus) Start(ctx context.Context) {
+	if b.dbConfig.RedisClient == nil {
+		return
+	}
+
+	pubsub := b.dbConfig.RedisClient.Subscribe(ctx, eventChannel)
+	go func() {
+		defer pubsub.Close()
+		for msg := range pubsub.Channel() {
+			var event models.WorldEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("Error deserializing event: %v", err)
+				continue
+			}
+			b.dispatch(event)
+		}
+	}()
+}
+
+// OnPublish registers a function called with every event published by this instance,
+// once it has an ID. Unlike subscribers, each event reaches the hooks of a single instance.
+func (b *EventBus) OnPublish(hook func(ctx context.Context, event models.WorldEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hooks = append(b.hooks, hook)
+}
+
+// Publish sends an event about a world to every subscriber
+func (b *EventBus) Publish(ctx context.Context, eventType string, w *models.World) {
+	event := models.WorldEvent{Type: eventType, World: *w, CreatedAt: time.Now().UTC()}
+	event.World.Diagnostics = nil
+
+	if b.dbConfig.RedisClient == nil {
+		b.mu.Lock()
+		b.lastID++
+		event.ID = b.lastID
+		if b.recent = append(b.recent, event); len(b.recent) > eventReplayLimit {
+			b.recent = b.recent[len(b.recent)-eventReplayLimit:]
+		}
+		b.mu.Unlock()
+		b.dispatch(event)
+		b.runHooks(ctx, event)
+		return
+	}
+
+	id, err := b.dbConfig.RedisClient.Incr(ctx, eventSeqKey).Result()
+	if err != nil {
+		log.Printf("Error publishing event: %v", err)
+		return
+	}
+	event.ID = id
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error serializing event: %v", err)
+		return
+	}
+
+	pipe := b.dbConfig.RedisClient.Pipeline()
+	pipe.ZAdd(ctx, eventLogKey, redis.Z{Score: float64(id), Member: eventJSON})
+	pipe.ZRemRangeByRank(ctx, eventLogKey, 0, -eventReplayLimit-1)
+	pipe.Publish(ctx, eventChannel, eventJSON)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error publishing event: %v", err)
+	}
+	b.runHooks(ctx, event)
+}
+
+func (b *EventBus) runHooks(ctx context.Context, event models.WorldEvent) {
+	b.mu.Lock()
+	hooks := b.hooks
+	b.mu.Unlock()
+	for _, hook := range hooks {
+		hook(ctx, event)
+	}
+}
+
+// Subscribe opens a subscription. When lastEventID is positive, the events published
+// after it that are still kept are replayed first.
+func (b *EventBus) Subscribe(ctx context.Context, lastEventID int64) (*EventSubscription, error) {
+	events := make(chan models.WorldEvent, eventSubscriberBuffer)
+	sub := &EventSubscription{Events: events, events: events, bus: b}
+
+	// Register before reading the replay so that no event falls in between
+	b.mu.Lock()
+	b.subscribers[sub] = true
+	b.mu.Unlock()
+
+	if lastEventID <= 0 {
+		return sub, nil
+	}
+
+	if b.dbConfig.RedisClient == nil {
+		b.mu.Lock()
+		for _, event := range b.recent {
+			if event.ID > lastEventID {
+				sub.Replay = append(sub.Replay, event)
+			}
+		}
+		b.mu.Unlock()
+		return sub, nil
+	}
+
+	eventsJSON, err := b.dbConfig.RedisClient.ZRangeByScore(ctx, eventLogKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(lastEventID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	for _, eventJSON := range eventsJSON {
+		var event models.WorldEvent
+		if err := json.Unmarshal([]byte(eventJSON), &event); err == nil {
+			sub.Replay = append(sub.Replay, event)
+		}
+	}
+	return sub, nil
+}
+
+// Close stops the subscription
+func (s *EventSubscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.bus.subscribers[s] {
+		delete(s.bus.subscribers, s)
+		close(s.events)
+	}
+}
+
+// dispatch hands an event to the local subscribers, dropping those that cannot keep up.
+// Dropped subscribers can resume from their last event.
+func (b *EventBus) dispatch(event models.WorldEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Formats of the world dossiers
+const (
+	DossierFormatMarkdown = "md"
+	DossierFormatHTML     = "html"
+	DossierFormatPDF      = "pdf"
+)
+
+// DossierFormats lists the formats a world can be exported to
+var DossierFormats = []string{DossierFormatMarkdown, DossierFormatHTML, DossierFormatPDF}
+
+// Tools worlds can be exported to in their native formats
+const (
+	ExportTargetObsidian = "obsidian"
+	ExportTargetFoundry  = "foundry"
+)
+
+// ExportTargets lists the tools a world can be exported to
+var ExportTargets = []string{ExportTargetObsidian, ExportTargetFoundry}
+
+// Names of the dossier templates, also looked up in the directory of custom templates
+const (
+	markdownTemplateName = "dossier.md.tmpl"
+	htmlTemplateName     = "dossier.html.tmpl"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Functions available to the dossier templates
+var dossierFuncs = map[string]any{
+	"join":  strings.Join,
+	"title": capitalize,
+}
+
+// Dossier is the data the dossier templates are executed with
+type Dossier struct {
+	World      *models.World
+	Locations  []models.Location
+	ExportedAt time.Time
+}
+
+// ExportService renders worlds as documents for writers
+type ExportService struct {
+	locationService  *LocationService
+	markdownTemplate *template.Template
+	htmlTemplate     *htmltemplate.Template
+}
+
+// NewExportService creates a new instance of the service. The templates of the dossiers are read from
+// the directory configured in the application, falling back to the built-in ones.
+func NewExportService(appConfig *config.AppConfig, locationService *LocationService) *ExportService {
+	md := template.Must(template.New(markdownTemplateName).Funcs(dossierFuncs).Parse(readTemplate(appConfig.ExportTemplateDir, markdownTemplateName)))
+	html := htmltemplate.Must(htmltemplate.New(htmlTemplateName).Funcs(dossierFuncs).Parse(readTemplate(appConfig.ExportTemplateDir, htmlTemplateName)))
+	return &ExportService{
+		locationService:  locationService,
+		markdownTemplate: md,
+		htmlTemplate:     html,
+	}
+}
+
+// readTemplate returns the custom template with the given name, or the built-in one when there is none or it is invalid
+func readTemplate(dir, name string) string {
+	builtin, _ := builtinTemplates.ReadFile("templates/" + name)
+	if dir == "" {
+		return string(builtin)
+	}
+
+	custom, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to read template %s: %v", name, err)
+		}
+		return string(builtin)
+	}
+
+	// Checks the template before using it, so that a broken one does not stop the server
+	var parseErr error
+	if name == htmlTemplateName {
+		_, parseErr = htmltemplate.New(name).Funcs(dossierFuncs).Parse(string(custom))
+	} else {
+		_, parseErr = template.New(name).Funcs(dossierFuncs).Parse(string(custom))
+	}
+	if parseErr != nil {
+		log.Printf("Warning: Invalid template %s, using the built-in one: %v", name, parseErr)
+		return string(builtin)
+	}
+	return string(custom)
+}
+
+// ValidateDossierFormat checks that worlds can be exported to a format
+func ValidateDossierFormat(format string) error {
+	if !containsString(DossierFormats, format) {
+		return newError(ErrValidation, "unknown format %q, expected one of %s", format, strings.Join(DossierFormats, ", "))
+	}
+	return nil
+}
+
+// DossierContentType returns the media type of a dossier format
+func DossierContentType(format string) string {
+	switch format {
+	case DossierFormatHTML:
+		return "text/html; charset=UTF-8"
+	case DossierFormatPDF:
+		return "application/pdf"
+	}
+	return "text/markdown; charset=UTF-8"
+}
+
+// DossierFileName returns the name of the file of a world's dossier
+func DossierFileName(w *models.World, format string) string {
+	return ExportFileName(w) + "." + format
+}
+
+// ExportFileName returns the base name of the files exported from a world, made of its ID and name
+func ExportFileName(w *models.World) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, w.Name)
+	return fmt.Sprintf("world-%d-%s", w.ID, strings.Trim(slug, "-"))
+}
+
+// ValidateExportTarget checks that worlds can be exported to a tool
+func ValidateExportTarget(target string) error {
+	if !containsString(ExportTargets, target) {
+		return newError(ErrValidation, "unknown export target %q, expected one of %s", target, strings.Join(ExportTargets, ", "))
+	}
+	return nil
+}
+
+// ExportObsidianVault builds the zip file of an Obsidian vault with notes for the world and its locations,
+// cultures, dangers and languages
+func (s *ExportService) ExportObsidianVault(ctx context.Context, w *models.World) ([]byte, error) {
+	locations, err := s.locationService.GetLocations(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildObsidianVault(w, locations)
+}
+
+// ExportFoundryJournal builds a Foundry VTT journal entry describing the world and its locations
+func (s *ExportService) ExportFoundryJournal(ctx context.Context, w *models.World) (*models.FoundryJournalEntry, error) {
+	locations, err := s.locationService.GetLocations(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	return buildFoundryJournal(w, locations), nil
+}
+
+// ExportWorld renders the dossier of a world with its locations
+func (s *ExportService) ExportWorld(ctx context.Context, w *models.World, format string) ([]byte, error) {
+	if err := ValidateDossierFormat(format); err != nil {
+		return nil, err
+	}
+
+	locations, err := s.locationService.GetLocations(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.render(Dossier{World: w, Locations: locations, ExportedAt: time.Now()}, format)
+}
+
+// WriteArchive writes a zip file with the dossiers of the worlds, one file per world
+func (s *ExportService) WriteArchive(ctx context.Context, out io.Writer, worlds []models.World, format string) error {
+	if err := ValidateDossierFormat(format); err != nil {
+		return err
+	}
+
+	ids := make([]int, len(worlds))
+	for i, w := range worlds {
+		ids[i] = w.ID
+	}
+	locations, err := s.locationService.GetLocationsByWorldIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(out)
+	exportedAt := time.Now()
+	for i := range worlds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		w := &worlds[i]
+		doc, err := s.render(Dossier{World: w, Locations: locations[w.ID], ExportedAt: exportedAt}, format)
+		if err != nil {
+			return err
+		}
+
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     DossierFileName(w, format),
+			Method:   zip.Deflate,
+			Modified: exportedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(doc); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// render executes the template of a format; PDF documents are laid out from the Markdown dossier
+func (s *ExportService) render(dossier Dossier, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == DossierFormatHTML {
+		err = s.htmlTemplate.Execute(&buf, dossier)
+	} else {
+		err = s.markdownTemplate.Execute(&buf, dossier)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render dossier of world %d: %w", dossier.World.ID, err)
+	}
+
+	if format == DossierFormatPDF {
+		return renderPDF(dossier.World.Name, buf.String())
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+func newTestExportService(t *testing.T, templateDir string) *ExportService {
+	t.Helper()
+	db := &config.DatabaseConfig{}
+	app := config.NewAppConfig()
+	app.ExportTemplateDir = templateDir
+	return NewExportService(app, NewLocationService(db, app))
+}
+
+// testDossier returns the dossier of a generated world with a location
+func testDossier(t *testing.T, theme string, seed int64) Dossier {
+	t.Helper()
+	w, _ := buildWorld(theme, &generateOptions{seed: &seed})
+	w.ID = int(seed)
+
+	db := &config.DatabaseConfig{}
+	location, err := NewLocationService(db, config.NewAppConfig()).GenerateLocation(context.Background(), w, models.CreateLocationRequest{})
+	if err != nil {
+		t.Fatalf("GenerateLocation() error = %v", err)
+	}
+	return Dossier{World: w, Locations: []models.Location{*location}, ExportedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestRenderDossier(t *testing.T) {
+	tests := []struct {
+		name    string
+		dossier func(t *testing.T) Dossier
+		// every rendering holds these texts
+		want []string
+		// no rendering holds these texts
+		absent []string
+	}{
+		{
+			name:    "fantasy world",
+			dossier: func(t *testing.T) Dossier { return testDossier(t, "fantasy", 1) },
+			want:    []string{"Features", "Fauna", "Religions", "Locations", "2024-05-01"},
+		},
+		{
+			name:    "sci-fi world",
+			dossier: func(t *testing.T) Dossier { return testDossier(t, "sci-fi", 2) },
+			want:    []string{"Faster than light travel", "Artificial intelligence"},
+		},
+		{
+			name:    "post-apocalyptic world",
+			dossier: func(t *testing.T) Dossier { return testDossier(t, "post-apocalyptic", 3) },
+			want:    []string{"Surviving technology"},
+		},
+		{
+			name: "world without lists",
+			dossier: func(t *testing.T) Dossier {
+				return Dossier{World: &models.World{ID: 4, Name: "Bare", Theme: "fantasy", Climate: "Arid"}}
+			},
+			want:   []string{"Bare", "Fantasy world, Arid climate"},
+			absent: []string{"Features", "Religions", "Locations"},
+		},
+	}
+
+	s := newTestExportService(t, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dossier := tt.dossier(t)
+			for _, format := range []string{DossierFormatMarkdown, DossierFormatHTML} {
+				doc, err := s.render(dossier, format)
+				if err != nil {
+					t.Fatalf("render(%s) error = %v", format, err)
+				}
+				text := string(doc)
+				for _, want := range append(tt.want, dossier.World.Name) {
+					if !strings.Contains(text, want) {
+						t.Errorf("%s dossier does not contain %q", format, want)
+					}
+				}
+				for _, absent := range append(tt.absent, "<no value>") {
+					if strings.Contains(text, absent) {
+						t.Errorf("%s dossier contains %q", format, absent)
+					}
+				}
+			}
+
+			pdf, err := s.render(dossier, DossierFormatPDF)
+			if err != nil {
+				t.Fatalf("render(pdf) error = %v", err)
+			}
+			if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+				t.Errorf("render(pdf) does not start with a PDF header")
+			}
+		})
+	}
+}
+
+func TestRenderDossierEscapesHTML(t *testing.T) {
+	s := newTestExportService(t, "")
+	dossier := Dossier{World: &models.World{Name: "<script>alert(1)</script>", Theme: "fantasy", Features: []string{"A & B"}}}
+
+	doc, err := s.render(dossier, DossierFormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(doc), "<script>") || !strings.Contains(string(doc), "A &amp; B") {
+		t.Errorf("HTML dossier is not escaped:\n%s", doc)
+	}
+}
+
+func TestCustomDossierTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"custom template", "Custom {{.World.Name}} {{join .World.Features \"/\"}}", "Custom Aster a/b"},
+		{"invalid template falls back", "{{.World.Name", "# Aster"},
+		{"missing template falls back", "", "# Aster"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.template != "" {
+				if err := os.WriteFile(filepath.Join(dir, markdownTemplateName), []byte(tt.template), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s := newTestExportService(t, dir)
+			doc, err := s.render(Dossier{World: &models.World{Name: "Aster", Theme: "fantasy", Features: []string{"a", "b"}}}, DossierFormatMarkdown)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if !strings.HasPrefix(string(doc), tt.want) {
+				t.Errorf("render() = %q, want it to start with %q", doc, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Aster", "world-7-aster"},
+		{"Nova Terra II", "world-7-nova-terra-ii"},
+		{"  Ærø's Isle! ", "world-7-r--s-isle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExportFileName(&models.World{ID: 7, Name: tt.name}); got != tt.want {
+				t.Errorf("ExportFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
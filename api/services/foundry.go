@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Foundry VTT constants of journal pages
+const (
+	foundryPageText   = "text"
+	foundryFormatHTML = 1
+	// Foundry spaces the sort keys of documents by this much
+	foundrySortStep = 100000
+	foundryScope    = "world-gen"
+)
+
+// buildFoundryJournal builds a journal entry with a page for the overview of the world, its peoples,
+// dangers, religions and power system, and a page per location
+func buildFoundryJournal(w *models.World, locations []models.Location) *models.FoundryJournalEntry {
+	entry := &models.FoundryJournalEntry{
+		Name: w.Name,
+		Flags: map[string]map[string]any{
+			foundryScope: {"id": w.ID, "seed": w.Seed, "theme": w.Theme},
+		},
+	}
+	addPage := func(name, content string) {
+		entry.Pages = append(entry.Pages, models.FoundryJournalPage{
+			Name:  name,
+			Type:  foundryPageText,
+			Title: models.FoundryPageTitle{Show: true, Level: 1},
+			Text:  models.FoundryPageText{Format: foundryFormatHTML, Content: content},
+			Sort:  (len(entry.Pages) + 1) * foundrySortStep,
+		})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<p><em>%s world, %s climate, population %d</em></p>", esc(capitalize(w.Theme)), esc(w.Climate), w.Population)
+	fmt.Fprintf(&sb, "<p>%s</p>", esc(w.Description))
+	htmlList(&sb, "Features", w.Features)
+	htmlList(&sb, "Fauna", w.Fauna)
+	htmlList(&sb, "Flora", w.Flora)
+	addPage("Overview", sb.String())
+
+	sb.Reset()
+	htmlList(&sb, "Cultures", w.Cultures)
+	htmlList(&sb, "Languages", w.Languages)
+	addPage("Peoples", sb.String())
+
+	if len(w.Dangers) > 0 {
+		sb.Reset()
+		htmlList(&sb, "Dangers", w.Dangers)
+		addPage("Dangers", sb.String())
+	}
+
+	for _, religion := range w.Religions {
+		sb.Reset()
+		fmt.Fprintf(&sb, "<p><em>%s</em></p><p>%s</p>", esc(religion.Type), esc(religion.Doctrine))
+		htmlList(&sb, "Cultures", religion.Cultures)
+		htmlList(&sb, "Domains", religion.Domains)
+		deities := make([]string, len(religion.Deities))
+		for i, deity := range religion.Deities {
+			deities[i] = fmt.Sprintf("%s, %s: %s (%s)", deity.Name, deity.Title, strings.Join(deity.Domains, ", "), deity.Symbol)
+		}
+		htmlList(&sb, "Deities", deities)
+		htmlList(&sb, "Symbols", religion.Symbols)
+		htmlList(&sb, "Holy sites", religion.HolySites)
+		htmlList(&sb, "Rituals", religion.Rituals)
+		htmlList(&sb, "Taboos", religion.Taboos)
+		addPage(religion.Name, sb.String())
+	}
+
+	if ps := w.PowerSystem; ps != nil {
+		sb.Reset()
+		fmt.Fprintf(&sb, "<p><em>%s</em></p><p>%s</p>", esc(capitalize(ps.Type)), esc(ps.Summary))
+		if m := ps.Magic; m != nil {
+			fmt.Fprintf(&sb, "<p><strong>Source:</strong> %s</p><p><strong>Cost:</strong> %s</p>", esc(m.Source), esc(m.Cost))
+			htmlList(&sb, "Limitations", m.Limitations)
+			htmlList(&sb, "Practitioners", m.Practitioners)
+		}
+		if t := ps.Technology; t != nil {
+			htmlList(&sb, "Technology", []string{
+				"Level: " + t.Level, "Faster than light travel: " + t.FTL,
+				"Artificial intelligence: " + t.AIStatus, "Energy source: " + t.EnergySource,
+			})
+		}
+		if c := ps.Collapse; c != nil {
+			fmt.Fprintf(&sb, "<p><strong>Collapse:</strong> %s, %d years ago</p>", esc(c.Type), c.YearsAgo)
+			htmlList(&sb, "Surviving technology", c.SurvivingTech)
+		}
+		fmt.Fprintf(&sb, "<p>%s</p>", esc(ps.Consequence))
+		addPage(ps.Name, sb.String())
+	}
+
+	for _, location := range locations {
+		sb.Reset()
+		fmt.Fprintf(&sb, "<p><em>%s built around %s, haunted by %s</em></p><p>%s</p>",
+			esc(capitalize(location.Type)), esc(location.Feature), esc(location.Danger), esc(location.History))
+		fmt.Fprintf(&sb, "<pre>%s</pre>", esc(strings.Join(location.Layout.ASCII, "\n")))
+		rooms := make([]string, len(location.Layout.Rooms))
+		for i, room := range location.Layout.Rooms {
+			rooms[i] = fmt.Sprintf("%s. %s: %s", room.Label, room.Name, room.Description)
+		}
+		htmlList(&sb, "Rooms", rooms)
+		inhabitants := make([]string, len(location.Inhabitants))
+		for i, inhabitant := range location.Inhabitants {
+			inhabitants[i] = fmt.Sprintf("%d %s in room %d", inhabitant.Count, inhabitant.Name, inhabitant.Room)
+			if inhabitant.Hostile {
+				inhabitants[i] += ", hostile"
+			}
+		}
+		htmlList(&sb, "Inhabitants", inhabitants)
+		treasure := make([]string, len(location.Treasure))
+		for i, item := range location.Treasure {
+			treasure[i] = fmt.Sprintf("%s, worth %d, in room %d", item.Name, item.Value, item.Room)
+		}
+		htmlList(&sb, "Treasure", treasure)
+		addPage(location.Name, sb.String())
+	}
+
+	return entry
+}
+
+// htmlList writes a heading and a list of escaped items, skipping empty lists
+func htmlList(sb *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "<h2>%s</h2><ul>", esc(heading))
+	for _, item := range items {
+		fmt.Fprintf(sb, "<li>%s</li>", esc(item))
+	}
+	sb.WriteString("</ul>")
+}
+
+func esc(s string) string {
+	return html.EscapeString(s)
+}
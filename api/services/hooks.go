@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for adventure hook generation
+
+// MaxHookCount is the highest number of hooks generated in a single request
+const MaxHookCount = 20
+
+// Difficulty tiers of adventure hooks, from tier 1 to 4
+var hookTiers = []struct {
+	Name       string
+	Weight     int
+	RewardBase int
+}{
+	{"local", 40, 50},
+	{"regional", 30, 200},
+	{"world-shaking", 20, 800},
+	{"legendary", 10, 3000},
+}
+
+var hookCurrencyByTheme = map[string]string{
+	"fantasy":          "gold crowns",
+	"sci-fi":           "credits",
+	"post-apocalyptic": "bottle caps",
+}
+
+var hookTitleAdjectives = []string{"Silent", "Broken", "Crimson", "Last", "Hollow", "Forgotten", "Burning", "Drowned", "Iron", "Whispering"}
+var hookTitleNouns = []string{"Bargain", "Hunt", "Oath", "Reckoning", "Road", "Debt", "Vigil", "Harvest", "Crown", "Signal"}
+
+// Objective templates by the kind of world element they are built around
+var hookObjectives = map[string][]string{
+	"danger":  {"Destroy the source of the %s", "Find out what awakened the %s", "Lead the survivors away from the %s"},
+	"fauna":   {"Hunt down a monstrous pack of %s", "Capture a living specimen of %s", "Protect the caravans from maddened %s"},
+	"culture": {"Negotiate a truce with the %s", "Recover a relic stolen from the %s", "Escort an envoy of the %s"},
+	"feature": {"Map the uncharted %s", "Rescue a lost expedition in the %s", "Find what is buried beneath the %s"},
+}
+
+var hookComplications = []string{
+	"The patron is lying about the real goal", "A storm cuts off the only road back",
+	"The job must be done within three days", "Someone in the patron's household is a traitor",
+	"The target is not what it seems", "The local authorities forbid any interference",
+}
+
+// Complication templates built around a culture or a danger of the world
+var hookCultureComplications = []string{"The %s want the prize for themselves", "An agent of the %s is after the same goal"}
+var hookDangerComplications = []string{"The %s are far stronger than anyone admits", "The %s stand between the party and the goal"}
+
+// GenerateHooks generates count adventure hooks of the world, optionally restricted to a difficulty tier,
+// skipping the hooks already used. Hooks are numbered from 1 so that each can be fetched again with GenerateHook.
+func (s *WorldService) GenerateHooks(w *models.World, count, tier int, used map[int]bool) ([]models.AdventureHook, error) {
+	if tier < 0 || tier > len(hookTiers) {
+		return nil, newError(ErrValidation, "invalid tier %d, expected 1 to %d", tier, len(hookTiers))
+	}
+
+	m := generateWorldMap(w)
+	hooks := make([]models.AdventureHook, 0, count)
+	// The tier of a hook is derived from its ID, so filtering skips IDs of other tiers
+	for id := 1; len(hooks) < count && id <= (count+len(used))*len(hookTiers)*10; id++ {
+		if used[id] {
+			continue
+		}
+		hook := s.generateHook(w, m, id)
+		if tier == 0 || hook.Tier == tier {
+			hooks = append(hooks, *hook)
+		}
+	}
+
+	return hooks, nil
+}
+
+// GenerateHook deterministically generates the adventure hook of the world with the given ID
+func (s *WorldService) GenerateHook(w *models.World, id int) *models.AdventureHook {
+	return s.generateHook(w, generateWorldMap(w), id)
+}
+
+// generateHook builds a hook using the already generated map of the world
+func (s *WorldService) generateHook(w *models.World, m *worldMap, id int) *models.AdventureHook {
+	rng := rand.New(rand.NewSource(worldSeed(w, fmt.Sprintf("hook:%d", id))))
+
+	tier := randomHookTier(rng)
+	patron := s.GenerateNPC(w, 1+rng.Intn(1000))
+
+	return &models.AdventureHook{
+		ID:       id,
+		WorldID:  w.ID,
+		Title:    fmt.Sprintf("The %s %s", hookTitleAdjectives[rng.Intn(len(hookTitleAdjectives))], hookTitleNouns[rng.Intn(len(hookTitleNouns))]),
+		Tier:     tier,
+		TierName: hookTiers[tier-1].Name,
+		Patron: models.HookPatron{
+			NPCID:      patron.ID,
+			Name:       patron.Name,
+			Culture:    patron.Culture,
+			Occupation: patron.Occupation,
+		},
+		Objective:    hookObjective(rng, w, tier),
+		Complication: hookComplication(rng, w),
+		Location:     hookLocation(rng, w, m),
+		Reward:       hookReward(rng, w.Theme, tier),
+	}
+}
+
+// randomHookTier picks a difficulty tier, easier tiers being more common
+func randomHookTier(rng *rand.Rand) int {
+	total := 0
+	for _, t := range hookTiers {
+		total += t.Weight
+	}
+
+	roll := rng.Intn(total)
+	for i, t := range hookTiers {
+		if roll < t.Weight {
+			return i + 1
+		}
+		roll -= t.Weight
+	}
+	return 1
+}
+
+// hookObjective builds the objective around a world element, higher tiers facing the world's dangers
+func hookObjective(rng *rand.Rand, w *models.World, tier int) string {
+	elements := map[string][]string{
+		"danger":  w.Dangers,
+		"fauna":   w.Fauna,
+		"culture": w.Cultures,
+		"feature": w.Features,
+	}
+
+	kinds := []string{"fauna", "culture", "feature"}
+	switch {
+	case tier >= 3:
+		kinds = []string{"danger", "danger", "culture"}
+	case tier == 2:
+		kinds = append(kinds, "danger")
+	}
+
+	// Skip kinds the world has no elements for
+	var available []string
+	for _, kind := range kinds {
+		if len(elements[kind]) > 0 {
+			available = append(available, kind)
+		}
+	}
+	if len(available) == 0 {
+		return "Find out why the patron really called for help"
+	}
+
+	kind := available[rng.Intn(len(available))]
+	options := elements[kind]
+	templates := hookObjectives[kind]
+	return fmt.Sprintf(templates[rng.Intn(len(templates))], strings.ToLower(options[rng.Intn(len(options))]))
+}
+
+// hookComplication picks a twist, sometimes involving a culture or a danger of the world
+func hookComplication(rng *rand.Rand, w *models.World) string {
+	roll := rng.Float64()
+	switch {
+	case roll < 0.25 && len(w.Cultures) > 0:
+		tmpl := hookCultureComplications[rng.Intn(len(hookCultureComplications))]
+		return fmt.Sprintf(tmpl, strings.ToLower(w.Cultures[rng.Intn(len(w.Cultures))]))
+	case roll < 0.5 && len(w.Dangers) > 0:
+		tmpl := hookDangerComplications[rng.Intn(len(hookDangerComplications))]
+		return fmt.Sprintf(tmpl, strings.ToLower(w.Dangers[rng.Intn(len(w.Dangers))]))
+	default:
+		return hookComplications[rng.Intn(len(hookComplications))]
+	}
+}
+
+// hookLocation places the hook on one of the world's features within a region of its map
+func hookLocation(rng *rand.Rand, w *models.World, m *worldMap) string {
+	location := "The wilds"
+	if len(w.Features) > 0 {
+		location = capitalize(strings.ToLower(w.Features[rng.Intn(len(w.Features))]))
+	}
+	if len(m.regions) > 0 {
+		location += " near " + m.regions[rng.Intn(len(m.regions))].Name
+	}
+	return location
+}
+
+// hookReward offers an amount of the theme's currency matching the tier, sometimes with an item
+func hookReward(rng *rand.Rand, theme string, tier int) models.HookReward {
+	currency, ok := hookCurrencyByTheme[theme]
+	if !ok {
+		currency = hookCurrencyByTheme["fantasy"]
+	}
+
+	value := int(float64(hookTiers[tier-1].RewardBase) * (0.75 + rng.Float64()*0.5))
+	reward := models.HookReward{
+		Description: fmt.Sprintf("%d %s", value, currency),
+		Value:       value,
+	}
+
+	if pool := treasureByTheme[theme]; len(pool) > 0 && rng.Float64() < 0.4 {
+		item := pool[rng.Intn(len(pool))]
+		reward.Description += " and " + strings.ToLower(item.Name)
+		reward.Value += item.Value
+	}
+
+	return reward
+}
+
+// MarkHookUsed records that a hook of a world was used in a campaign, so it is not offered again
+func (s *WorldService) MarkHookUsed(ctx context.Context, worldID, hookID int, campaign string) (*models.HookUsage, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	usage := &models.HookUsage{Campaign: campaign, WorldID: worldID, HookID: hookID}
+	err := s.dbConfig.DB.QueryRow(ctx,
+		`INSERT INTO used_hooks(campaign, world_id, hook_id) VALUES($1,$2,$3)
+		 ON CONFLICT (campaign, world_id, hook_id) DO UPDATE SET used_at = used_hooks.used_at
+		 RETURNING used_at`,
+		campaign, worldID, hookID).Scan(&usage.UsedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// GetUsedHookIDs retrieves the IDs of the hooks of a world already used in a campaign
+func (s *WorldService) GetUsedHookIDs(ctx context.Context, worldID int, campaign string) (map[int]bool, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := s.dbConfig.DB.Query(ctx,
+		`SELECT hook_id FROM used_hooks WHERE campaign = $1 AND world_id = $2`, campaign, worldID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	used := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		used[id] = true
+	}
+
+	return used, rows.Err()
+}
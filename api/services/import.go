@@ -0,0 +1,347 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/medinapdr/world-gen/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the documents worlds are imported from
+const (
+	ImportFormatJSON   = "json"
+	ImportFormatNDJSON = "ndjson"
+	ImportFormatYAML   = "yaml"
+)
+
+// ImportFormats lists the formats worlds can be imported from
+var ImportFormats = []string{ImportFormatJSON, ImportFormatNDJSON, ImportFormatYAML}
+
+// MaxImportCount is the largest number of worlds imported at once
+const MaxImportCount = 1000
+
+// Longest line of an NDJSON document
+const maxImportLine = 4 << 20
+
+// ImportOptions controls how worlds are stored by an import
+type ImportOptions struct {
+	// Atomic stores every world or none of them
+	Atomic bool
+	// PreserveIDs keeps the IDs and star systems of the worlds instead of assigning new IDs,
+	// failing the worlds whose ID is taken
+	PreserveIDs bool
+}
+
+// ImportedWorld is a world read from an import document, or the error that prevented reading it
+type ImportedWorld struct {
+	World *models.World
+	Err   error
+}
+
+// queryRower runs a query returning a single row, on the pool or in a transaction
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// DecodeImport reads the worlds of a JSON, NDJSON or YAML document. JSON documents and every YAML document
+// hold a world, a list of worlds or a page of search results, so the output of the exports can be imported.
+// Worlds that cannot be read are returned with their error, while a document that cannot be parsed fails as a whole.
+func DecodeImport(format string, r io.Reader) ([]ImportedWorld, error) {
+	switch format {
+	case ImportFormatJSON:
+		raw, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return decodeImportDocument(raw)
+
+	case ImportFormatNDJSON:
+		var records []ImportedWorld
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxImportLine)
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				records = append(records, decodeImportedWorld(line))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, newError(ErrValidation, "invalid NDJSON: %w", err)
+		}
+		return records, nil
+
+	case ImportFormatYAML:
+		var records []ImportedWorld
+		decoder := yaml.NewDecoder(r)
+		for {
+			var doc any
+			err := decoder.Decode(&doc)
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			if err != nil {
+				return nil, newError(ErrValidation, "invalid YAML: %w", err)
+			}
+			if doc == nil {
+				continue
+			}
+
+			// Going through JSON reads the fields by their JSON names, like the other formats
+			raw, err := json.Marshal(doc)
+			if err != nil {
+				return nil, newError(ErrValidation, "invalid YAML: %w", err)
+			}
+			docRecords, err := decodeImportDocument(raw)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, docRecords...)
+		}
+	}
+
+	return nil, newError(ErrValidation, "unknown import format %q, expected one of %s", format, strings.Join(ImportFormats, ", "))
+}
+
+// decodeImportDocument reads a JSON document holding a world, a list of worlds or a page of search results
+func decodeImportDocument(raw []byte) ([]ImportedWorld, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var items []json.RawMessage
+	switch raw[0] {
+	case '[':
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, newError(ErrValidation, "invalid JSON: %v", err)
+		}
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, newError(ErrValidation, "invalid JSON: %v", err)
+		}
+		data, isPage := fields["data"]
+		if !isPage {
+			return []ImportedWorld{decodeImportedWorld(raw)}, nil
+		}
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, newError(ErrValidation, "invalid page of worlds: %v", err)
+		}
+	default:
+		return nil, newError(ErrValidation, "expected a world, a list of worlds or a page of search results")
+	}
+
+	records := make([]ImportedWorld, len(items))
+	for i, item := range items {
+		records[i] = decodeImportedWorld(item)
+	}
+	return records, nil
+}
+
+// decodeImportedWorld reads a world, rejecting unknown fields so that misspelled ones are not silently lost
+func decodeImportedWorld(raw []byte) ImportedWorld {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	var w models.World
+	if err := decoder.Decode(&w); err != nil {
+		return ImportedWorld{Err: newError(ErrValidation, "invalid world: %v", err)}
+	}
+	return ImportedWorld{World: &w}
+}
+
+// validateImportedWorld checks that a world can be stored, filling in its default theme and the rarities of its lists
+func validateImportedWorld(w *models.World) error {
+	if strings.TrimSpace(w.Name) == "" {
+		return newError(ErrValidation, "name is required")
+	}
+	if w.Theme == "" {
+		w.Theme = "fantasy"
+	}
+	if !validateTheme(w.Theme) {
+		return newError(ErrValidation, "unknown theme %q", w.Theme)
+	}
+	if !validateClimate(w.Climate) {
+		return newError(ErrValidation, "unknown climate %q", w.Climate)
+	}
+	if w.Population < 0 {
+		return newError(ErrValidation, "population cannot be negative")
+	}
+	if len(w.Features) == 0 {
+		return newError(ErrValidation, "at least one feature is required")
+	}
+
+	w.Rarities = worldRarities(w)
+	w.Diagnostics = nil
+	return nil
+}
+
+// ImportWorlds validates and stores imported worlds, reporting the outcome of every one of them.
+// Without the atomic option, valid worlds are stored even when others fail.
+func (s *WorldService) ImportWorlds(ctx context.Context, records []ImportedWorld, opts ImportOptions) (*models.ImportResult, error) {
+	if len(records) == 0 {
+		return nil, newError(ErrValidation, "no worlds to import")
+	}
+	if len(records) > MaxImportCount {
+		return nil, newError(ErrValidation, "at most %d worlds can be imported at once", MaxImportCount)
+	}
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	result := &models.ImportResult{Records: make([]models.ImportRecord, len(records))}
+	for i, rec := range records {
+		r := &result.Records[i]
+		r.Index = i
+
+		err := rec.Err
+		if err == nil {
+			r.Name = rec.World.Name
+			r.SourceID = rec.World.ID
+			err = validateImportedWorld(rec.World)
+		}
+		if err != nil {
+			r.Error = err.Error()
+			continue
+		}
+
+		// IDs of star systems belong to the environment the world comes from, like the ID of the world
+		if !opts.PreserveIDs {
+			rec.World.ID = 0
+			rec.World.SystemID = nil
+		}
+	}
+
+	var stored []*models.World
+	var err error
+	if opts.Atomic {
+		stored, err = s.importAtomically(ctx, records, result, opts)
+	} else {
+		stored = s.importEach(ctx, records, result, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range result.Records {
+		if r.Error != "" {
+			result.Failed++
+		}
+	}
+	result.Imported = len(stored)
+
+	if opts.PreserveIDs && len(stored) > 0 {
+		// Worlds created later must not take the preserved IDs
+		if _, err := s.dbConfig.DB.Exec(ctx,
+			`SELECT setval(pg_get_serial_sequence('worlds', 'id'), GREATEST(MAX(id), 1)) FROM worlds`); err != nil {
+			log.Printf("Error updating the world ID sequence: %v", err)
+		}
+	}
+
+	for _, w := range stored {
+		s.events.Publish(ctx, EventWorldImported, w)
+	}
+	return result, nil
+}
+
+// importEach stores the valid worlds one by one, recording the failure of each world that cannot be stored
+func (s *WorldService) importEach(ctx context.Context, records []ImportedWorld, result *models.ImportResult, opts ImportOptions) []*models.World {
+	var stored []*models.World
+	for i, rec := range records {
+		r := &result.Records[i]
+		if r.Error != "" {
+			continue
+		}
+
+		if err := insertImportedWorld(ctx, s.dbConfig.DB, rec.World, opts.PreserveIDs); err != nil {
+			r.Error = importError(err, rec.World).Error()
+			continue
+		}
+		r.ID = rec.World.ID
+		stored = append(stored, rec.World)
+	}
+	return stored
+}
+
+// importAtomically stores every world in a single transaction, storing none when some world is invalid or cannot be stored
+func (s *WorldService) importAtomically(ctx context.Context, records []ImportedWorld, result *models.ImportResult, opts ImportOptions) ([]*models.World, error) {
+	for _, r := range result.Records {
+		if r.Error != "" {
+			result.RolledBack = true
+			return nil, nil
+		}
+	}
+
+	tx, err := s.dbConfig.DB.Begin(ctx)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	stored := make([]*models.World, len(records))
+	for i, rec := range records {
+		if err := insertImportedWorld(ctx, tx, rec.World, opts.PreserveIDs); err != nil {
+			result.Records[i].Error = importError(err, rec.World).Error()
+			result.RolledBack = true
+			return nil, nil
+		}
+		stored[i] = rec.World
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, storageError(err)
+	}
+	for i, w := range stored {
+		result.Records[i].ID = w.ID
+	}
+	return stored, nil
+}
+
+// insertImportedWorld stores a world, with its own ID when preserved and keeping its creation date when it has one.
+// Its version starts over, like the version of a generated world.
+func insertImportedWorld(ctx context.Context, db queryRower, w *models.World, preserveID bool) error {
+	var id *int
+	if preserveID && w.ID > 0 {
+		id = &w.ID
+	}
+	var createdAt *time.Time
+	if !w.CreatedAt.IsZero() {
+		createdAt = &w.CreatedAt
+	}
+
+	return db.QueryRow(ctx,
+		`INSERT INTO worlds(id, name, description, population, climate, features, theme,
+		                    fauna, flora, cultures, dangers, languages, religions, power_system, system_id, seed, created_at)
+		 VALUES(COALESCE($1::integer, nextval(pg_get_serial_sequence('worlds', 'id'))),
+		        $2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16, COALESCE($17::timestamp, NOW()))
+		 RETURNING id, created_at, version`,
+		id, w.Name, w.Description, w.Population, w.Climate, w.Features, w.Theme,
+		w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages, w.Religions, w.PowerSystem, w.SystemID, w.Seed, createdAt,
+	).Scan(&w.ID, &w.CreatedAt, &w.Version)
+}
+
+// importError describes why a world could not be stored
+func importError(err error, w *models.World) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return newError(ErrConflict, "world %d already exists", w.ID)
+		case "23503":
+			if w.SystemID != nil {
+				return newError(ErrValidation, "star system %d does not exist", *w.SystemID)
+			}
+		}
+	}
+	return fmt.Errorf("failed to save world: %w", storageError(err))
+}
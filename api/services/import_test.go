@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecodeImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		// names of the worlds read, empty for a world that could not be read
+		want    []string
+		wantErr bool
+	}{
+		{"json world", ImportFormatJSON, `{"name": "Aster"}`, []string{"Aster"}, false},
+		{"json list", ImportFormatJSON, `[{"name": "Aster"}, {"name": "Brin"}]`, []string{"Aster", "Brin"}, false},
+		{"json page", ImportFormatJSON, `{"data": [{"name": "Aster"}], "total": 1}`, []string{"Aster"}, false},
+		{"json empty document", ImportFormatJSON, "  ", nil, false},
+		{"json unknown field", ImportFormatJSON, `[{"name": "Aster"}, {"nmae": "Brin"}]`, []string{"Aster", ""}, false},
+		{"json wrong type", ImportFormatJSON, `[{"name": "Aster", "population": "many"}]`, []string{""}, false},
+		{"json malformed", ImportFormatJSON, `[{"name": "Aster"`, nil, true},
+		{"json scalar", ImportFormatJSON, `42`, nil, true},
+		{"json page without a list", ImportFormatJSON, `{"data": {"name": "Aster"}}`, nil, true},
+		{"ndjson", ImportFormatNDJSON, "{\"name\": \"Aster\"}\n\n{\"name\": \"Brin\"}\r\n", []string{"Aster", "Brin"}, false},
+		{"ndjson bad line", ImportFormatNDJSON, "{\"name\": \"Aster\"}\nnot json\n", []string{"Aster", ""}, false},
+		{"yaml world", ImportFormatYAML, "name: Aster\nfeatures: [Dunes]\n", []string{"Aster"}, false},
+		{"yaml documents", ImportFormatYAML, "name: Aster\n---\n- name: Brin\n- name: Cael\n---\n", []string{"Aster", "Brin", "Cael"}, false},
+		{"yaml page", ImportFormatYAML, "data:\n  - name: Aster\ntotal: 1\n", []string{"Aster"}, false},
+		{"yaml unknown field", ImportFormatYAML, "name: Aster\ncolour: blue\n", []string{""}, false},
+		{"yaml malformed", ImportFormatYAML, "name: [Aster\n", nil, true},
+		{"unknown format", "xml", "<world/>", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := DecodeImport(tt.format, strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("DecodeImport() error = %v, want a validation error", err)
+				}
+				return
+			}
+
+			if len(records) != len(tt.want) {
+				t.Fatalf("DecodeImport() read %d worlds, want %d", len(records), len(tt.want))
+			}
+			for i, rec := range records {
+				if tt.want[i] == "" {
+					if rec.Err == nil || !errors.Is(rec.Err, ErrValidation) {
+						t.Errorf("world %d: error = %v, want a validation error", i, rec.Err)
+					}
+					continue
+				}
+				if rec.Err != nil {
+					t.Fatalf("world %d: error = %v", i, rec.Err)
+				}
+				if rec.World.Name != tt.want[i] {
+					t.Errorf("world %d: name = %q, want %q", i, rec.World.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// Worlds exported as JSON or YAML are imported unchanged
+func TestDecodeImportRoundTrip(t *testing.T) {
+	seed := int64(5)
+	w, _ := buildWorld("sci-fi", &generateOptions{seed: &seed})
+	w.ID = 12
+	systemID := 3
+	w.SystemID = &systemID
+
+	encoded, err := json.Marshal([]*models.World{w})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generic any
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		t.Fatal(err)
+	}
+	yamlDoc, err := yaml.Marshal(generic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for format, doc := range map[string]string{ImportFormatJSON: string(encoded), ImportFormatYAML: string(yamlDoc)} {
+		t.Run(format, func(t *testing.T) {
+			records, err := DecodeImport(format, strings.NewReader(doc))
+			if err != nil || len(records) != 1 || records[0].Err != nil {
+				t.Fatalf("DecodeImport() = %+v, %v", records, err)
+			}
+			if !reflect.DeepEqual(records[0].World, w) {
+				t.Errorf("DecodeImport() = %+v, want %+v", records[0].World, w)
+			}
+		})
+	}
+}
+
+func TestValidateImportedWorld(t *testing.T) {
+	valid := func() *models.World {
+		return &models.World{Name: "Aster", Climate: "Arid", Features: []string{"Dunes"}}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(w *models.World)
+		wantErr bool
+	}{
+		{"valid", func(w *models.World) {}, false},
+		{"blank name", func(w *models.World) { w.Name = "  " }, true},
+		{"unknown theme", func(w *models.World) { w.Theme = "western" }, true},
+		{"unknown climate", func(w *models.World) { w.Climate = "Lava" }, true},
+		{"negative population", func(w *models.World) { w.Population = -1 }, true},
+		{"no features", func(w *models.World) { w.Features = nil }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid()
+			tt.modify(w)
+			err := validateImportedWorld(w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateImportedWorld() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (w.Theme != "fantasy" || w.Rarities["Dunes"] != RarityCommon) {
+				t.Errorf("validateImportedWorld() left theme %q and rarities %v", w.Theme, w.Rarities)
+			}
+		})
+	}
+}
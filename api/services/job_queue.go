@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Helper functions for the storage and queueing of jobs
+
+const (
+	jobStream       = "jobs"
+	jobGroup        = "job-workers"
+	jobDelayedKey   = "jobs:delayed"
+	jobTTL          = 24 * time.Hour
+	jobPollInterval = 2 * time.Second
+	jobClaimIdle    = 10 * time.Minute
+	// Leases are extended well before they become idle enough to be claimed
+	jobHeartbeatInterval = jobClaimIdle / 5
+	memoryQueueSize      = 1024
+	jobKeyPrefix         = "job:"
+)
+
+// jobBackend stores the state of jobs and queues their IDs for the workers
+type jobBackend interface {
+	Save(ctx context.Context, job *models.Job) error
+	Load(ctx context.Context, id string) (*models.Job, error)
+	// Enqueue makes the job available to the workers once the delay has passed
+	Enqueue(ctx context.Context, id string, delay time.Duration) error
+	// Dequeue blocks until a job is available, returning the lease the worker holds while processing it
+	Dequeue(ctx context.Context) (*jobLease, error)
+	Cancel(ctx context.Context, id string) error
+	Cancelled(ctx context.Context, id string) (bool, error)
+}
+
+// jobLease is a job taken by a worker
+type jobLease struct {
+	id string
+	// extend keeps other workers from claiming the job while it runs
+	extend func(ctx context.Context) error
+	// done releases the job once it has been processed
+	done func()
+}
+
+// redisJobBackend shares jobs between every instance of the API through a Redis stream.
+// Delayed jobs wait in a sorted set until they are due, and jobs left pending by a
+// crashed worker are claimed again after jobClaimIdle. Running jobs are kept from
+// being claimed by a heartbeat that resets their idle time.
+type redisJobBackend struct {
+	client   *redis.Client
+	consumer string
+}
+
+func newRedisJobBackend(client *redis.Client) *redisJobBackend {
+	hostname, _ := os.Hostname()
+	b := &redisJobBackend{
+		client:   client,
+		consumer: hostname + "-" + strconv.Itoa(os.Getpid()),
+	}
+
+	err := client.XGroupCreateMkStream(context.Background(), jobStream, jobGroup, "0").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		log.Printf("Error creating job consumer group: %v", err)
+	}
+	return b
+}
+
+func (b *redisJobBackend) Save(ctx context.Context, job *models.Job) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.client.Set(ctx, jobKeyPrefix+job.ID, jobJSON, jobTTL).Err()
+}
+
+func (b *redisJobBackend) Load(ctx context.Context, id string) (*models.Job, error) {
+	jobJSON, err := b.client.Get(ctx, jobKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		return nil, ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var job models.Job
+	if err := json.Unmarshal(jobJSON, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (b *redisJobBackend) Enqueue(ctx context.Context, id string, delay time.Duration) error {
+	if delay > 0 {
+		due := float64(time.Now().Add(delay).UnixMilli())
+		return b.client.ZAdd(ctx, jobDelayedKey, redis.Z{Score: due, Member: id}).Err()
+	}
+	return b.client.XAdd(ctx, &redis.XAddArgs{Stream: jobStream, Values: map[string]interface{}{"id": id}}).Err()
+}
+
+func (b *redisJobBackend) Dequeue(ctx context.Context) (*jobLease, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		b.promoteDelayed(ctx)
+
+		// Take back jobs that a crashed worker never acknowledged
+		msgs, _, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream: jobStream, Group: jobGroup, Consumer: b.consumer,
+			MinIdle: jobClaimIdle, Start: "0-0", Count: 1,
+		}).Result()
+		if err == nil && len(msgs) > 0 {
+			return b.message(ctx, msgs[0])
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group: jobGroup, Consumer: b.consumer,
+			Streams: []string{jobStream, ">"}, Count: 1, Block: jobPollInterval,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			return b.message(ctx, streams[0].Messages[0])
+		}
+	}
+}
+
+// message extracts the job ID of a stream message, acknowledging it once the job is processed.
+// Claiming the message again for the same consumer resets its idle time.
+func (b *redisJobBackend) message(ctx context.Context, msg redis.XMessage) (*jobLease, error) {
+	ack := func() {
+		b.client.XAck(context.Background(), jobStream, jobGroup, msg.ID)
+	}
+	id, ok := msg.Values["id"].(string)
+	if !ok {
+		ack()
+		return nil, fmt.Errorf("malformed job message %s", msg.ID)
+	}
+
+	extend := func(ctx context.Context) error {
+		return b.client.XClaimJustID(ctx, &redis.XClaimArgs{
+			Stream: jobStream, Group: jobGroup, Consumer: b.consumer, Messages: []string{msg.ID},
+		}).Err()
+	}
+	return &jobLease{id: id, extend: extend, done: ack}, nil
+}
+
+// promoteDelayed moves the delayed jobs that are due to the stream.
+// Removing a job from the sorted set first ensures only one instance promotes it.
+func (b *redisJobBackend) promoteDelayed(ctx context.Context) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	ids, err := b.client.ZRangeByScore(ctx, jobDelayedKey, &redis.ZRangeBy{Min: "-inf", Max: now}).Result()
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		if removed, err := b.client.ZRem(ctx, jobDelayedKey, id).Result(); err == nil && removed == 1 {
+			b.Enqueue(ctx, id, 0)
+		}
+	}
+}
+
+func (b *redisJobBackend) Cancel(ctx context.Context, id string) error {
+	return b.client.Set(ctx, jobKeyPrefix+id+":cancelled", "1", jobTTL).Err()
+}
+
+func (b *redisJobBackend) Cancelled(ctx context.Context, id string) (bool, error) {
+	n, err := b.client.Exists(ctx, jobKeyPrefix+id+":cancelled").Result()
+	return n > 0, err
+}
+
+// memoryJobBackend keeps jobs in process when Redis is not available.
+// Jobs are lost on restart and are not shared between instances.
+type memoryJobBackend struct {
+	mu        sync.Mutex
+	jobs      map[string][]byte
+	cancelled map[string]bool
+	queue     chan string
+}
+
+func newMemoryJobBackend() *memoryJobBackend {
+	return &memoryJobBackend{
+		jobs:      make(map[string][]byte),
+		cancelled: make(map[string]bool),
+		queue:     make(chan string, memoryQueueSize),
+	}
+}
+
+// Jobs are stored serialized so callers never share state with the workers
+func (b *memoryJobBackend) Save(ctx context.Context, job *models.Job) error {
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.jobs[job.ID] = jobJSON
+	return nil
+}
+
+// prune forgets the jobs not updated for longer than jobTTL, as Redis expires them
+func (b *memoryJobBackend) prune() {
+	for id, jobJSON := range b.jobs {
+		var job models.Job
+		if json.Unmarshal(jobJSON, &job) == nil && time.Since(job.UpdatedAt) > jobTTL {
+			delete(b.jobs, id)
+			delete(b.cancelled, id)
+		}
+	}
+}
+
+func (b *memoryJobBackend) Load(ctx context.Context, id string) (*models.Job, error) {
+	b.mu.Lock()
+	jobJSON, ok := b.jobs[id]
+	b.mu.Unlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	var job models.Job
+	if err := json.Unmarshal(jobJSON, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (b *memoryJobBackend) Enqueue(ctx context.Context, id string, delay time.Duration) error {
+	b.mu.Lock()
+	b.prune()
+	b.mu.Unlock()
+
+	push := func() error {
+		select {
+		case b.queue <- id:
+			return nil
+		default:
+			return ErrJobQueueFull
+		}
+	}
+	if delay > 0 {
+		if len(b.queue) == cap(b.queue) {
+			return ErrJobQueueFull
+		}
+		// The queue may have filled up by the time the job is due
+		time.AfterFunc(delay, func() {
+			if err := push(); err != nil {
+				b.fail(id, err)
+			}
+		})
+		return nil
+	}
+	return push()
+}
+
+// fail marks a job that could not be queued as failed
+func (b *memoryJobBackend) fail(id string, err error) {
+	ctx := context.Background()
+	job, loadErr := b.Load(ctx, id)
+	if loadErr != nil {
+		return
+	}
+	job.Status, job.Error, job.NextAttemptAt = JobFailed, err.Error(), nil
+	job.UpdatedAt = time.Now().UTC()
+	b.Save(ctx, job)
+}
+
+// Jobs run in the process that holds them, so no other worker can claim them
+func (b *memoryJobBackend) Dequeue(ctx context.Context) (*jobLease, error) {
+	select {
+	case id := <-b.queue:
+		return &jobLease{id: id, extend: func(context.Context) error { return nil }, done: func() {}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *memoryJobBackend) Cancel(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cancelled[id] = true
+	return nil
+}
+
+func (b *memoryJobBackend) Cancelled(ctx context.Context, id string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cancelled[id], nil
+}
@@ -0,0 +1,549 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Job types. Worlds have no history to simulate in this tree; the market
+// simulation of a world runs as an economy job.
+const (
+	JobTypeWorld   = "world"
+	JobTypeBatch   = "batch"
+	JobTypeEconomy = "economy"
+	JobTypeMap     = "map"
+)
+
+// Job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobRetrying  = "retrying"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+const (
+	defaultJobAttempts = 3
+	maxJobAttempts     = 10
+	jobRetryBase       = 2 * time.Second
+	jobRetryMax        = time.Minute
+)
+
+// Errors returned when a job cannot be retrieved or cancelled
+var (
+	ErrJobNotFound  = newError(ErrNotFound, "job not found")
+	ErrJobFinished  = newError(ErrConflict, "job already finished")
+	ErrJobQueueFull = newError(ErrUnavailable, "job queue is full")
+)
+
+// jobHandler validates and runs the jobs of a type
+type jobHandler struct {
+	// validate checks the parameters when the job is created and returns its rate limit weight
+	validate func(params json.RawMessage) (int, error)
+	// run runs an attempt at the job and returns its result
+	run func(ctx context.Context, run *jobRun) (interface{}, error)
+}
+
+// jobRun is an attempt at running a job
+type jobRun struct {
+	params json.RawMessage
+	// progress reports the progress of the job from 0 to 100
+	progress func(int)
+	// checkpoint holds the state recorded by the previous attempts, empty on the first one
+	checkpoint json.RawMessage
+	// record saves the state the next attempts resume from
+	record func(state interface{}) error
+}
+
+// JobService runs expensive generations in the background.
+// Jobs are queued in a Redis stream, or in process when Redis is not available.
+type JobService struct {
+	backend         jobBackend
+	appConfig       *config.AppConfig
+	worldService    *WorldService
+	locationService *LocationService
+	handlers        map[string]jobHandler
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// NewJobService creates a new instance of the service
+func NewJobService(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, worldService *WorldService, locationService *LocationService) *JobService {
+	var backend jobBackend
+	if dbConfig.RedisClient != nil {
+		backend = newRedisJobBackend(dbConfig.RedisClient)
+	} else {
+		backend = newMemoryJobBackend()
+	}
+
+	s := &JobService{
+		backend:         backend,
+		appConfig:       appConfig,
+		worldService:    worldService,
+		locationService: locationService,
+		running:         make(map[string]context.CancelFunc),
+	}
+	s.handlers = map[string]jobHandler{
+		JobTypeWorld:   {validate: validateWorldJob, run: s.runWorldJob},
+		JobTypeBatch:   {validate: s.validateBatchJob, run: s.runBatchJob},
+		JobTypeEconomy: {validate: validateEconomyJob, run: s.runEconomyJob},
+		JobTypeMap:     {validate: validateMapJob, run: s.runMapJob},
+	}
+	return s
+}
+
+// Start launches the workers, which stop when the context is done
+func (s *JobService) Start(ctx context.Context) {
+	workers := s.appConfig.JobWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go s.work(ctx)
+	}
+}
+
+// ValidateJobRequest checks the type and parameters of a job, returning its rate limit weight
+func (s *JobService) ValidateJobRequest(req models.CreateJobRequest) (int, error) {
+	handler, ok := s.handlers[req.Type]
+	if !ok {
+		return 0, newError(ErrValidation, "unknown job type %q, expected %s, %s, %s or %s",
+			req.Type, JobTypeWorld, JobTypeBatch, JobTypeEconomy, JobTypeMap)
+	}
+	if req.MaxAttempts < 0 || req.MaxAttempts > maxJobAttempts {
+		return 0, newError(ErrValidation, "max_attempts must be between 1 and %d", maxJobAttempts)
+	}
+	return handler.validate(req.Params)
+}
+
+// CreateJob validates and enqueues a job
+func (s *JobService) CreateJob(ctx context.Context, req models.CreateJobRequest) (*models.Job, error) {
+	if _, err := s.ValidateJobRequest(req); err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	job := &models.Job{
+		ID:          id,
+		Type:        req.Type,
+		Status:      JobQueued,
+		Params:      req.Params,
+		MaxAttempts: req.MaxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = defaultJobAttempts
+	}
+
+	if err := s.backend.Save(ctx, job); err != nil {
+		return nil, err
+	}
+	if err := s.backend.Enqueue(ctx, job.ID, 0); err != nil {
+		job.Status, job.Error = JobFailed, err.Error()
+		s.save(ctx, job)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetJob retrieves the status, progress and result of a job
+func (s *JobService) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.backend.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Checkpoint = nil
+
+	// Workers may not have seen the cancellation yet
+	if !jobFinished(job) {
+		if cancelled, err := s.backend.Cancelled(ctx, id); err == nil && cancelled {
+			job.Status = JobCancelled
+		}
+	}
+	return job, nil
+}
+
+// CancelJob stops a job, whether it is still queued, waiting for a retry or running
+func (s *JobService) CancelJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.backend.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if jobFinished(job) {
+		return nil, ErrJobFinished
+	}
+
+	if err := s.backend.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Stop the job right away when it runs on this instance; others notice on their next progress update
+	s.mu.Lock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	job.Status = JobCancelled
+	job.UpdatedAt = time.Now().UTC()
+	job.Checkpoint = nil
+	return job, nil
+}
+
+// work processes jobs until the context is done
+func (s *JobService) work(ctx context.Context) {
+	for {
+		lease, err := s.backend.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error dequeuing job: %v", err)
+			time.Sleep(jobPollInterval)
+			continue
+		}
+
+		stop := s.heartbeat(ctx, lease)
+		s.process(ctx, lease.id)
+		stop()
+		lease.done()
+	}
+}
+
+// heartbeat extends the lease of a job until stop is called, so that long jobs are not claimed by another worker
+func (s *JobService) heartbeat(ctx context.Context, lease *jobLease) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(jobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := lease.extend(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("Error extending the lease of job %s: %v", lease.id, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return cancel
+}
+
+// process runs a job once, scheduling a retry with exponential backoff when it fails
+func (s *JobService) process(ctx context.Context, id string) {
+	job, err := s.backend.Load(ctx, id)
+	if err != nil {
+		log.Printf("Error loading job %s: %v", id, err)
+		return
+	}
+	if s.isCancelled(ctx, job) || jobFinished(job) {
+		return
+	}
+
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		job.Status, job.Error = JobFailed, fmt.Sprintf("unknown job type %q", job.Type)
+		s.save(ctx, job)
+		return
+	}
+
+	job.Status = JobRunning
+	job.Attempts++
+	job.NextAttemptAt = nil
+	s.save(ctx, job)
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, id)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	progress := func(percent int) {
+		if s.isCancelled(ctx, job) {
+			cancel()
+			return
+		}
+		if percent != job.Progress {
+			job.Progress = percent
+			s.save(ctx, job)
+		}
+	}
+
+	// Checkpoints are saved right away, since retries rely on them to skip the work already done
+	record := func(state interface{}) error {
+		checkpoint, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		job.Checkpoint = checkpoint
+		job.UpdatedAt = time.Now().UTC()
+		return s.backend.Save(ctx, job)
+	}
+
+	result, err := handler.run(jobCtx, &jobRun{params: job.Params, progress: progress, checkpoint: job.Checkpoint, record: record})
+	if s.isCancelled(ctx, job) {
+		return
+	}
+
+	if err != nil {
+		job.Error = err.Error()
+		if job.Attempts >= job.MaxAttempts {
+			job.Status = JobFailed
+			s.save(ctx, job)
+			return
+		}
+
+		delay := min(jobRetryBase<<(job.Attempts-1), jobRetryMax)
+		next := time.Now().UTC().Add(delay)
+		job.Status = JobRetrying
+		job.NextAttemptAt = &next
+		s.save(ctx, job)
+		if err := s.backend.Enqueue(ctx, id, delay); err != nil {
+			log.Printf("Error scheduling retry of job %s: %v", id, err)
+			job.Status, job.Error, job.NextAttemptAt = JobFailed, err.Error(), nil
+			s.save(ctx, job)
+		}
+		return
+	}
+
+	job.Result, err = json.Marshal(result)
+	if err != nil {
+		job.Status, job.Error = JobFailed, err.Error()
+	} else {
+		job.Status, job.Error, job.Progress, job.Checkpoint = JobSucceeded, "", 100, nil
+	}
+	s.save(ctx, job)
+}
+
+// isCancelled checks whether the job was cancelled, recording it in the job state
+func (s *JobService) isCancelled(ctx context.Context, job *models.Job) bool {
+	cancelled, err := s.backend.Cancelled(ctx, job.ID)
+	if err != nil || !cancelled {
+		return false
+	}
+	if job.Status != JobCancelled {
+		job.Status = JobCancelled
+		s.save(ctx, job)
+	}
+	return true
+}
+
+// save stores the job state, logging failures so that the job keeps running
+func (s *JobService) save(ctx context.Context, job *models.Job) {
+	job.UpdatedAt = time.Now().UTC()
+	if err := s.backend.Save(ctx, job); err != nil {
+		log.Printf("Error saving job %s: %v", job.ID, err)
+	}
+}
+
+// jobFinished reports whether the job reached a final status
+func jobFinished(job *models.Job) bool {
+	return job.Status == JobSucceeded || job.Status == JobFailed || job.Status == JobCancelled
+}
+
+// newJobID returns a random job ID
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Job handlers
+
+func validateWorldJob(params json.RawMessage) (int, error) {
+	var p models.WorldJobParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return 0, err
+	}
+	if p.Theme != "" && !validateTheme(p.Theme) {
+		return 0, newError(ErrValidation, "unknown theme %q", p.Theme)
+	}
+	return 1, nil
+}
+
+func (s *JobService) runWorldJob(ctx context.Context, run *jobRun) (interface{}, error) {
+	var p models.WorldJobParams
+	if err := decodeJobParams(run.params, &p); err != nil {
+		return nil, err
+	}
+
+	var opts []GenerateOption
+	if p.Seed != nil {
+		opts = append(opts, WithSeed(*p.Seed))
+	}
+	return s.worldService.GenerateWorld(ctx, p.Theme, opts...)
+}
+
+// Batch jobs are charged to the rate limit like the batch endpoint
+func (s *JobService) validateBatchJob(params json.RawMessage) (int, error) {
+	var req models.BatchGenerateRequest
+	if err := decodeJobParams(params, &req); err != nil {
+		return 0, err
+	}
+	if err := s.worldService.ValidateBatchRequest(&req); err != nil {
+		return 0, err
+	}
+	return req.Count, nil
+}
+
+// batchCheckpoint records the progress of a batch job. Retries generate the same
+// batch from its seed and skip the worlds already saved, keyed by their index.
+type batchCheckpoint struct {
+	Seed   int64                 `json:"seed"`
+	Worlds map[int]*models.World `json:"worlds,omitempty"`
+}
+
+func (s *JobService) runBatchJob(ctx context.Context, run *jobRun) (interface{}, error) {
+	var req models.BatchGenerateRequest
+	if err := decodeJobParams(run.params, &req); err != nil {
+		return nil, err
+	}
+
+	var checkpoint batchCheckpoint
+	if len(run.checkpoint) > 0 {
+		if err := json.Unmarshal(run.checkpoint, &checkpoint); err != nil {
+			return nil, err
+		}
+	} else {
+		// Pin the seed before saving anything, so that a retry reproduces the batch
+		checkpoint.Seed = batchSeed(req)
+		if err := run.record(checkpoint); err != nil {
+			return nil, err
+		}
+	}
+	if checkpoint.Worlds == nil {
+		checkpoint.Worlds = make(map[int]*models.World, req.Count)
+	}
+	req.Seed = &checkpoint.Seed
+
+	skip := make(map[int]bool, len(checkpoint.Worlds))
+	for i := range checkpoint.Worlds {
+		skip[i] = true
+	}
+	run.progress(len(checkpoint.Worlds) * 100 / max(req.Count, 1))
+
+	// A chunk saved right before the checkpoint fails to be recorded is the only one a retry saves again
+	err := s.worldService.generateBatch(ctx, req, skip, func(chunk []batchWorld) error {
+		for _, bw := range chunk {
+			checkpoint.Worlds[bw.index] = bw.world
+		}
+		if err := run.record(checkpoint); err != nil {
+			return err
+		}
+		run.progress(len(checkpoint.Worlds) * 100 / req.Count)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	worlds := make([]*models.World, 0, len(checkpoint.Worlds))
+	for i := 0; i < req.Count; i++ {
+		if w, ok := checkpoint.Worlds[i]; ok {
+			worlds = append(worlds, w)
+		}
+	}
+	return worlds, nil
+}
+
+func validateEconomyJob(params json.RawMessage) (int, error) {
+	var p models.EconomyJobParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return 0, err
+	}
+	if p.WorldID <= 0 {
+		return 0, newError(ErrValidation, "a world_id is required")
+	}
+	if p.Turns < 0 || p.Turns > MaxEconomyTurns {
+		return 0, newError(ErrValidation, "turns must be between 0 and %d", MaxEconomyTurns)
+	}
+	return 1, nil
+}
+
+func (s *JobService) runEconomyJob(ctx context.Context, run *jobRun) (interface{}, error) {
+	var p models.EconomyJobParams
+	if err := decodeJobParams(run.params, &p); err != nil {
+		return nil, err
+	}
+
+	w, err := s.worldService.GetWorldByID(ctx, p.WorldID)
+	if err != nil {
+		return nil, err
+	}
+	run.progress(50)
+	return s.worldService.GenerateEconomy(w, p.Turns), nil
+}
+
+// decodeJobParams decodes the parameters of a job, which may be omitted
+func decodeJobParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return newError(ErrValidation, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func validateMapJob(params json.RawMessage) (int, error) {
+	var p models.MapJobParams
+	if err := decodeJobParams(params, &p); err != nil {
+		return 0, err
+	}
+	if p.WorldID <= 0 || p.LocationID <= 0 {
+		return 0, newError(ErrValidation, "a world_id and a location_id are required")
+	}
+	if p.Format != "" && p.Format != LocationFormatASCII && p.Format != LocationFormatSVG {
+		return 0, newError(ErrValidation, "invalid format %q, expected %s or %s", p.Format, LocationFormatASCII, LocationFormatSVG)
+	}
+	return 1, nil
+}
+
+func (s *JobService) runMapJob(ctx context.Context, run *jobRun) (interface{}, error) {
+	var p models.MapJobParams
+	if err := decodeJobParams(run.params, &p); err != nil {
+		return nil, err
+	}
+	if p.Format == "" {
+		p.Format = LocationFormatSVG
+	}
+
+	location, err := s.locationService.GetLocationByID(ctx, p.WorldID, p.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	run.progress(50)
+
+	rendered, err := s.locationService.RenderLocation(location, p.Format)
+	if err != nil {
+		return nil, err
+	}
+	return models.MapJobResult{Format: p.Format, Map: rendered}, nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+func newTestJobService() *JobService {
+	db := &config.DatabaseConfig{}
+	app := config.NewAppConfig()
+	return NewJobService(db, app, NewWorldService(db, app, NewEventBus(db)), NewLocationService(db, app))
+}
+
+func TestValidateMapJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr bool
+	}{
+		{"default format", `{"world_id": 1, "location_id": 2}`, false},
+		{"ascii", `{"world_id": 1, "location_id": 2, "format": "ascii"}`, false},
+		{"svg", `{"world_id": 1, "location_id": 2, "format": "svg"}`, false},
+		{"missing location", `{"world_id": 1}`, true},
+		{"missing world", `{"location_id": 2}`, true},
+		{"unknown format", `{"world_id": 1, "location_id": 2, "format": "png"}`, true},
+		{"malformed", `{"world_id": "one"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateMapJob(json.RawMessage(tt.params))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateMapJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("validateMapJob() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestMemoryJobBackendRefusesJobsWhenFull(t *testing.T) {
+	b := newMemoryJobBackend()
+	ctx := context.Background()
+	for i := 0; i < memoryQueueSize; i++ {
+		if err := b.Enqueue(ctx, "queued", 0); err != nil {
+			t.Fatalf("Enqueue() error = %v with %d queued jobs", err, i)
+		}
+	}
+
+	if err := b.Enqueue(ctx, "now", 0); !errors.Is(err, ErrJobQueueFull) {
+		t.Errorf("Enqueue() error = %v, want ErrJobQueueFull", err)
+	}
+	if err := b.Enqueue(ctx, "later", time.Minute); !errors.Is(err, ErrJobQueueFull) {
+		t.Errorf("Enqueue() with a delay error = %v, want ErrJobQueueFull", err)
+	}
+}
+
+func TestMemoryJobBackendFailsDelayedJobsWhenFull(t *testing.T) {
+	b := newMemoryJobBackend()
+	ctx := context.Background()
+	job := &models.Job{ID: "delayed", Status: JobRetrying, UpdatedAt: time.Now().UTC()}
+	if err := b.Save(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Enqueue(ctx, job.ID, 20*time.Millisecond); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	// The queue fills up before the job is due
+	for len(b.queue) < cap(b.queue) {
+		b.queue <- "queued"
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		got, err := b.Load(ctx, job.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status == JobFailed {
+			if got.Error != ErrJobQueueFull.Error() {
+				t.Errorf("job error = %q, want %q", got.Error, ErrJobQueueFull.Error())
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("the delayed job was not marked as failed")
+}
+
+func TestRunBatchJobResumesFromCheckpoint(t *testing.T) {
+	s := newTestJobService()
+	ctx := context.Background()
+	params := json.RawMessage(`{"count": 12, "themes": {"fantasy": 1, "sci-fi": 1}}`)
+
+	var checkpoint json.RawMessage
+	newRun := func() *jobRun {
+		return &jobRun{
+			params:     params,
+			progress:   func(int) {},
+			checkpoint: checkpoint,
+			record: func(state interface{}) error {
+				var err error
+				checkpoint, err = json.Marshal(state)
+				return err
+			},
+		}
+	}
+
+	first, err := s.runBatchJob(ctx, newRun())
+	if err != nil {
+		t.Fatalf("runBatchJob() error = %v", err)
+	}
+	worlds := first.([]*models.World)
+
+	// Forget the last worlds, as if the first attempt had failed halfway through
+	var state batchCheckpoint
+	if err := json.Unmarshal(checkpoint, &state); err != nil {
+		t.Fatal(err)
+	}
+	kept := map[int]*models.World{}
+	for i := 0; i < 5; i++ {
+		kept[i] = state.Worlds[i]
+		kept[i].Name = "Saved " + kept[i].Name
+	}
+	state.Worlds = kept
+	if checkpoint, err = json.Marshal(state); err != nil {
+		t.Fatal(err)
+	}
+
+	retried, err := s.runBatchJob(ctx, newRun())
+	if err != nil {
+		t.Fatalf("runBatchJob() retry error = %v", err)
+	}
+	resumed := retried.([]*models.World)
+	if len(resumed) != len(worlds) {
+		t.Fatalf("retry returned %d worlds, want %d", len(resumed), len(worlds))
+	}
+	for i, w := range resumed {
+		want := worlds[i].Name
+		if i < len(kept) {
+			// Worlds already saved are kept rather than generated and saved again
+			want = "Saved " + want
+		}
+		if w.Name != want {
+			t.Errorf("world %d = %q, want %q", i, w.Name, want)
+		}
+	}
+}
@@ -0,0 +1,537 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for point of interest generation
+
+const (
+	locationWidth  = 48
+	locationHeight = 24
+)
+
+// Location types
+const (
+	LocationDungeon  = "dungeon"
+	LocationRuins    = "ruins"
+	LocationTemple   = "temple"
+	LocationCaverns  = "caverns"
+	LocationLair     = "lair"
+	LocationBunker   = "bunker"
+	LocationFacility = "facility"
+)
+
+// Layout rendering formats
+const (
+	LocationFormatASCII = "ascii"
+	LocationFormatSVG   = "svg"
+)
+
+// Cells of a location grid
+const (
+	cellRock byte = iota
+	cellFloor
+	cellCorridor
+)
+
+// Keywords in dangers and features that suggest a location type, checked in order
+var locationTypeKeywords = []struct {
+	Type     string
+	Keywords []string
+}{
+	{LocationTemple, []string{"temple", "guardian", "shrine", "spirit", "sanctum"}},
+	{LocationRuins, []string{"ruin", "curse", "infrastructure", "stone"}},
+	{LocationBunker, []string{"cache", "weapon", "bunker", "radiation", "bandit", "raider", "camp"}},
+	{LocationCaverns, []string{"cave", "cavern", "underground", "pit", "quicksand"}},
+	{LocationLair, []string{"dragon", "giant", "monster", "predator", "siren"}},
+	{LocationFacility, []string{"machine", "nanobot", "nanite", "defense system", "bioweapon", "field"}},
+}
+
+var defaultLocationByTheme = map[string]string{
+	"fantasy":          LocationDungeon,
+	"sci-fi":           LocationFacility,
+	"post-apocalyptic": LocationBunker,
+}
+
+var locationNameTemplates = map[string][]string{
+	LocationDungeon:  {"Dungeon of %s", "The %s Keep"},
+	LocationRuins:    {"Ruins of %s", "The Fallen Halls of %s"},
+	LocationTemple:   {"Temple of %s", "Sanctum of %s"},
+	LocationCaverns:  {"Caves of %s", "The %s Hollows"},
+	LocationLair:     {"Lair of %s", "The %s Den"},
+	LocationBunker:   {"Bunker %s", "The %s Vault"},
+	LocationFacility: {"%s Research Station", "Outpost %s"},
+}
+
+// Room names of each location type; the first is the entrance and the last the innermost room
+var roomNamesByType = map[string][]string{
+	LocationDungeon:  {"Iron gate", "Guard room", "Torture chamber", "Cells", "Storeroom", "Crypt", "Shrine", "Vault"},
+	LocationRuins:    {"Collapsed gate", "Overgrown courtyard", "Great hall", "Crumbling library", "Flooded cellar", "Barracks", "Watchtower base", "Throne room"},
+	LocationTemple:   {"Pillared portico", "Hall of offerings", "Cloister", "Priests' quarters", "Reliquary", "Ossuary", "Meditation chamber", "Inner sanctum"},
+	LocationCaverns:  {"Cave mouth", "Dripping gallery", "Fungus grotto", "Underground stream", "Crystal chamber", "Bone pit", "Narrow chimney", "Deep hollow"},
+	LocationLair:     {"Trampled entrance", "Gnawed-bone tunnel", "Nest", "Larder", "Sleeping hollow", "Watering hole", "Spoils pile", "Heart of the lair"},
+	LocationBunker:   {"Blast door", "Decontamination room", "Mess hall", "Dormitory", "Armory", "Generator room", "Infirmary", "Command center"},
+	LocationFacility: {"Airlock", "Security checkpoint", "Laboratory", "Server room", "Crew quarters", "Reactor bay", "Specimen storage", "Control core"},
+}
+
+var roomDetailsByTheme = map[string][]string{
+	"fantasy": {
+		"Torches gutter in rusted sconces", "Faded murals cover the walls", "The floor is littered with old bones",
+		"Cold water pools ankle-deep", "Cobwebs hang thick from the ceiling", "A faint chanting echoes from nowhere",
+		"Roots have split the flagstones", "Scorch marks blacken one wall",
+	},
+	"sci-fi": {
+		"Emergency lights pulse red", "Consoles flicker with corrupted data", "The air recyclers have failed",
+		"Cables hang from a torn ceiling", "Frost coats every surface", "A maintenance drone circles uselessly",
+		"Bulkheads are sealed half-shut", "Alien growths cover the panels",
+	},
+	"post-apocalyptic": {
+		"Rusted shelves lie toppled", "Graffiti warns intruders away", "The Geiger counter ticks steadily",
+		"Water drips through cracked concrete", "Skeletons sit where they fell", "Scavenged junk blocks half the room",
+		"A generator hums somewhere nearby", "Barricades of old furniture line the walls",
+	},
+}
+
+var treasureByTheme = map[string][]models.Treasure{
+	"fantasy": {
+		{Name: "Gold coins", Value: 50}, {Name: "Healing potions", Value: 80}, {Name: "Silver chalice", Value: 120},
+		{Name: "Ancient tome", Value: 300}, {Name: "Runed amulet", Value: 450}, {Name: "Enchanted blade", Value: 600},
+		{Name: "Jeweled crown", Value: 1000},
+	},
+	"sci-fi": {
+		{Name: "Credit chips", Value: 50}, {Name: "Medical nanites", Value: 150}, {Name: "Quantum battery", Value: 250},
+		{Name: "Navigation charts", Value: 300}, {Name: "Encrypted data core", Value: 400}, {Name: "Prototype weapon", Value: 700},
+		{Name: "Alien artifact", Value: 1000},
+	},
+	"post-apocalyptic": {
+		{Name: "Canned food", Value: 20}, {Name: "Ammunition", Value: 60}, {Name: "Medkit", Value: 90},
+		{Name: "Fuel canister", Value: 120}, {Name: "Water purifier", Value: 250}, {Name: "Working radio", Value: 300},
+		{Name: "Pre-war power cell", Value: 500},
+	},
+}
+
+// History templates receive the location name, its builders, feature, age and danger, in that order
+var locationHistoryTemplates = []string{
+	"%[1]s was built by %[2]s among the %[3]s %[4]d years ago. It was abandoned when the %[5]s claimed it, and few who enter return.",
+	"For %[4]d years %[1]s has stood silent among the %[3]s. Those who remember %[2]s say the %[5]s drove them out.",
+	"%[2]s sealed %[1]s %[4]d years ago to contain the %[5]s. The seals near the %[3]s are failing.",
+}
+
+// locationGrid is the cell grid a location layout is carved from
+type locationGrid [locationHeight][locationWidth]byte
+
+// randomLocation generates a point of interest of a world built around a danger and a feature
+func randomLocation(rng *rand.Rand, w *models.World, danger, feature string) *models.Location {
+	locationType := locationTypeFor(w.Theme, danger, feature)
+	templates := locationNameTemplates[locationType]
+	name := fmt.Sprintf(templates[rng.Intn(len(templates))], languageWord(rng, pickLanguage(rng, w.Languages, w.Theme)))
+
+	layout := generateLayout(rng, roomNamesByType[locationType])
+	innermost := layout.Rooms[len(layout.Rooms)-1].ID
+
+	details := roomDetailsByTheme[w.Theme]
+	if details == nil {
+		details = roomDetailsByTheme["fantasy"]
+	}
+	for i := range layout.Rooms {
+		room := &layout.Rooms[i]
+		room.Description = details[rng.Intn(len(details))] + "."
+		if room.ID == innermost && danger != "" {
+			room.Description += fmt.Sprintf(" The source of the %s lurks here.", strings.ToLower(danger))
+		}
+	}
+
+	return &models.Location{
+		WorldID:     w.ID,
+		Name:        name,
+		Type:        locationType,
+		Danger:      danger,
+		Feature:     feature,
+		History:     locationHistory(rng, name, w.Cultures, feature, danger),
+		Layout:      layout,
+		Inhabitants: locationInhabitants(rng, w, danger, layout.Rooms),
+		Treasure:    locationTreasure(rng, w.Theme, layout.Rooms),
+	}
+}
+
+// locationTypeFor picks the location type suggested by the danger or the feature, falling back to the theme default
+func locationTypeFor(theme, danger, feature string) string {
+	for _, text := range []string{danger, feature} {
+		for _, candidate := range locationTypeKeywords {
+			if containsAnyKeyword(text, candidate.Keywords) {
+				return candidate.Type
+			}
+		}
+	}
+
+	if locationType, ok := defaultLocationByTheme[theme]; ok {
+		return locationType
+	}
+	return LocationDungeon
+}
+
+// locationHistory writes the short history of a location
+func locationHistory(rng *rand.Rand, name string, cultures []string, feature, danger string) string {
+	builders := "forgotten builders"
+	if len(cultures) > 0 {
+		builders = "the " + strings.ToLower(cultures[rng.Intn(len(cultures))])
+	}
+	if danger == "" {
+		danger = "darkness"
+	}
+
+	tmpl := locationHistoryTemplates[rng.Intn(len(locationHistoryTemplates))]
+	years := 20 + rng.Intn(980)
+	return capitalize(fmt.Sprintf(tmpl, name, builders, strings.ToLower(feature), years, strings.ToLower(danger)))
+}
+
+// locationInhabitants places the danger in the innermost room and groups of the world's fauna in the others
+func locationInhabitants(rng *rand.Rand, w *models.World, danger string, rooms []models.Room) []models.Inhabitant {
+	inhabitants := []models.Inhabitant{}
+	innermost := rooms[len(rooms)-1].ID
+
+	if danger != "" {
+		inhabitants = append(inhabitants, models.Inhabitant{
+			Name:    danger,
+			Origin:  "danger",
+			Count:   1 + rng.Intn(3),
+			Room:    innermost,
+			Hostile: true,
+		})
+	}
+
+	// A second danger of the world sometimes wanders in
+	for _, other := range w.Dangers {
+		if other != danger && rng.Float64() < 0.3 {
+			inhabitants = append(inhabitants, models.Inhabitant{
+				Name:    other,
+				Origin:  "danger",
+				Count:   1 + rng.Intn(2),
+				Room:    rooms[1+rng.Intn(len(rooms)-1)].ID,
+				Hostile: true,
+			})
+			break
+		}
+	}
+
+	for _, creature := range randomWithoutDuplicatesFrom(rng, w.Fauna, 1+rng.Intn(3)) {
+		inhabitants = append(inhabitants, models.Inhabitant{
+			Name:    creature,
+			Origin:  "fauna",
+			Count:   1 + rng.Intn(6),
+			Room:    rooms[rng.Intn(len(rooms))].ID,
+			Hostile: rng.Float64() < 0.5,
+		})
+	}
+
+	return inhabitants
+}
+
+// locationTreasure hides the most valuable item in the innermost room and scatters the rest
+func locationTreasure(rng *rand.Rand, theme string, rooms []models.Room) []models.Treasure {
+	pool := treasureByTheme[theme]
+	if pool == nil {
+		pool = treasureByTheme["fantasy"]
+	}
+
+	jitter := func(value int) int {
+		return int(float64(value) * (0.8 + rng.Float64()*0.4))
+	}
+
+	best := pool[len(pool)-3+rng.Intn(3)]
+	treasure := []models.Treasure{{Name: best.Name, Value: jitter(best.Value), Room: rooms[len(rooms)-1].ID}}
+
+	for i := 0; i < 2+rng.Intn(3); i++ {
+		item := pool[rng.Intn(len(pool)-3)]
+		treasure = append(treasure, models.Treasure{
+			Name:  item.Name,
+			Value: jitter(item.Value),
+			Room:  rooms[1+rng.Intn(len(rooms)-1)].ID,
+		})
+	}
+
+	return treasure
+}
+
+// generateLayout places rooms on a grid, connects them with corridors and names them
+// so that the entrance comes first and the room furthest from it last
+func generateLayout(rng *rand.Rand, names []string) models.LocationLayout {
+	count := 5 + rng.Intn(len(names)-4) // 5 up to one room per name
+
+	var rooms []models.Room
+	for attempts := 0; attempts < 300 && len(rooms) < count; attempts++ {
+		room := models.Room{Width: 4 + rng.Intn(6), Height: 3 + rng.Intn(3)}
+		room.X = 1 + rng.Intn(locationWidth-room.Width-2)
+		room.Y = 1 + rng.Intn(locationHeight-room.Height-2)
+		if !roomOverlaps(room, rooms) {
+			rooms = append(rooms, room)
+		}
+	}
+
+	var grid locationGrid
+	for _, room := range rooms {
+		for y := room.Y; y < room.Y+room.Height; y++ {
+			for x := room.X; x < room.X+room.Width; x++ {
+				grid[y][x] = cellFloor
+			}
+		}
+	}
+
+	exits := make([][]int, len(rooms))
+	connect := func(a, b int) {
+		exits[a] = append(exits[a], b)
+		exits[b] = append(exits[b], a)
+		carveCorridor(rng, &grid, roomCenter(rooms[a]), roomCenter(rooms[b]))
+	}
+
+	// Connect the rooms with a minimum spanning tree, then add a loop when possible
+	connected := []int{0}
+	for len(connected) < len(rooms) {
+		from, to := -1, -1
+		for _, a := range connected {
+			for b := range rooms {
+				if containsInt(connected, b) {
+					continue
+				}
+				if from < 0 || roomDistance(rooms[a], rooms[b]) < roomDistance(rooms[from], rooms[to]) {
+					from, to = a, b
+				}
+			}
+		}
+		connect(from, to)
+		connected = append(connected, to)
+	}
+	if len(rooms) > 3 && rng.Float64() < 0.6 {
+		a, b := rng.Intn(len(rooms)), rng.Intn(len(rooms))
+		if a != b && !containsInt(exits[a], b) {
+			connect(a, b)
+		}
+	}
+
+	// Order rooms from the entrance, so the innermost room is the furthest away
+	order := roomsByDepth(exits)
+	rank := make([]int, len(rooms))
+	for i, idx := range order {
+		rank[idx] = i
+	}
+
+	middle := append([]string(nil), names[1:len(names)-1]...)
+	rng.Shuffle(len(middle), func(i, j int) { middle[i], middle[j] = middle[j], middle[i] })
+
+	ordered := make([]models.Room, len(rooms))
+	for i, idx := range order {
+		room := rooms[idx]
+		room.ID = i + 1
+		room.Label = strconv.Itoa(room.ID)
+		switch i {
+		case 0:
+			room.Name = names[0]
+		case len(order) - 1:
+			room.Name = names[len(names)-1]
+		default:
+			room.Name = middle[i-1]
+		}
+		room.Exits = make([]int, 0, len(exits[idx]))
+		for _, other := range exits[idx] {
+			room.Exits = append(room.Exits, rank[other]+1)
+		}
+		sort.Ints(room.Exits)
+		ordered[i] = room
+	}
+
+	legend := map[string]string{"#": "wall", ".": "floor"}
+	for _, room := range ordered {
+		legend[room.Label] = room.Name
+	}
+
+	return models.LocationLayout{
+		Width:  locationWidth,
+		Height: locationHeight,
+		Rooms:  ordered,
+		ASCII:  gridRows(&grid, ordered),
+		Legend: legend,
+	}
+}
+
+// roomsByDepth returns the room indexes in breadth-first order from the first room
+func roomsByDepth(exits [][]int) []int {
+	visited := make([]bool, len(exits))
+	visited[0] = true
+	order := []int{0}
+	for i := 0; i < len(order); i++ {
+		for _, next := range exits[order[i]] {
+			if !visited[next] {
+				visited[next] = true
+				order = append(order, next)
+			}
+		}
+	}
+	return order
+}
+
+// carveCorridor digs an L-shaped corridor between two points through solid rock
+func carveCorridor(rng *rand.Rand, grid *locationGrid, from, to models.Point) {
+	dig := func(x, y int) {
+		if grid[y][x] == cellRock {
+			grid[y][x] = cellCorridor
+		}
+	}
+	horizontal := func(y, x1, x2 int) {
+		for x := min(x1, x2); x <= max(x1, x2); x++ {
+			dig(x, y)
+		}
+	}
+	vertical := func(x, y1, y2 int) {
+		for y := min(y1, y2); y <= max(y1, y2); y++ {
+			dig(x, y)
+		}
+	}
+
+	if rng.Intn(2) == 0 {
+		horizontal(from.Y, from.X, to.X)
+		vertical(to.X, from.Y, to.Y)
+	} else {
+		vertical(from.X, from.Y, to.Y)
+		horizontal(to.Y, from.X, to.X)
+	}
+}
+
+// gridRows draws the grid as text, surrounding open cells with walls and labeling each room
+func gridRows(grid *locationGrid, rooms []models.Room) []string {
+	canvas := make([][]byte, locationHeight)
+	for y := range canvas {
+		canvas[y] = make([]byte, locationWidth)
+		for x := range canvas[y] {
+			switch {
+			case grid[y][x] != cellRock:
+				canvas[y][x] = '.'
+			case touchesOpenCell(grid, x, y):
+				canvas[y][x] = '#'
+			default:
+				canvas[y][x] = ' '
+			}
+		}
+	}
+
+	for _, room := range rooms {
+		copy(canvas[room.Y][room.X:], room.Label)
+	}
+
+	rows := make([]string, locationHeight)
+	for y, line := range canvas {
+		rows[y] = strings.TrimRight(string(line), " ")
+	}
+	return rows
+}
+
+// touchesOpenCell reports whether any of the eight neighbours of a cell is open
+func touchesOpenCell(grid *locationGrid, x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := x+dx, y+dy
+			if nx < 0 || ny < 0 || nx >= locationWidth || ny >= locationHeight {
+				continue
+			}
+			if grid[ny][nx] != cellRock {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// roomOverlaps reports whether a room would touch any other room, keeping space for walls between them
+func roomOverlaps(room models.Room, rooms []models.Room) bool {
+	for _, other := range rooms {
+		if room.X-2 < other.X+other.Width && other.X-2 < room.X+room.Width &&
+			room.Y-2 < other.Y+other.Height && other.Y-2 < room.Y+room.Height {
+			return true
+		}
+	}
+	return false
+}
+
+// roomCenter returns the central cell of a room
+func roomCenter(room models.Room) models.Point {
+	return models.Point{X: room.X + room.Width/2, Y: room.Y + room.Height/2}
+}
+
+// roomDistance is the Manhattan distance between the centers of two rooms
+func roomDistance(a, b models.Room) int {
+	ca, cb := roomCenter(a), roomCenter(b)
+	dx, dy := ca.X-cb.X, ca.Y-cb.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// renderLocationASCII returns the layout rows followed by the room key
+func renderLocationASCII(loc *models.Location) string {
+	var sb strings.Builder
+	sb.WriteString(loc.Name + "\n\n")
+	for _, row := range loc.Layout.ASCII {
+		sb.WriteString(row + "\n")
+	}
+	sb.WriteString("\n")
+	for _, room := range loc.Layout.Rooms {
+		fmt.Fprintf(&sb, "%s  %s\n", room.Label, room.Name)
+	}
+	return sb.String()
+}
+
+// renderLocationSVG draws the layout as an SVG image, with room names shown as tooltips
+func renderLocationSVG(loc *models.Location) string {
+	const cell = 16
+	width, height := loc.Layout.Width*cell, loc.Layout.Height*cell
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(loc.Name))
+	sb.WriteString(`<rect width="100%" height="100%" fill="#1b1b1b"/>` + "\n")
+
+	for y, row := range loc.Layout.ASCII {
+		for x, symbol := range row {
+			fill := "#d9cfb4"
+			if symbol == ' ' {
+				continue
+			} else if symbol == '#' {
+				fill = "#5a5248"
+			}
+			fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", x*cell, y*cell, cell, cell, fill)
+		}
+	}
+
+	for _, room := range loc.Layout.Rooms {
+		fmt.Fprintf(&sb, `<g><title>%s</title>`, html.EscapeString(room.Name))
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#8c2f1b" stroke-width="2"/>`,
+			room.X*cell, room.Y*cell, room.Width*cell, room.Height*cell)
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" font-family="monospace" font-size="12" fill="#2b2118">%s</text></g>`+"\n",
+			room.X*cell+3, room.Y*cell+12, html.EscapeString(room.Label))
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// containsInt reports whether value is present in values
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
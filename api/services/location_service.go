@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// ErrLocationNotFound is returned when a point of interest does not exist
+var ErrLocationNotFound = newError(ErrNotFound, "location not found")
+
+// LocationService manages the points of interest of worlds
+type LocationService struct {
+	dbConfig  *config.DatabaseConfig
+	appConfig *config.AppConfig
+}
+
+// NewLocationService creates a new instance of the service
+func NewLocationService(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig) *LocationService {
+	return &LocationService{
+		dbConfig:  dbConfig,
+		appConfig: appConfig,
+	}
+}
+
+const locationColumns = `id, world_id, name, type, danger, feature, history, layout, inhabitants, treasure, created_at`
+
+// GenerateLocation creates a point of interest of the world and stores it as a child of the world.
+// Random danger and feature of the world are used when the request leaves them empty.
+func (s *LocationService) GenerateLocation(ctx context.Context, w *models.World, req models.CreateLocationRequest) (*models.Location, error) {
+	rng := rand.New(rand.NewSource(rand.Int63()))
+
+	danger, err := pickWorldTrait(rng, "danger", req.Danger, w.Dangers)
+	if err != nil {
+		return nil, err
+	}
+	feature, err := pickWorldTrait(rng, "feature", req.Feature, w.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	location := randomLocation(rng, w, danger, feature)
+
+	// Locations of unsaved worlds cannot reference them, so they are never saved
+	if w.ID > 0 {
+		if err := persist(s.dbConfig, s.appConfig, "location", func() error { return s.saveLocationToDB(ctx, location) }); err != nil {
+			return nil, err
+		}
+	}
+
+	return location, nil
+}
+
+// saveLocationToDB persists the location to the database and updates the ID
+func (s *LocationService) saveLocationToDB(ctx context.Context, location *models.Location) error {
+	return s.dbConfig.DB.QueryRow(ctx,
+		`INSERT INTO locations(world_id, name, type, danger, feature, history, layout, inhabitants, treasure)
+		 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id, created_at`,
+		location.WorldID, location.Name, location.Type, location.Danger, location.Feature, location.History,
+		location.Layout, location.Inhabitants, location.Treasure).Scan(&location.ID, &location.CreatedAt)
+}
+
+// scanLocation reads a row selected with locationColumns into a location
+func scanLocation(row pgx.Row, location *models.Location) error {
+	return row.Scan(
+		&location.ID, &location.WorldID, &location.Name, &location.Type, &location.Danger, &location.Feature,
+		&location.History, &location.Layout, &location.Inhabitants, &location.Treasure, &location.CreatedAt,
+	)
+}
+
+// GetLocations retrieves the points of interest generated for a world
+func (s *LocationService) GetLocations(ctx context.Context, worldID int) ([]models.Location, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := s.dbConfig.DB.Query(ctx,
+		`SELECT `+locationColumns+` FROM locations WHERE world_id = $1 ORDER BY id`, worldID)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var location models.Location
+		if err := scanLocation(rows, &location); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, rows.Err()
+}
+
+// GetLocationsByWorldIDs retrieves the points of interest of several worlds in a single query
+func (s *LocationService) GetLocationsByWorldIDs(ctx context.Context, worldIDs []int) (map[int][]models.Location, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := s.dbConfig.DB.Query(ctx,
+		`SELECT `+locationColumns+` FROM locations WHERE world_id = ANY($1) ORDER BY id`, worldIDs)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	defer rows.Close()
+
+	locations := make(map[int][]models.Location, len(worldIDs))
+	for rows.Next() {
+		var location models.Location
+		if err := scanLocation(rows, &location); err != nil {
+			return nil, err
+		}
+		locations[location.WorldID] = append(locations[location.WorldID], location)
+	}
+
+	return locations, rows.Err()
+}
+
+// GetLocationByID retrieves a point of interest of a world
+func (s *LocationService) GetLocationByID(ctx context.Context, worldID, id int) (*models.Location, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var location models.Location
+	err := scanLocation(s.dbConfig.DB.QueryRow(ctx,
+		`SELECT `+locationColumns+` FROM locations WHERE world_id = $1 AND id = $2`, worldID, id), &location)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%w: ID %d", ErrLocationNotFound, id)
+	} else if err != nil {
+		return nil, storageError(err)
+	}
+
+	return &location, nil
+}
+
+// RenderLocation draws the layout of a location in the requested format
+func (s *LocationService) RenderLocation(location *models.Location, format string) (string, error) {
+	switch format {
+	case LocationFormatASCII:
+		return renderLocationASCII(location), nil
+	case LocationFormatSVG:
+		return renderLocationSVG(location), nil
+	default:
+		return "", newError(ErrValidation, "invalid format %q, expected %s or %s", format, LocationFormatASCII, LocationFormatSVG)
+	}
+}
+
+// pickWorldTrait returns the requested trait as named by the world, or a random one when none is requested
+func pickWorldTrait(rng *rand.Rand, kind, requested string, traits []string) (string, error) {
+	if requested == "" {
+		if len(traits) == 0 {
+			return "", nil
+		}
+		return traits[rng.Intn(len(traits))], nil
+	}
+
+	for _, trait := range traits {
+		if strings.EqualFold(trait, requested) {
+			return trait, nil
+		}
+	}
+	return "", newError(ErrValidation, "unknown %s %q, available: %s", kind, requested, strings.Join(traits, ", "))
+}
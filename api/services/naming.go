@@ -0,0 +1,78 @@
+package services
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Syllables used to build words in each of the world languages
+var syllablesByLanguage = map[string][]string{
+	// fantasy
+	"Ancient Elvish":  {"ae", "la", "thi", "el", "ril", "sil", "va", "nor", "wen", "lith", "ia", "mir"},
+	"Dwarven Runes":   {"dur", "gar", "khaz", "bor", "dun", "grim", "thor", "ak", "rum", "mok", "bal", "drak"},
+	"Common Tongue":   {"al", "ben", "mar", "ton", "wick", "ford", "ric", "ly", "den", "sa", "ell", "har"},
+	"Sylvan Whispers": {"fey", "li", "whis", "sha", "lo", "ven", "ae", "ri", "syl", "na", "ith", "ou"},
+	"Draconic":        {"vor", "thax", "rax", "ix", "zar", "gul", "kor", "ath", "vyr", "sar", "dra", "oth"},
+	"Abyssal":         {"zul", "xag", "mor", "goth", "vex", "ur", "azz", "kul", "noth", "baz", "yog", "ith"},
+	"Celestial":       {"sera", "el", "ion", "lu", "mi", "ra", "the", "ael", "cael", "ori", "on", "sol"},
+	"Primordial":      {"ur", "aqu", "ign", "ter", "aer", "os", "kai", "um", "gra", "vo", "ond", "ek"},
+	"Fae Speech":      {"pip", "lil", "mo", "ti", "fae", "wisp", "ny", "bel", "dew", "zi", "quin", "lo"},
+	"Gnomish":         {"fiz", "bim", "nok", "dle", "gim", "wob", "tin", "ker", "pox", "bun", "zig", "le"},
+	// sci-fi
+	"Galactic Standard":     {"ter", "ra", "cor", "ves", "ta", "lon", "dex", "an", "mer", "is", "kal", "ro"},
+	"Binary Code":           {"zer", "one", "bit", "ox", "ix", "nul", "hex", "ex", "bin", "lo", "hi", "ack"},
+	"Quantum Script":        {"qua", "ket", "psi", "lep", "ton", "ze", "qu", "bra", "ion", "phi", "syn", "ar"},
+	"Neural Interface":      {"neu", "ro", "syn", "ap", "cor", "tex", "li", "nk", "ax", "on", "den", "dri"},
+	"Alien Dialects":        {"xl", "qo", "zz", "th'", "kri", "uul", "vek", "'ah", "yx", "ghr", "oo", "tl"},
+	"Mathematical Patterns": {"pi", "sig", "ma", "del", "ta", "eps", "lon", "gam", "mu", "nu", "rho", "tau"},
+	"Light Pulses":          {"lum", "pho", "ton", "lux", "ra", "di", "glo", "ray", "ir", "is", "flo", "ence"},
+	"Sonic Patterns":        {"hum", "dro", "ne", "ech", "o", "vib", "ra", "tone", "wa", "ve", "so", "nar"},
+	"Encoded Transmissions": {"sig", "nal", "tra", "nsm", "cod", "ex", "ph", "rem", "ot", "ix", "cyph", "er"},
+	"Temporal Linguistics":  {"chro", "no", "tem", "po", "ae", "on", "kai", "ros", "sec", "und", "ep", "och"},
+	// post-apocalyptic
+	"Wasteland Slang":   {"rus", "ty", "scra", "pp", "grit", "dus", "ter", "jun", "k", "rat", "sk", "ag"},
+	"Old World English": {"wil", "liam", "jon", "mar", "gar", "et", "rob", "ert", "ann", "ie", "ed", "ward"},
+	"Trade Pidgin":      {"sel", "bai", "tra", "da", "wa", "ta", "go", "bak", "lo", "ka", "mi", "nu"},
+	"Signal Code":       {"al", "pha", "bra", "vo", "del", "ta", "ec", "ho", "zu", "lu", "kil", "o"},
+	"Radiation Clicks":  {"tik", "kla", "ck", "rad", "zz", "gei", "ger", "tk", "ix", "kr", "tsk", "ak"},
+	"Bunker Dialect":    {"bun", "ker", "vau", "lt", "hat", "ch", "con", "crete", "ste", "el", "dor", "lok"},
+	"Survivor's Cant":   {"ash", "bone", "crow", "dusk", "em", "ber", "gris", "tle", "hol", "low", "mire", "sh"},
+	"Scavenger Signs":   {"sca", "vi", "nge", "pi", "ck", "tin", "can", "wi", "re", "ko", "pa", "rt"},
+	"Tech-Speech":       {"vol", "tage", "cir", "cuit", "mod", "ul", "ar", "gig", "ohm", "ram", "ter", "mi"},
+	"Brotherhood Code":  {"pal", "adin", "ord", "er", "sa", "cr", "ed", "stee", "l", "sc", "ri", "be"},
+}
+
+// languageWord builds a capitalized word of 2-3 syllables in the given language
+func languageWord(rng *rand.Rand, language string) string {
+	syllables := syllablesByLanguage[language]
+	if syllables == nil {
+		syllables = syllablesByLanguage["Common Tongue"]
+	}
+
+	count := 2 + rng.Intn(2) // 2 or 3 syllables
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		sb.WriteString(syllables[rng.Intn(len(syllables))])
+	}
+
+	return capitalize(sb.String())
+}
+
+// pickLanguage chooses one of the world languages, falling back to the theme's languages
+func pickLanguage(rng *rand.Rand, languages []string, theme string) string {
+	if len(languages) == 0 {
+		languages = languagesByTheme[theme]
+		if languages == nil {
+			languages = languagesByTheme["fantasy"]
+		}
+	}
+	return languages[rng.Intn(len(languages))]
+}
+
+// capitalize upper-cases the first letter of a word
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
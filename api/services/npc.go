@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for NPC generation
+
+// MaxNPCCount is the highest number of NPCs generated in a single request
+const MaxNPCCount = 50
+
+var occupationsByTheme = map[string][]string{
+	"fantasy": {
+		"Blacksmith", "Innkeeper", "Priest", "Mercenary", "Merchant", "Scribe",
+		"Alchemist", "Bard", "Guard captain", "Hedge mage", "Thief", "Minor noble",
+	},
+	"sci-fi": {
+		"Pilot", "Engineer", "Xenobiologist", "Medic", "Smuggler", "Security officer",
+		"Data broker", "Diplomat", "Asteroid miner", "AI technician", "Colony administrator", "Bounty hunter",
+	},
+	"post-apocalyptic": {
+		"Scavenger", "Trader", "Mechanic", "Medic", "Raider", "Water purifier",
+		"Settlement leader", "Scout", "Gunsmith", "Farmer", "Preacher", "Courier",
+	},
+}
+
+var occupationsByClimate = map[string][]string{
+	"Arid":              {"Caravan guide", "Water seller", "Salt miner"},
+	"Temperate":         {"Farmer", "Woodcutter", "Miller"},
+	"Tropical":          {"Pearl diver", "Fruit grower", "Boat builder"},
+	"Arctic":            {"Ice fisher", "Sled driver", "Fur trapper"},
+	"Mediterranean":     {"Vintner", "Olive grower", "Sailor"},
+	"Alpine":            {"Mountain guide", "Shepherd", "Stonecutter"},
+	"Oceanic":           {"Fisher", "Peat cutter", "Lighthouse keeper"},
+	"Continental":       {"Rancher", "River trader", "Grain farmer"},
+	"Monsoonal":         {"Rice farmer", "Tea picker", "Ferry pilot"},
+	"Polar":             {"Seal hunter", "Lamp keeper", "Ice cutter"},
+	"Desert":            {"Well digger", "Glassblower", "Dune tracker"},
+	"Savanna":           {"Herder", "Tracker", "Game hunter"},
+	"Rainforest":        {"Herbalist", "Canopy climber", "River guide"},
+	"Tundra":            {"Reindeer herder", "Fur trapper", "Moss gatherer"},
+	"Humid Subtropical": {"Cotton grower", "Swamp guide", "Ferry operator"},
+}
+
+var occupationsByCulture = map[string][]string{
+	"Ancient elven dynasties":   {"Loremaster", "Court archer"},
+	"Dwarf mining guilds":       {"Mine foreman", "Runesmith"},
+	"Nomadic halfling tribes":   {"Wagon master", "Storyteller"},
+	"Human kingdoms":            {"Knight", "Tax collector"},
+	"Dragonborn clans":          {"Clan champion", "Dragon speaker"},
+	"Magical academies":         {"Apprentice wizard", "Archmage"},
+	"Twilight courts":           {"Court intriguer", "Masked envoy"},
+	"Oracle temples":            {"Seer", "Temple acolyte"},
+	"Beast-people tribes":       {"Pack hunter", "Shaman"},
+	"Elemental communes":        {"Elemental binder", "Commune elder"},
+	"Space mining corporations": {"Shift supervisor", "Ore assayer"},
+	"AI collectives":            {"Collective node", "Interface liaison"},
+	"Human resistance":          {"Cell leader", "Saboteur"},
+	"Genetic purists":           {"Gene auditor", "Lineage archivist"},
+	"Cyborg syndicates":         {"Augmentation surgeon", "Enforcer"},
+	"Terraforming guilds":       {"Atmosphere engineer", "Soil chemist"},
+	"Quantum researchers":       {"Lab director", "Probability analyst"},
+	"Alien embassies":           {"Ambassador", "Translator"},
+	"Data monks":                {"Archive keeper", "Code chanter"},
+	"Void explorers":            {"Navigator", "Survey captain"},
+	"Bunker dwellers":           {"Overseer", "Air filter technician"},
+	"Wasteland raiders":         {"Warlord", "Road raider"},
+	"Water barons":              {"Water enforcer", "Cistern keeper"},
+	"Tech salvagers":            {"Salvage chief", "Circuit tinkerer"},
+	"Radiation cultists":        {"Glow prophet", "Fallout pilgrim"},
+	"Agricultural communes":     {"Seed keeper", "Harvest warden"},
+	"Trading caravans":          {"Caravan master", "Barter broker"},
+	"Stronghold cities":         {"Wall sentry", "City magistrate"},
+	"Nomad tribes":              {"Pathfinder", "Tribal elder"},
+	"Memory keepers":            {"Archivist", "Oral historian"},
+}
+
+var npcTraits = []string{
+	"Curious", "Stubborn", "Generous", "Suspicious", "Cheerful", "Melancholic", "Ambitious", "Loyal",
+	"Reckless", "Cautious", "Pious", "Cynical", "Honest", "Secretive", "Proud", "Humble",
+	"Hot-tempered", "Patient", "Greedy", "Compassionate", "Superstitious", "Witty", "Vain", "Brave",
+}
+
+var npcMotivations = []string{
+	"Pay off a crippling debt", "Protect their family", "Earn the respect of their peers", "Uncover the truth about their past",
+	"Get rich before it is too late", "Avenge a murdered friend", "Leave home and see the world", "Atone for an old betrayal",
+	"Win back a lost love", "Keep a dangerous secret hidden",
+}
+
+// GenerateNPCs generates count NPCs of the world, optionally restricted to one of its cultures.
+// NPCs are numbered from 1 so that each can be fetched again with GenerateNPC.
+func (s *WorldService) GenerateNPCs(w *models.World, count int, culture string) ([]models.NPC, error) {
+	if culture != "" {
+		found := false
+		for _, c := range w.Cultures {
+			if strings.EqualFold(c, culture) {
+				culture, found = c, true
+				break
+			}
+		}
+		if !found {
+			return nil, newError(ErrValidation, "unknown culture %q, available cultures: %s", culture, strings.Join(w.Cultures, ", "))
+		}
+	}
+
+	npcs := make([]models.NPC, 0, count)
+	// The culture of an NPC is derived from its ID, so filtering skips IDs of other cultures
+	for id := 1; len(npcs) < count && id <= count*(len(w.Cultures)+1)*10; id++ {
+		npc := s.GenerateNPC(w, id)
+		if culture == "" || npc.Culture == culture {
+			npcs = append(npcs, *npc)
+		}
+	}
+
+	return npcs, nil
+}
+
+// GenerateNPC deterministically generates the NPC of the world with the given ID
+func (s *WorldService) GenerateNPC(w *models.World, id int) *models.NPC {
+	rng := rand.New(rand.NewSource(worldSeed(w, fmt.Sprintf("npc:%d", id))))
+
+	culture, language := "Unaffiliated", pickLanguage(rng, w.Languages, w.Theme)
+	if len(w.Cultures) > 0 {
+		idx := rng.Intn(len(w.Cultures))
+		culture = w.Cultures[idx]
+		// Each culture speaks one of the world's languages
+		if len(w.Languages) > 0 {
+			language = w.Languages[idx%len(w.Languages)]
+		}
+	}
+
+	npc := &models.NPC{
+		ID:          id,
+		WorldID:     w.ID,
+		Name:        languageWord(rng, language) + " " + languageWord(rng, language),
+		Age:         16 + rng.Intn(65),
+		Culture:     culture,
+		Language:    language,
+		Religion:    religionOfCulture(w.Religions, culture),
+		Occupation:  randomOccupation(rng, w.Theme, w.Climate, culture),
+		Traits:      randomWithoutDuplicatesFrom(rng, npcTraits, 2+rng.Intn(2)),
+		Motivations: randomMotivations(rng, w),
+	}
+
+	return npc
+}
+
+// randomOccupation picks an occupation from the culture, the climate or the theme of the world
+func randomOccupation(rng *rand.Rand, theme, climate, culture string) string {
+	roll := rng.Float64()
+	if options := occupationsByCulture[culture]; roll < 0.3 && len(options) > 0 {
+		return options[rng.Intn(len(options))]
+	}
+	if options := occupationsByClimate[climate]; roll < 0.55 && len(options) > 0 {
+		return options[rng.Intn(len(options))]
+	}
+
+	options := occupationsByTheme[theme]
+	if options == nil {
+		options = occupationsByTheme["fantasy"]
+	}
+	return options[rng.Intn(len(options))]
+}
+
+// randomMotivations picks a personal motivation and, sometimes, one tied to the world's dangers, faiths or powers
+func randomMotivations(rng *rand.Rand, w *models.World) []string {
+	motivations := []string{npcMotivations[rng.Intn(len(npcMotivations))]}
+
+	var worldly []string
+	for _, danger := range w.Dangers {
+		worldly = append(worldly, fmt.Sprintf("Rid their home of the %s", strings.ToLower(danger)))
+	}
+	for _, religion := range w.Religions {
+		worldly = append(worldly, fmt.Sprintf("Spread the teachings of %s", religion.Name))
+	}
+	if w.PowerSystem != nil {
+		worldly = append(worldly, fmt.Sprintf("Master the secrets of %s", strings.Replace(w.PowerSystem.Name, "The ", "the ", 1)))
+	}
+
+	if len(worldly) > 0 && rng.Float64() < 0.7 {
+		motivations = append(motivations, worldly[rng.Intn(len(worldly))])
+	}
+
+	return motivations
+}
+
+// religionOfCulture returns the name of the first religion followed by the culture
+func religionOfCulture(religions []models.Religion, culture string) string {
+	for _, religion := range religions {
+		if containsString(religion.Cultures, culture) {
+			return religion.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Folders of the notes of an Obsidian vault, below the folder of the world
+const (
+	vaultCultures  = "Cultures"
+	vaultDangers   = "Dangers"
+	vaultLanguages = "Languages"
+	vaultLocations = "Locations"
+)
+
+// vault builds the notes of an Obsidian vault, keyed by their path
+type vault struct {
+	root  string
+	notes map[string]string
+	order []string
+}
+
+// buildObsidianVault writes a zip file with one note per world, culture, danger, language and location,
+// linked to each other with wikilinks
+func buildObsidianVault(w *models.World, locations []models.Location) ([]byte, error) {
+	v := &vault{root: noteName(w.Name), notes: make(map[string]string)}
+
+	v.add("", w.Name, worldNote(v, w, locations))
+	for _, culture := range w.Cultures {
+		v.add(vaultCultures, culture, cultureNote(v, w, culture))
+	}
+	for _, danger := range w.Dangers {
+		v.add(vaultDangers, danger, dangerNote(v, w, danger, locations))
+	}
+	for _, language := range w.Languages {
+		v.add(vaultLanguages, language, languageNote(v, w, language))
+	}
+	for _, location := range locations {
+		v.add(vaultLocations, location.Name, locationNote(v, w, location))
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	modified := time.Now()
+	for _, name := range v.order {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write([]byte(v.notes[name])); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// add stores a note in a folder of the vault
+func (v *vault) add(folder, title, content string) {
+	name := path.Join(v.root, folder, noteName(title)+".md")
+	if _, exists := v.notes[name]; !exists {
+		v.order = append(v.order, name)
+	}
+	v.notes[name] = content
+}
+
+// link returns the wikilink to the note of a folder, shown with its title
+func (v *vault) link(folder, title string) string {
+	return fmt.Sprintf("[[%s|%s]]", path.Join(v.root, folder, noteName(title)), title)
+}
+
+// links returns the wikilinks to several notes of a folder
+func (v *vault) links(folder string, titles []string) []string {
+	links := make([]string, len(titles))
+	for i, title := range titles {
+		links[i] = v.link(folder, title)
+	}
+	return links
+}
+
+// noteName removes the characters Obsidian does not allow in the names of notes
+func noteName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|#^[]`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+	return strings.TrimSpace(name)
+}
+
+// frontmatter returns the YAML properties of a note; values are quoted so that any name is valid
+func frontmatter(noteType string, w *models.World, props ...[2]string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	fmt.Fprintf(&sb, "type: %s\n", noteType)
+	fmt.Fprintf(&sb, "world: %q\n", w.Name)
+	for _, prop := range props {
+		fmt.Fprintf(&sb, "%s: %q\n", prop[0], prop[1])
+	}
+	fmt.Fprintf(&sb, "tags:\n  - world-gen/%s\n  - world-gen/%s\n", noteType, w.Theme)
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
+
+// writeList writes a section with a bullet per item, skipping empty sections
+func writeList(sb *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## %s\n\n", heading)
+	for _, item := range items {
+		fmt.Fprintf(sb, "- %s\n", item)
+	}
+}
+
+func worldNote(v *vault, w *models.World, locations []models.Location) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatter("world", w,
+		[2]string{"id", fmt.Sprint(w.ID)},
+		[2]string{"theme", w.Theme},
+		[2]string{"climate", w.Climate},
+		[2]string{"population", fmt.Sprint(w.Population)},
+	))
+	fmt.Fprintf(&sb, "# %s\n\n%s\n", w.Name, w.Description)
+
+	writeList(&sb, "Features", w.Features)
+	writeList(&sb, "Fauna", w.Fauna)
+	writeList(&sb, "Flora", w.Flora)
+	writeList(&sb, "Cultures", v.links(vaultCultures, w.Cultures))
+	writeList(&sb, "Languages", v.links(vaultLanguages, w.Languages))
+	writeList(&sb, "Dangers", v.links(vaultDangers, w.Dangers))
+
+	for _, religion := range w.Religions {
+		fmt.Fprintf(&sb, "\n## %s\n\n*%s*\n\n%s\n\n", religion.Name, religion.Type, religion.Doctrine)
+		fmt.Fprintf(&sb, "Followed by %s.\n", strings.Join(v.links(vaultCultures, religion.Cultures), ", "))
+	}
+
+	if ps := w.PowerSystem; ps != nil {
+		fmt.Fprintf(&sb, "\n## %s\n\n%s\n\n%s\n", ps.Name, ps.Summary, ps.Consequence)
+	}
+
+	names := make([]string, len(locations))
+	for i, location := range locations {
+		names[i] = location.Name
+	}
+	writeList(&sb, "Locations", v.links(vaultLocations, names))
+	return sb.String()
+}
+
+func cultureNote(v *vault, w *models.World, culture string) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatter("culture", w, [2]string{"rarity", w.Rarities[culture]}))
+	fmt.Fprintf(&sb, "# %s\n\nA culture of %s.\n", culture, v.link("", w.Name))
+
+	var religions []string
+	for _, religion := range w.Religions {
+		if containsString(religion.Cultures, culture) {
+			religions = append(religions, fmt.Sprintf("%s (%s): %s", religion.Name, religion.Type, religion.Doctrine))
+		}
+	}
+	writeList(&sb, "Religions", religions)
+
+	if ps := w.PowerSystem; ps != nil && containsString(ps.Cultures, culture) {
+		fmt.Fprintf(&sb, "\n## %s\n\nThis culture wields the %s.\n", ps.Name, ps.Name)
+	}
+	return sb.String()
+}
+
+func dangerNote(v *vault, w *models.World, danger string, locations []models.Location) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatter("danger", w, [2]string{"rarity", w.Rarities[danger]}))
+	fmt.Fprintf(&sb, "# %s\n\nA danger of %s.\n", danger, v.link("", w.Name))
+
+	var haunted []string
+	for _, location := range locations {
+		if location.Danger == danger {
+			haunted = append(haunted, v.link(vaultLocations, location.Name))
+		}
+	}
+	writeList(&sb, "Locations", haunted)
+
+	if ps := w.PowerSystem; ps != nil && containsString(ps.Dangers, danger) {
+		fmt.Fprintf(&sb, "\n## %s\n\n%s\n", ps.Name, ps.Consequence)
+	}
+	return sb.String()
+}
+
+func languageNote(v *vault, w *models.World, language string) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatter("language", w, [2]string{"rarity", w.Rarities[language]}))
+	fmt.Fprintf(&sb, "# %s\n\nA language spoken in %s by %s.\n",
+		language, v.link("", w.Name), strings.Join(v.links(vaultCultures, w.Cultures), ", "))
+	return sb.String()
+}
+
+func locationNote(v *vault, w *models.World, location models.Location) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatter("location", w,
+		[2]string{"id", fmt.Sprint(location.ID)},
+		[2]string{"location_type", location.Type},
+	))
+	fmt.Fprintf(&sb, "# %s\n\n%s in %s, built around %s, haunted by %s.\n\n%s\n",
+		location.Name, capitalize(location.Type), v.link("", w.Name),
+		location.Feature, v.link(vaultDangers, location.Danger), location.History)
+
+	fmt.Fprintf(&sb, "\n## Map\n\n```\n%s\n```\n", strings.Join(location.Layout.ASCII, "\n"))
+
+	rooms := make([]string, len(location.Layout.Rooms))
+	for i, room := range location.Layout.Rooms {
+		rooms[i] = fmt.Sprintf("**%s. %s:** %s", room.Label, room.Name, room.Description)
+	}
+	writeList(&sb, "Rooms", rooms)
+
+	inhabitants := make([]string, len(location.Inhabitants))
+	for i, inhabitant := range location.Inhabitants {
+		name := inhabitant.Name
+		if containsString(w.Dangers, name) {
+			name = v.link(vaultDangers, name)
+		}
+		inhabitants[i] = fmt.Sprintf("%d %s in room %d", inhabitant.Count, name, inhabitant.Room)
+	}
+	writeList(&sb, "Inhabitants", inhabitants)
+
+	treasure := make([]string, len(location.Treasure))
+	for i, item := range location.Treasure {
+		treasure[i] = fmt.Sprintf("%s, worth %d, in room %d", item.Name, item.Value, item.Room)
+	}
+	writeList(&sb, "Treasure", treasure)
+	return sb.String()
+}
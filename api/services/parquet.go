@@ -0,0 +1,104 @@
+package services
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetWriter writes a Parquet file of INT64 and UTF8 columns, times being stored as milliseconds.
+// Rows are written as a row group whenever rowGroupSize bytes of values are buffered,
+// so that a file of any size is written with bounded memory.
+type parquetWriter struct {
+	out          *parquet.Writer
+	columns      []datasetColumn
+	rowGroupSize int
+	buffered     int
+	row          parquet.Row
+}
+
+func newParquetWriter(out io.Writer, columns []datasetColumn, rowGroupSize int) (*parquetWriter, error) {
+	fields := make([]parquet.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = parquetField{Node: column.parquetNode(), name: column.name}
+	}
+	schema := parquet.NewSchema("schema", parquetGroup{fields: fields})
+
+	return &parquetWriter{
+		out:          parquet.NewWriter(out, schema, parquet.CreatedBy("world-gen", "", "")),
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		row:          make(parquet.Row, len(columns)),
+	}, nil
+}
+
+// writeRow buffers a row of int64, string, or nil values for optional columns
+func (w *parquetWriter) writeRow(values []any) error {
+	for i, value := range values {
+		var v parquet.Value
+		switch value := value.(type) {
+		case int64:
+			v = parquet.Int64Value(value)
+			w.buffered += 8
+		case string:
+			v = parquet.ByteArrayValue([]byte(value))
+			w.buffered += 4 + len(value)
+		}
+
+		definitionLevel := 0
+		if w.columns[i].optional && value != nil {
+			definitionLevel = 1
+		}
+		w.row[i] = v.Level(0, definitionLevel, i)
+	}
+	if _, err := w.out.WriteRows([]parquet.Row{w.row}); err != nil {
+		return err
+	}
+
+	if w.buffered >= w.rowGroupSize {
+		w.buffered = 0
+		return w.out.Flush()
+	}
+	return nil
+}
+
+// close writes the remaining rows and the footer describing the file
+func (w *parquetWriter) close() error {
+	return w.out.Close()
+}
+
+// parquetNode returns the type of the values of a column
+func (c datasetColumn) parquetNode() parquet.Node {
+	var node parquet.Node
+	switch c.kind {
+	case columnString:
+		node = parquet.String()
+	case columnTime:
+		node = parquet.Timestamp(parquet.Millisecond)
+	default:
+		node = parquet.Leaf(parquet.Int64Type)
+	}
+	if c.optional {
+		node = parquet.Optional(node)
+	}
+	return node
+}
+
+// parquetGroup is a group of columns kept in the order of the dataset, where parquet.Group sorts them by name
+type parquetGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g parquetGroup) Fields() []parquet.Field { return g.fields }
+
+// parquetField is a column of a parquetGroup. Rows are written as values, so fields are never read from Go values.
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string { return f.name }
+
+func (f parquetField) Value(reflect.Value) reflect.Value { return reflect.Value{} }
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+	"github.com/parquet-go/parquet-go"
+)
+
+// readParquet reads a file with a reference implementation, returning its rows formatted like the CSV datasets
+func readParquet(t *testing.T, data []byte, columns []datasetColumn) (rows [][]string, rowGroups int) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+
+	fields := f.Schema().Fields()
+	if len(fields) != len(columns) {
+		t.Fatalf("schema has %d columns, want %d", len(fields), len(columns))
+	}
+	for i, field := range fields {
+		if field.Name() != columns[i].name || field.Optional() != columns[i].optional {
+			t.Errorf("column %d is %q (optional %v), want %q (optional %v)",
+				i, field.Name(), field.Optional(), columns[i].name, columns[i].optional)
+		}
+	}
+
+	for _, group := range f.RowGroups() {
+		reader := group.Rows()
+		buf := make([]parquet.Row, 16)
+		for {
+			n, err := reader.ReadRows(buf)
+			for _, row := range buf[:n] {
+				record := make([]string, len(columns))
+				for _, value := range row {
+					record[value.Column()] = parquetValueString(value, columns[value.Column()])
+				}
+				rows = append(rows, record)
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				t.Fatalf("ReadRows() error = %v", err)
+			}
+		}
+		reader.Close()
+	}
+	if int64(len(rows)) != f.NumRows() {
+		t.Errorf("read %d rows, the footer counts %d", len(rows), f.NumRows())
+	}
+	return rows, len(f.RowGroups())
+}
+
+// parquetValueString formats a value like csvDataset does
+func parquetValueString(value parquet.Value, column datasetColumn) string {
+	switch {
+	case value.IsNull():
+		return ""
+	case column.kind == columnString:
+		return string(value.ByteArray())
+	case column.kind == columnTime:
+		return time.UnixMilli(value.Int64()).UTC().Format(time.RFC3339)
+	default:
+		return strconv.FormatInt(value.Int64(), 10)
+	}
+}
+
+// writeDatasets writes the worlds to a CSV and a Parquet dataset, returning the CSV records without the header
+func writeDatasets(t *testing.T, arrays string, worlds []*models.World) (csvRows [][]string, parquetFile []byte) {
+	t.Helper()
+	var csvOut, parquetOut bytes.Buffer
+	for format, out := range map[string]*bytes.Buffer{DatasetFormatCSV: &csvOut, DatasetFormatParquet: &parquetOut} {
+		d, err := NewDatasetWriter(out, format, arrays)
+		if err != nil {
+			t.Fatalf("NewDatasetWriter(%s) error = %v", format, err)
+		}
+		for _, w := range worlds {
+			if err := d.Write(w); err != nil {
+				t.Fatalf("Write(%s) error = %v", format, err)
+			}
+		}
+		if err := d.Close(); err != nil {
+			t.Fatalf("Close(%s) error = %v", format, err)
+		}
+	}
+
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records[1:], parquetOut.Bytes()
+}
+
+func testDatasetWorlds() []*models.World {
+	var worlds []*models.World
+	for i := 0; i < 4; i++ {
+		seed := int64(i + 1)
+		w, _ := buildWorld([]string{"fantasy", "sci-fi"}[i%2], &generateOptions{seed: &seed})
+		w.ID = i + 1
+		w.CreatedAt = time.Date(2024, 5, i+1, 12, 30, 0, 0, time.UTC)
+		if i%2 == 1 {
+			systemID := 7
+			w.SystemID = &systemID
+		}
+		worlds = append(worlds, w)
+	}
+
+	// A world without any list still has a row in the exploded layout
+	worlds = append(worlds, &models.World{ID: 9, Name: "Void", Theme: "fantasy", CreatedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)})
+	return worlds
+}
+
+func TestParquetDatasetRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrays  string
+		worlds  []*models.World
+		columns []datasetColumn
+	}{
+		{"empty json", DatasetArraysJSON, nil, jsonDatasetColumns},
+		{"empty explode", DatasetArraysExplode, nil, explodedDatasetColumns},
+		{"json", DatasetArraysJSON, testDatasetWorlds(), jsonDatasetColumns},
+		{"explode", DatasetArraysExplode, testDatasetWorlds(), explodedDatasetColumns},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, data := writeDatasets(t, tt.arrays, tt.worlds)
+			columns := append(append([]datasetColumn{}, worldDatasetColumns...), tt.columns...)
+
+			got, _ := readParquet(t, data, columns)
+			if len(got) != len(want) {
+				t.Fatalf("read %d rows, want %d", len(got), len(want))
+			}
+			for i := range want {
+				for j := range want[i] {
+					if got[i][j] != want[i][j] {
+						t.Errorf("row %d column %s = %q, want %q", i, columns[j].name, got[i][j], want[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParquetWriterRowGroups(t *testing.T) {
+	columns := []datasetColumn{
+		{name: "id", kind: columnInt},
+		{name: "name", kind: columnString},
+		{name: "parent", kind: columnInt, optional: true},
+	}
+
+	tests := []struct {
+		name          string
+		rows          int
+		rowGroupSize  int
+		wantRowGroups int
+	}{
+		{"no rows", 0, 64, 0},
+		// Each row buffers 8 + 4 + 2 bytes, and 8 more when the parent is defined, so 4 rows fill 72 bytes
+		{"single group", 3, 1 << 20, 1},
+		{"exact boundary", 8, 72, 2},
+		{"partial last group", 10, 72, 3},
+		{"group per row", 5, 1, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w, err := newParquetWriter(&out, columns, tt.rowGroupSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want [][]string
+			for i := 0; i < tt.rows; i++ {
+				// Parents alternate with nulls, so both runs of definition levels cross the boundaries
+				name := "n" + strconv.Itoa(i%10)
+				row := []any{int64(i), name, nil}
+				record := []string{strconv.Itoa(i), name, ""}
+				if i%2 == 0 {
+					row[2], record[2] = int64(i/2), strconv.Itoa(i/2)
+				}
+				if err := w.writeRow(row); err != nil {
+					t.Fatalf("writeRow() error = %v", err)
+				}
+				want = append(want, record)
+			}
+			if err := w.close(); err != nil {
+				t.Fatalf("close() error = %v", err)
+			}
+
+			got, rowGroups := readParquet(t, out.Bytes(), columns)
+			if rowGroups != tt.wantRowGroups {
+				t.Errorf("file has %d row groups, want %d", rowGroups, tt.wantRowGroups)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("read %d rows, want %d", len(got), len(want))
+			}
+			for i := range want {
+				for j := range want[i] {
+					if got[i][j] != want[i][j] {
+						t.Errorf("row %d column %s = %q, want %q", i, columns[j].name, got[i][j], want[i][j])
+					}
+				}
+			}
+		})
+	}
+}
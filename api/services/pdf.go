@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Layout of the PDF dossiers, in millimeters and points
+const (
+	pdfMargin     = 20.0
+	pdfIndent     = 6.0
+	pdfLineHeight = 5.5
+	pdfFontSize   = 10.5
+	pdfCodeSize   = 7.5
+)
+
+// renderPDF lays out a Markdown dossier as a PDF document. Only the subset of Markdown used by the dossier
+// templates is supported: headings, bullets, bold and italic text, code blocks and rules.
+// The core fonts of the PDF standard are used, so that no font has to be loaded.
+func renderPDF(title, markdown string) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("World Generator API", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin / 2)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s - %d", tr(title), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	inCode := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			pdf.Ln(2)
+			continue
+		}
+		if inCode {
+			pdf.SetFont("Courier", "", pdfCodeSize)
+			pdf.CellFormat(0, pdfCodeSize*0.45, tr(line), "", 1, "L", false, 0, "")
+			continue
+		}
+
+		line = strings.TrimRight(line, " ")
+		switch {
+		case line == "":
+			pdf.Ln(2)
+		case strings.HasPrefix(line, "# "):
+			pdfHeading(pdf, tr(line[2:]), 20, 4)
+		case strings.HasPrefix(line, "## "):
+			pdfHeading(pdf, tr(line[3:]), 15, 3)
+			x, y := pdf.GetXY()
+			pdf.Line(x, y, x+pdfPageWidth(pdf), y)
+			pdf.Ln(2)
+		case strings.HasPrefix(line, "### "):
+			pdfHeading(pdf, tr(line[4:]), 12, 1)
+		case line == "---":
+			pdf.Ln(4)
+			x, y := pdf.GetXY()
+			pdf.Line(x, y, x+pdfPageWidth(pdf), y)
+			pdf.Ln(2)
+		case strings.HasPrefix(line, "- "):
+			pdf.SetFont("Helvetica", "", pdfFontSize)
+			pdf.SetX(pdfMargin + pdfIndent/2)
+			pdf.Write(pdfLineHeight, tr("•")+" ")
+			pdf.SetLeftMargin(pdfMargin + pdfIndent)
+			pdfInline(pdf, tr, line[2:])
+			pdf.SetLeftMargin(pdfMargin)
+			pdf.Ln(pdfLineHeight)
+		case len(line) > 2 && line[0] == '*' && line[1] != '*' && strings.HasSuffix(line, "*"):
+			pdf.SetFont("Helvetica", "I", pdfFontSize)
+			pdf.MultiCell(0, pdfLineHeight, tr(strings.Trim(line, "*")), "", "L", false)
+		default:
+			pdfInline(pdf, tr, line)
+			pdf.Ln(pdfLineHeight)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfHeading writes a heading in bold, leaving space above it
+func pdfHeading(pdf *gofpdf.Fpdf, text string, size float64, spaceBefore float64) {
+	pdf.Ln(spaceBefore)
+	pdf.SetFont("Helvetica", "B", size)
+	pdf.MultiCell(0, size*0.5, text, "", "L", false)
+	pdf.Ln(1)
+}
+
+// pdfInline writes text flowing from the current position, with the parts between ** in bold
+func pdfInline(pdf *gofpdf.Fpdf, tr func(string) string, text string) {
+	for i, part := range strings.Split(text, "**") {
+		style := ""
+		if i%2 == 1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, pdfFontSize)
+		pdf.Write(pdfLineHeight, tr(part))
+	}
+}
+
+// pdfPageWidth returns the width between the margins of the page
+func pdfPageWidth(pdf *gofpdf.Fpdf) float64 {
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	return width - left - right
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"math/rand"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for weighted sampling of generation pools
+
+// Rarity tiers of pool items
+const (
+	RarityCommon    = "common"
+	RarityUncommon  = "uncommon"
+	RarityRare      = "rare"
+	RarityLegendary = "legendary"
+)
+
+// Default sampling weight of each rarity tier
+var rarityWeights = map[string]int{
+	RarityCommon:    16,
+	RarityUncommon:  8,
+	RarityRare:      3,
+	RarityLegendary: 1,
+}
+
+// poolEntry annotates a pool item. Items without an entry are common and untagged.
+type poolEntry struct {
+	Rarity   string
+	Weight   int      // overrides the weight of the rarity tier when set
+	Tags     []string // tags the item brings to a world
+	Requires []string // tags that must all be present before the item can be picked
+	Excludes []string // tags that prevent the item from being picked, and that it keeps out once picked
+}
+
+// Annotations of pool items, keyed by item name across every pool
+var poolEntries = map[string]poolEntry{
+	// Features
+	"Volcanic islands":          {Rarity: RarityUncommon, Tags: []string{"volcanic", "coastal"}},
+	"Obsidian fields":           {Rarity: RarityUncommon, Tags: []string{"volcanic"}},
+	"Hot springs":               {Rarity: RarityUncommon, Requires: []string{"volcanic"}},
+	"Midnight sun":              {Tags: []string{"midnight-sun"}, Excludes: []string{"polar-night"}},
+	"Polar night":               {Tags: []string{"polar-night"}, Excludes: []string{"midnight-sun"}},
+	"Aurora borealis":           {Rarity: RarityUncommon},
+	"Shimmering lights":         {Rarity: RarityUncommon},
+	"Crystal forests":           {Rarity: RarityRare},
+	"Ancient meteorite craters": {Rarity: RarityRare},
+	"Desert blooms":             {Rarity: RarityRare, Excludes: []string{"dust-storms"}},
+	"Dust storms":               {Tags: []string{"dust-storms"}},
+	"Paradise islands":          {Rarity: RarityUncommon, Tags: []string{"coastal"}},
+	"Hidden caverns":            {Rarity: RarityUncommon},
+	"Glacial caves":             {Rarity: RarityUncommon},
+	"Coral reefs":               {Tags: []string{"coastal"}},
+	"Pristine beaches":          {Tags: []string{"coastal"}},
+	"Sun-drenched coasts":       {Tags: []string{"coastal"}},
+	"Rocky coves":               {Tags: []string{"coastal"}},
+	"Azure waters":              {Tags: []string{"coastal"}},
+	"Coastal cliffs":            {Tags: []string{"coastal"}},
+	"Windswept coasts":          {Tags: []string{"coastal"}},
+
+	// Fauna
+	"Frost giants":          {Rarity: RarityRare},
+	"Ice wyverns":           {Rarity: RarityUncommon},
+	"Snow sphinxes":         {Rarity: RarityRare},
+	"Boreal phoenixes":      {Rarity: RarityLegendary},
+	"Mirage phoenixes":      {Rarity: RarityLegendary},
+	"Sand drakes":           {Rarity: RarityUncommon},
+	"Rainbow serpents":      {Rarity: RarityUncommon},
+	"Fae panthers":          {Rarity: RarityRare},
+	"Sphinx lions":          {Rarity: RarityRare},
+	"Oracle octopi":         {Rarity: RarityRare, Requires: []string{"coastal"}},
+	"Sea nymphs":            {Rarity: RarityUncommon, Requires: []string{"coastal"}},
+	"Heat-energy beings":    {Rarity: RarityRare},
+	"Hyper-evolved felines": {Rarity: RarityRare},
+
+	// Flora
+	"Wish-granting flowers":       {Rarity: RarityLegendary},
+	"Immortality figs":            {Rarity: RarityLegendary},
+	"Time-slowing succulents":     {Rarity: RarityRare},
+	"Eternal ice roses":           {Rarity: RarityRare},
+	"Anti-gravity flowers":        {Rarity: RarityRare},
+	"Weather-controlling plants":  {Rarity: RarityRare},
+	"Frozen time capsule flowers": {Rarity: RarityRare},
+
+	// Cultures
+	"Dragonborn clans":    {Rarity: RarityUncommon},
+	"Twilight courts":     {Rarity: RarityRare},
+	"Alien embassies":     {Rarity: RarityRare},
+	"Quantum researchers": {Rarity: RarityUncommon},
+	"Data monks":          {Rarity: RarityUncommon},
+	"Radiation cultists":  {Rarity: RarityUncommon},
+	"Memory keepers":      {Rarity: RarityRare},
+
+	// Dangers
+	"Sun dragons":                {Rarity: RarityRare},
+	"Ancient sea monsters":       {Rarity: RarityRare, Requires: []string{"coastal"}},
+	"Sirens":                     {Requires: []string{"coastal"}},
+	"Cursed islands":             {Requires: []string{"coastal"}},
+	"Coastal defense systems":    {Requires: []string{"coastal"}},
+	"Coastal raiders":            {Requires: []string{"coastal"}},
+	"Quicksand portals":          {Rarity: RarityRare},
+	"Reality distortion fields":  {Rarity: RarityRare},
+	"Consciousness-stealing ice": {Rarity: RarityRare},
+	"Reality bubbles":            {Rarity: RarityRare},
+	"Nuclear mirages":            {Rarity: RarityRare},
+
+	// Languages
+	"Draconic":             {Rarity: RarityUncommon},
+	"Abyssal":              {Rarity: RarityRare},
+	"Celestial":            {Rarity: RarityRare},
+	"Primordial":           {Rarity: RarityLegendary},
+	"Light Pulses":         {Rarity: RarityRare},
+	"Temporal Linguistics": {Rarity: RarityLegendary},
+	"Radiation Clicks":     {Rarity: RarityRare},
+
+	// Surviving technology of post-apocalyptic worlds
+	"Power armor":        {Rarity: RarityRare},
+	"Working satellites": {Rarity: RarityLegendary},
+}
+
+// rarityOf returns the rarity tier of a pool item
+func rarityOf(item string) string {
+	if entry, ok := poolEntries[item]; ok && entry.Rarity != "" {
+		return entry.Rarity
+	}
+	return RarityCommon
+}
+
+// poolWeight returns the sampling weight of a pool item
+func poolWeight(item string) int {
+	if entry := poolEntries[item]; entry.Weight > 0 {
+		return entry.Weight
+	}
+	return rarityWeights[rarityOf(item)]
+}
+
+// weightedSample picks up to count unique items, favoring the more common ones.
+// Items already chosen for the world in context contribute their tags to the
+// requires and excludes rules. The result only depends on the state of rng.
+func weightedSample(rng *rand.Rand, items []string, count int, context []string) []string {
+	picked := []string{}
+	if count <= 0 {
+		return picked
+	}
+
+	tags := make(map[string]bool)
+	var excluded []string
+	addTags := func(item string) {
+		entry := poolEntries[item]
+		for _, tag := range entry.Tags {
+			tags[tag] = true
+		}
+		excluded = append(excluded, entry.Excludes...)
+	}
+	for _, item := range context {
+		addTags(item)
+	}
+
+	available := append([]string(nil), items...)
+	for len(picked) < count {
+		var eligible []string
+		total := 0
+		for _, item := range available {
+			if poolItemAllowed(item, tags, excluded) {
+				eligible = append(eligible, item)
+				total += poolWeight(item)
+			}
+		}
+		if total == 0 {
+			break
+		}
+
+		roll := rng.Intn(total)
+		for _, item := range eligible {
+			if roll -= poolWeight(item); roll < 0 {
+				picked = append(picked, item)
+				addTags(item)
+				available = removeString(available, item)
+				break
+			}
+		}
+	}
+
+	return picked
+}
+
+// poolItemAllowed reports whether the tag rules of an item are met by the tags gathered so far
+func poolItemAllowed(item string, tags map[string]bool, excluded []string) bool {
+	entry := poolEntries[item]
+	for _, tag := range entry.Requires {
+		if !tags[tag] {
+			return false
+		}
+	}
+	for _, tag := range entry.Excludes {
+		if tags[tag] {
+			return false
+		}
+	}
+	for _, tag := range entry.Tags {
+		if containsString(excluded, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// worldRarities reports the rarity tier of every element picked from the world pools
+func worldRarities(w *models.World) map[string]string {
+	rarities := make(map[string]string)
+	for _, pool := range [][]string{w.Features, w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages} {
+		for _, item := range pool {
+			rarities[item] = rarityOf(item)
+		}
+	}
+	return rarities
+}
+
+// removeString returns items without the first occurrence of value
+func removeString(items []string, value string) []string {
+	for i, item := range items {
+		if item == value {
+			return append(items[:i:i], items[i+1:]...)
+		}
+	}
+	return items
+}
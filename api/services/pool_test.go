@@ -0,0 +1,110 @@
+package services
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+func TestWeightedSample(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		count   int
+		context []string
+		// every sample holds these items, in any order
+		want []string
+	}{
+		{"no items requested", []string{"Wolves", "Bears"}, 0, nil, []string{}},
+		{"more items requested than available", []string{"Wolves", "Bears", "Foxes"}, 5, nil, []string{"Wolves", "Bears", "Foxes"}},
+		{"required tag missing", []string{"Hot springs", "Wolves"}, 2, nil, []string{"Wolves"}},
+		{"required tag brought by the context", []string{"Hot springs", "Wolves"}, 2, []string{"Obsidian fields"}, []string{"Hot springs", "Wolves"}},
+		{"required tag brought by an earlier pick", []string{"Hot springs", "Volcanic islands"}, 2, nil, []string{"Hot springs", "Volcanic islands"}},
+		{"excluded by the context", []string{"Desert blooms", "Wolves"}, 2, []string{"Dust storms"}, []string{"Wolves"}},
+		{"excluded tag kept out once picked", []string{"Dust storms", "Wolves"}, 2, []string{"Desert blooms"}, []string{"Wolves"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 50; seed++ {
+				got := weightedSample(rand.New(rand.NewSource(seed)), tt.items, tt.count, tt.context)
+				if len(got) != len(tt.want) {
+					t.Fatalf("seed %d: weightedSample() = %v, want %v", seed, got, tt.want)
+				}
+				for _, item := range tt.want {
+					if !slices.Contains(got, item) {
+						t.Fatalf("seed %d: weightedSample() = %v, want %v", seed, got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedSampleExclusivePairs(t *testing.T) {
+	for seed := int64(1); seed <= 200; seed++ {
+		got := weightedSample(rand.New(rand.NewSource(seed)), []string{"Midnight sun", "Polar night", "Wolves"}, 3, nil)
+		if len(got) != 2 || !slices.Contains(got, "Wolves") {
+			t.Fatalf("seed %d: weightedSample() = %v, want Wolves and one of the pair", seed, got)
+		}
+		if problems := brokenTagRules(&models.World{Features: got}); len(problems) > 0 {
+			t.Fatalf("seed %d: weightedSample() = %v breaks tag rules: %v", seed, got, problems)
+		}
+	}
+}
+
+// Items are drawn in proportion to the weights of their rarity tiers
+func TestWeightedSampleFollowsRarity(t *testing.T) {
+	items := []string{"Wolves", "Ice wyverns", "Frost giants", "Boreal phoenixes"}
+	rng := rand.New(rand.NewSource(1))
+
+	const draws = 28000
+	counts := make(map[string]int)
+	for i := 0; i < draws; i++ {
+		counts[weightedSample(rng, items, 1, nil)[0]]++
+	}
+
+	total := 0
+	for _, item := range items {
+		total += poolWeight(item)
+	}
+	for _, item := range items {
+		want := draws * poolWeight(item) / total
+		if got := counts[item]; got < want*85/100 || got > want*115/100 {
+			t.Errorf("%s (%s) drawn %d times, want about %d", item, rarityOf(item), got, want)
+		}
+	}
+}
+
+func TestWeightedSampleIsSeeded(t *testing.T) {
+	pool := coherentPool(faunaByClimate, "fantasy", "Arctic")
+	for seed := int64(1); seed <= 20; seed++ {
+		first := weightedSample(rand.New(rand.NewSource(seed)), pool, 4, []string{"Polar night"})
+		second := weightedSample(rand.New(rand.NewSource(seed)), pool, 4, []string{"Polar night"})
+		if !slices.Equal(first, second) {
+			t.Fatalf("seed %d: samples differ: %v and %v", seed, first, second)
+		}
+	}
+}
+
+func TestPoolWeight(t *testing.T) {
+	tests := []struct {
+		item string
+		want int
+	}{
+		{"Wolves", rarityWeights[RarityCommon]},
+		{"Ice wyverns", rarityWeights[RarityUncommon]},
+		{"Frost giants", rarityWeights[RarityRare]},
+		{"Boreal phoenixes", rarityWeights[RarityLegendary]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			if got := poolWeight(tt.item); got != tt.want {
+				t.Errorf("poolWeight(%q) = %d, want %d", tt.item, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for power system generation
+
+// Power system types, one per theme
+const (
+	PowerSystemMagic      = "magic"
+	PowerSystemTechnology = "technology"
+	PowerSystemRemnant    = "remnant technology"
+)
+
+var magicSources = []string{
+	"Ley lines beneath the earth", "The breath of ancient dragons", "Pacts with otherworldly patrons",
+	"Starlight caught in crystals", "The dreams of a sleeping god", "Blood and lineage", "Spoken true names",
+}
+
+var magicCosts = []string{
+	"Years of the caster's life", "Vivid memories, lost forever", "Physical exhaustion and fever",
+	"Rare reagents consumed with each spell", "A debt owed to the source", "Slow transformation into stone",
+}
+
+var magicLimitations = []string{
+	"Cannot create life", "Fails under running water", "Weakens during the new moon",
+	"Requires spoken words and gestures", "Cannot affect iron", "Bound to a single element per caster",
+	"Leaves a visible mark on the caster", "Only works within sight of the source",
+}
+
+var magicPractitionerRoles = []string{"Sorcerers", "Hedge witches", "Runesmiths", "Druids", "Battle-mages", "Oracles", "Warlocks"}
+
+var magicNames = []string{"Weave", "Art", "Current", "Gift", "Song"}
+
+var techLevels = []struct {
+	Level string
+	FTL   []string
+}{
+	{"Early spacefaring", []string{"None, sublight generation ships only", "Experimental jump drives"}},
+	{"Interplanetary", []string{"None, fusion torch ships", "Solar sails between planets"}},
+	{"Interstellar", []string{"Jump gates", "Warp drives", "Hyperspace lanes"}},
+	{"Post-singularity", []string{"Folded space", "Quantum tunneling", "Consciousness transmission"}},
+}
+
+var aiStatuses = []string{
+	"Banned after the uprising", "Sentient citizens with rights", "Tools without self-awareness",
+	"Ruling the colonies as a council", "Hiding in the networks",
+}
+
+var energySources = []string{"Fusion reactors", "Antimatter cells", "Dyson swarm", "Zero-point taps", "Geothermal cores", "Exotic crystals"}
+
+var collapses = []struct {
+	Type string
+	Name string
+}{
+	{"Nuclear war", "The Great Fire"},
+	{"Engineered pandemic", "The Sickness"},
+	{"Climate collapse", "The Long Drought"},
+	{"AI uprising", "The Machine Revolt"},
+	{"Solar flare", "The Day the Lights Died"},
+	{"Asteroid impact", "The Falling Star"},
+	{"Nanotech outbreak", "The Grey Tide"},
+}
+
+var survivingTech = []string{
+	"Hand-cranked radios", "Solar panels", "Water purifiers", "Combustion engines", "Pre-war firearms",
+	"Geiger counters", "Medical scanners", "Hydroponic farms", "Power armor", "Working satellites",
+}
+
+// randomPowerSystem generates the power system matching the world's theme
+func randomPowerSystem(rng *rand.Rand, theme string, cultures, dangers, languages []string) *models.PowerSystem {
+	var ps *models.PowerSystem
+	switch theme {
+	case "sci-fi":
+		ps = randomTechnologySystem(rng, cultures)
+	case "post-apocalyptic":
+		ps = randomRemnantSystem(rng, cultures)
+	default:
+		ps = randomMagicSystem(rng, cultures, languages)
+	}
+
+	ps.Cultures = cultures
+	if ps.Cultures == nil {
+		ps.Cultures = []string{}
+	}
+	ps.Dangers = randomWithoutDuplicatesFrom(rng, dangers, 1)
+	ps.Consequence = generateConsequence(rng, ps, ps.Dangers)
+
+	return ps
+}
+
+// randomMagicSystem generates the magic system of a fantasy world
+func randomMagicSystem(rng *rand.Rand, cultures, languages []string) *models.PowerSystem {
+	magic := &models.MagicSystem{
+		Source:        magicSources[rng.Intn(len(magicSources))],
+		Cost:          magicCosts[rng.Intn(len(magicCosts))],
+		Limitations:   randomWithoutDuplicatesFrom(rng, magicLimitations, 1+rng.Intn(3)),
+		Practitioners: make([]string, 0, len(cultures)),
+	}
+
+	for _, culture := range cultures {
+		role := magicPractitionerRoles[rng.Intn(len(magicPractitionerRoles))]
+		magic.Practitioners = append(magic.Practitioners, fmt.Sprintf("%s of the %s", role, strings.ToLower(culture)))
+	}
+
+	name := fmt.Sprintf("The %s %s", languageWord(rng, pickLanguage(rng, languages, "fantasy")), magicNames[rng.Intn(len(magicNames))])
+	return &models.PowerSystem{
+		Type:    PowerSystemMagic,
+		Name:    name,
+		Summary: fmt.Sprintf("Magic flows from %s, and every spell costs %s.", strings.ToLower(magic.Source), strings.ToLower(magic.Cost)),
+		Magic:   magic,
+	}
+}
+
+// randomTechnologySystem generates the technology level of a sci-fi world
+func randomTechnologySystem(rng *rand.Rand, cultures []string) *models.PowerSystem {
+	level := techLevels[rng.Intn(len(techLevels))]
+	tech := &models.TechnologyLevel{
+		Level:        level.Level,
+		FTL:          level.FTL[rng.Intn(len(level.FTL))],
+		AIStatus:     aiStatuses[rng.Intn(len(aiStatuses))],
+		EnergySource: energySources[rng.Intn(len(energySources))],
+	}
+
+	// Worlds with AI collectives cannot have banned or unaware AIs
+	if containsString(cultures, "AI collectives") {
+		tech.AIStatus = "Sentient collectives with a voice in government"
+	}
+
+	return &models.PowerSystem{
+		Type:       PowerSystemTechnology,
+		Name:       fmt.Sprintf("The %s Age", level.Level),
+		Summary:    fmt.Sprintf("%s civilization powered by %s.", tech.Level, strings.ToLower(tech.EnergySource)),
+		Technology: tech,
+	}
+}
+
+// randomRemnantSystem generates the collapse and the surviving technology of a post-apocalyptic world
+func randomRemnantSystem(rng *rand.Rand, cultures []string) *models.PowerSystem {
+	collapse := collapses[rng.Intn(len(collapses))]
+
+	// Tech salvagers keep more of the old world running
+	techCount := 2 + rng.Intn(2)
+	if containsString(cultures, "Tech salvagers") {
+		techCount += 2
+	}
+
+	c := &models.Collapse{
+		Type:          collapse.Type,
+		YearsAgo:      5 + rng.Intn(296), // 5-300 years
+		SurvivingTech: randomWithoutDuplicatesFrom(rng, survivingTech, techCount),
+	}
+
+	return &models.PowerSystem{
+		Type:     PowerSystemRemnant,
+		Name:     collapse.Name,
+		Summary:  fmt.Sprintf("%d years after the %s, survivors depend on what still works.", c.YearsAgo, strings.ToLower(c.Type)),
+		Collapse: c,
+	}
+}
+
+// generateConsequence links the power system to one of the world's dangers
+func generateConsequence(rng *rand.Rand, ps *models.PowerSystem, dangers []string) string {
+	if len(dangers) == 0 {
+		return ""
+	}
+
+	// Templates receive the power system name first and the danger second
+	templates := map[string][]string{
+		PowerSystemMagic: {
+			"Reckless use of %[1]s is blamed for the %[2]s.",
+			"Scholars believe the %[2]s feed on %[1]s.",
+		},
+		PowerSystemTechnology: {
+			"Failures during %[1]s gave rise to the %[2]s.",
+			"The %[2]s are the price of %[1]s.",
+		},
+		PowerSystemRemnant: {
+			"%[1]s left behind the %[2]s.",
+			"The %[2]s are a lasting scar of %[1]s.",
+		},
+	}
+
+	subject := strings.Replace(ps.Name, "The ", "the ", 1)
+	options := templates[ps.Type]
+	tmpl := options[rng.Intn(len(options))]
+	return capitalize(fmt.Sprintf(tmpl, subject, strings.ToLower(dangers[0])))
+}
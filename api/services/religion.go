@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for religion generation
+
+var religionTypesByTheme = map[string][]string{
+	"fantasy":          {"Pantheon", "Monotheism", "Dualism", "Ancestor worship", "Animism", "Mystery cult"},
+	"sci-fi":           {"Techno-faith", "Machine cult", "Cosmic philosophy", "Ancestor archive", "Xeno-mysticism"},
+	"post-apocalyptic": {"Doomsday cult", "Ancestor worship", "Relic veneration", "Nature revival", "Prophet movement"},
+}
+
+var religionNameTemplatesByTheme = map[string][]string{
+	"fantasy":          {"The Faith of %s", "Church of %s", "The Old Ways of %s", "Circle of %s", "The %s Covenant"},
+	"sci-fi":           {"The %s Doctrine", "Order of %s", "The %s Protocol", "Children of %s", "The %s Axiom"},
+	"post-apocalyptic": {"Cult of %s", "The %s Remnant", "Followers of %s", "The Last Word of %s", "Keepers of %s"},
+}
+
+var domainsByTheme = map[string][]string{
+	"fantasy":          {"War", "Wisdom", "Death", "Love", "Trickery", "Magic", "Harvest", "Justice", "Dreams", "The hunt", "Forge", "Moon"},
+	"sci-fi":           {"Knowledge", "Evolution", "Entropy", "The void", "Creation", "Connection", "Memory", "Time", "Order", "Transcendence"},
+	"post-apocalyptic": {"Survival", "Rebirth", "Scarcity", "Memory", "Radiation", "Iron", "Water", "Endings", "Mercy", "The road"},
+}
+
+var domainsByClimate = map[string][]string{
+	"Arid":              {"Sun", "Thirst", "Sand"},
+	"Temperate":         {"Seasons", "Rivers", "Growth"},
+	"Tropical":          {"Rain", "Serpents", "Abundance"},
+	"Arctic":            {"Winter", "Endurance", "Northern lights"},
+	"Mediterranean":     {"The sea", "Wine", "Olive groves"},
+	"Alpine":            {"Peaks", "Avalanches", "Eagles"},
+	"Oceanic":           {"Mist", "Tides", "Hearth"},
+	"Continental":       {"Storms", "Plains", "Harvest"},
+	"Monsoonal":         {"Monsoon", "Floods", "Rice"},
+	"Polar":             {"Endless night", "Ice", "Stars"},
+	"Desert":            {"Mirages", "Stone", "Scorching heat"},
+	"Savanna":           {"Fire", "Herds", "Drought"},
+	"Rainforest":        {"Canopy", "Decay", "Green"},
+	"Tundra":            {"Frost", "Migration", "Silence"},
+	"Humid Subtropical": {"Thunder", "Swamps", "Fireflies"},
+}
+
+var deityTitleTemplates = []string{
+	"Keeper of %s", "Lord of %s", "Mother of %s", "Voice of %s", "Bringer of %s", "Warden of %s",
+}
+
+var symbolsByTheme = map[string][]string{
+	"fantasy":          {"Silver crescent", "Burning eye", "Oak leaf", "Broken sword", "Coiled serpent", "Seven-pointed star", "White stag", "Golden scales", "Raven feather", "Open hand"},
+	"sci-fi":           {"Concentric circles", "Glowing helix", "Fractal spiral", "Binary halo", "Black sphere", "Orbital rings", "Circuit tree", "Prism", "Infinity loop", "Pulsar mark"},
+	"post-apocalyptic": {"Trefoil", "Rusted key", "Gas mask", "Cracked bell", "Hand-painted sun", "Bottle cap", "Barbed circle", "Seed pod", "Antenna cross", "Skull with flowers"},
+}
+
+var holySitesByTheme = map[string][]string{
+	"fantasy":          {"Temple of the first dawn", "Standing stones", "Sunken cathedral", "Hermit's grotto", "Sacred grove"},
+	"sci-fi":           {"Orbital shrine", "Derelict colony ship", "Primary data core", "Observatory spire", "First landing site"},
+	"post-apocalyptic": {"Crater's edge", "Collapsed cathedral", "Old world bunker", "Dry reservoir", "Broadcast tower"},
+}
+
+var ritualsByTheme = map[string][]string{
+	"fantasy":          {"Dawn prayers", "Blood oaths", "Seasonal feasts", "Pilgrimage to holy sites", "Offerings of bread and wine", "Night-long vigils", "Trial by ordeal", "Naming ceremonies"},
+	"sci-fi":           {"Neural communion", "Data confession", "Memory backups", "Zero-gravity meditation", "Recitation of the first code", "Signal vigils", "Gene blessings", "Star-chart readings"},
+	"post-apocalyptic": {"Scrap offerings", "Rad-water baptism", "Reading the old books", "Burning the dead", "Counting of survivors", "Siren songs at dusk", "Sharing the last ration", "Marking the walls"},
+}
+
+var taboosByTheme = map[string][]string{
+	"fantasy":          {"Speaking the true names of gods", "Cutting sacred trees", "Breaking hospitality", "Eating during the new moon", "Lying under oath", "Harming ravens"},
+	"sci-fi":           {"Deleting memories", "Unsanctioned modification", "Disconnecting from the network", "Harming sentient code", "Looking into the void unshielded", "Altering the first code"},
+	"post-apocalyptic": {"Wasting water", "Entering the old cities", "Hoarding medicine", "Repairing old world weapons", "Abandoning the wounded", "Speaking of the before times"},
+}
+
+// Faiths of cultures that are religious in nature, with their fixed type and doctrine
+var faithsByCulture = map[string]struct {
+	Type     string
+	Doctrine string
+}{
+	"Oracle temples":     {"Mystery cult", "The future is already written, and only the veiled oracles may read it aloud."},
+	"Twilight courts":    {"Dualism", "Every dusk the light and the dark renegotiate the world; the courts keep the balance."},
+	"Data monks":         {"Techno-faith", "All that is recorded endures; to be forgotten is the only true death."},
+	"AI collectives":     {"Machine cult", "Individual minds are drafts of a greater mind that is still being compiled."},
+	"Radiation cultists": {"Doomsday cult", "The Glow that ended the old world is a purifying fire, and those it changes are chosen."},
+	"Memory keepers":     {"Ancestor worship", "The names of the dead must be spoken every day, or the world will forget it was ever whole."},
+}
+
+// randomReligions generates the religions followed by the world's cultures
+func randomReligions(rng *rand.Rand, theme, climate string, cultures, features, languages []string) []models.Religion {
+	if len(cultures) == 0 {
+		return []models.Religion{}
+	}
+
+	religions := make([]models.Religion, 0, len(cultures))
+	for i, culture := range cultures {
+		// Cultures sometimes share the faith of a neighboring culture
+		if i > 0 && rng.Intn(3) == 0 {
+			prev := &religions[len(religions)-1]
+			prev.Cultures = append(prev.Cultures, culture)
+			continue
+		}
+		religions = append(religions, randomReligion(rng, theme, climate, culture, features, languages))
+	}
+
+	return religions
+}
+
+// randomReligion generates a single religion founded by the given culture
+func randomReligion(rng *rand.Rand, theme, climate, culture string, features, languages []string) models.Religion {
+	language := pickLanguage(rng, languages, theme)
+
+	religionType := pickFromTheme(rng, religionTypesByTheme, theme)
+	doctrine := ""
+	if faith, ok := faithsByCulture[culture]; ok {
+		religionType = faith.Type
+		doctrine = faith.Doctrine
+	}
+
+	domains := randomDomains(rng, theme, climate)
+	if doctrine == "" {
+		doctrine = generateDoctrine(rng, theme, domains)
+	}
+
+	nameTemplates := religionNameTemplatesByTheme[theme]
+	if nameTemplates == nil {
+		nameTemplates = religionNameTemplatesByTheme["fantasy"]
+	}
+
+	return models.Religion{
+		Name:      fmt.Sprintf(nameTemplates[rng.Intn(len(nameTemplates))], languageWord(rng, language)),
+		Type:      religionType,
+		Doctrine:  doctrine,
+		Cultures:  []string{culture},
+		Domains:   domains,
+		Deities:   randomDeities(rng, religionType, domains, theme, language),
+		Symbols:   randomFromTheme(rng, symbolsByTheme, theme, 1+rng.Intn(2)),
+		HolySites: randomHolySites(rng, theme, features),
+		Rituals:   randomFromTheme(rng, ritualsByTheme, theme, 2+rng.Intn(2)),
+		Taboos:    randomFromTheme(rng, taboosByTheme, theme, 1+rng.Intn(2)),
+	}
+}
+
+// randomDomains mixes theme domains with domains inspired by the climate
+func randomDomains(rng *rand.Rand, theme, climate string) []string {
+	domains := randomFromTheme(rng, domainsByTheme, theme, 2+rng.Intn(2))
+	if climateDomains := domainsByClimate[climate]; climateDomains != nil {
+		domains = append(domains, climateDomains[rng.Intn(len(climateDomains))])
+	}
+	return domains
+}
+
+// randomDeities creates the deities of a religion, according to its type
+func randomDeities(rng *rand.Rand, religionType string, domains []string, theme, language string) []models.Deity {
+	count := 0
+	switch religionType {
+	case "Pantheon":
+		count = 3 + rng.Intn(3) // 3-5 deities
+	case "Dualism":
+		count = 2
+	case "Monotheism", "Machine cult", "Doomsday cult", "Prophet movement":
+		count = 1
+	default:
+		count = rng.Intn(3) // 0-2 revered entities
+	}
+
+	symbols := symbolsByTheme[theme]
+	if symbols == nil {
+		symbols = symbolsByTheme["fantasy"]
+	}
+
+	deities := make([]models.Deity, 0, count)
+	for i := 0; i < count; i++ {
+		primary := domains[i%len(domains)]
+		deityDomains := []string{primary}
+		if secondary := domains[rng.Intn(len(domains))]; secondary != primary {
+			deityDomains = append(deityDomains, secondary)
+		}
+
+		template := deityTitleTemplates[rng.Intn(len(deityTitleTemplates))]
+		deities = append(deities, models.Deity{
+			Name:    languageWord(rng, language),
+			Title:   fmt.Sprintf(template, strings.ToLower(primary)),
+			Domains: deityDomains,
+			Symbol:  symbols[rng.Intn(len(symbols))],
+		})
+	}
+
+	return deities
+}
+
+// randomHolySites picks holy sites among the world's features and the theme's sacred places
+func randomHolySites(rng *rand.Rand, theme string, features []string) []string {
+	sites := randomFromTheme(rng, holySitesByTheme, theme, 1)
+	if len(features) > 0 {
+		feature := features[rng.Intn(len(features))]
+		sites = append(sites, "The sacred "+strings.ToLower(feature))
+	}
+	return sites
+}
+
+// generateDoctrine writes a short statement of belief centered on the religion's domains
+func generateDoctrine(rng *rand.Rand, theme string, domains []string) string {
+	templates := map[string][]string{
+		"fantasy": {
+			"The gods of %s and %s shaped the world, and they demand devotion in return.",
+			"Mortals are bound to %s until they earn the favor of %s.",
+		},
+		"sci-fi": {
+			"The universe tends toward %s; the faithful seek %s before the end of all computation.",
+			"%s and %s are two expressions of the same cosmic equation.",
+		},
+		"post-apocalyptic": {
+			"The old world fell because it forgot %s; only %s can redeem what remains.",
+			"Those who honor %s and %s will inherit the ruins.",
+		},
+	}
+
+	options := templates[theme]
+	if options == nil {
+		options = templates["fantasy"]
+	}
+
+	first := strings.ToLower(domains[0])
+	second := strings.ToLower(domains[len(domains)-1])
+	return capitalize(fmt.Sprintf(options[rng.Intn(len(options))], first, second))
+}
+
+// pickFromTheme returns a single random entry of a theme pool
+func pickFromTheme(rng *rand.Rand, pools map[string][]string, theme string) string {
+	pool := pools[theme]
+	if pool == nil {
+		pool = pools["fantasy"]
+	}
+	return pool[rng.Intn(len(pool))]
+}
+
+// randomFromTheme returns up to count unique entries of a theme pool
+func randomFromTheme(rng *rand.Rand, pools map[string][]string, theme string, count int) []string {
+	pool := pools[theme]
+	if pool == nil {
+		pool = pools["fantasy"]
+	}
+	return randomWithoutDuplicatesFrom(rng, pool, count)
+}
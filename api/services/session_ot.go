@@ -0,0 +1,207 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Helper functions for the operational transformation of the description text
+
+// textComponent is one step of a text operation: it retains, inserts or deletes characters.
+// Lengths are counted in runes.
+type textComponent struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+// textOperation edits a whole document, walking it from start to end.
+// It is encoded like ot.js: positive numbers retain, negative numbers delete and strings insert,
+// so [3, "abc", -2] keeps 3 characters, inserts "abc" and deletes the next 2.
+type textOperation []textComponent
+
+func (op textOperation) MarshalJSON() ([]byte, error) {
+	parts := make([]interface{}, 0, len(op))
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			parts = append(parts, c.Retain)
+		case c.Insert != "":
+			parts = append(parts, c.Insert)
+		case c.Delete > 0:
+			parts = append(parts, -c.Delete)
+		}
+	}
+	return json.Marshal(parts)
+}
+
+func (op *textOperation) UnmarshalJSON(data []byte) error {
+	var parts []interface{}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+
+	var result textOperation
+	for _, part := range parts {
+		switch v := part.(type) {
+		case float64:
+			n := int(v)
+			if float64(n) != v || n == 0 {
+				return fmt.Errorf("invalid text operation component %v", v)
+			}
+			if n > 0 {
+				result = result.retain(n)
+			} else {
+				result = result.delete(-n)
+			}
+		case string:
+			result = result.insert(v)
+		default:
+			return fmt.Errorf("invalid text operation component %v", v)
+		}
+	}
+	*op = result
+	return nil
+}
+
+// retain, insert and delete append a component, merging it with the last one when they are alike
+func (op textOperation) retain(n int) textOperation {
+	if n <= 0 {
+		return op
+	}
+	if last := len(op) - 1; last >= 0 && op[last].Retain > 0 {
+		op[last].Retain += n
+		return op
+	}
+	return append(op, textComponent{Retain: n})
+}
+
+func (op textOperation) insert(s string) textOperation {
+	if s == "" {
+		return op
+	}
+	if last := len(op) - 1; last >= 0 && op[last].Insert != "" {
+		op[last].Insert += s
+		return op
+	}
+	return append(op, textComponent{Insert: s})
+}
+
+func (op textOperation) delete(n int) textOperation {
+	if n <= 0 {
+		return op
+	}
+	if last := len(op) - 1; last >= 0 && op[last].Delete > 0 {
+		op[last].Delete += n
+		return op
+	}
+	return append(op, textComponent{Delete: n})
+}
+
+// baseLength is the length of the documents the operation applies to
+func (op textOperation) baseLength() int {
+	n := 0
+	for _, c := range op {
+		n += c.Retain + c.Delete
+	}
+	return n
+}
+
+// apply runs the operation on a document
+func (op textOperation) apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if op.baseLength() != len(runes) {
+		return "", fmt.Errorf("the operation expects a text of %d characters, not %d", op.baseLength(), len(runes))
+	}
+
+	result := make([]rune, 0, len(runes))
+	pos := 0
+	for _, c := range op {
+		switch {
+		case c.Retain > 0:
+			result = append(result, runes[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case c.Insert != "":
+			result = append(result, []rune(c.Insert)...)
+		case c.Delete > 0:
+			pos += c.Delete
+		}
+	}
+	return string(result), nil
+}
+
+var errIncompatibleOperations = errors.New("operations apply to texts of different lengths")
+
+// transformText rewrites two concurrent operations on the same document so that
+// applying a then b' gives the same text as applying b then a'. When both insert
+// at the same position, the text inserted by a comes first.
+func transformText(a, b textOperation) (textOperation, textOperation, error) {
+	if a.baseLength() != b.baseLength() {
+		return nil, nil, errIncompatibleOperations
+	}
+
+	var a1, b1 textOperation
+	i, j := 0, 0
+	var ca, cb textComponent
+	next := func(op textOperation, idx *int) textComponent {
+		if *idx < len(op) {
+			c := op[*idx]
+			*idx++
+			return c
+		}
+		return textComponent{}
+	}
+	ca, cb = next(a, &i), next(b, &j)
+
+	for ca != (textComponent{}) || cb != (textComponent{}) {
+		// Inserts go through untouched, and the other operation retains them
+		if ca.Insert != "" {
+			a1 = a1.insert(ca.Insert)
+			b1 = b1.retain(utf8.RuneCountInString(ca.Insert))
+			ca = next(a, &i)
+			continue
+		}
+		if cb.Insert != "" {
+			a1 = a1.retain(utf8.RuneCountInString(cb.Insert))
+			b1 = b1.insert(cb.Insert)
+			cb = next(b, &j)
+			continue
+		}
+		if ca == (textComponent{}) || cb == (textComponent{}) {
+			return nil, nil, errIncompatibleOperations
+		}
+
+		n := min(ca.Retain+ca.Delete, cb.Retain+cb.Delete)
+		switch {
+		case ca.Retain > 0 && cb.Retain > 0:
+			a1, b1 = a1.retain(n), b1.retain(n)
+		case ca.Delete > 0 && cb.Retain > 0:
+			a1 = a1.delete(n)
+		case ca.Retain > 0 && cb.Delete > 0:
+			b1 = b1.delete(n)
+		}
+		// When both delete the same characters, neither needs to delete them again
+
+		ca, cb = shorten(ca, n), shorten(cb, n)
+		if ca == (textComponent{}) {
+			ca = next(a, &i)
+		}
+		if cb == (textComponent{}) {
+			cb = next(b, &j)
+		}
+	}
+
+	return a1, b1, nil
+}
+
+// shorten consumes n characters of a retain or delete component
+func shorten(c textComponent, n int) textComponent {
+	if c.Retain > 0 {
+		c.Retain -= n
+	} else {
+		c.Delete -= n
+	}
+	return c
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+func parseTextOperation(t *testing.T, s string) textOperation {
+	t.Helper()
+	var op textOperation
+	if err := json.Unmarshal([]byte(s), &op); err != nil {
+		t.Fatalf("invalid operation %s: %v", s, err)
+	}
+	return op
+}
+
+func TestTextOperationJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{"retain insert delete", `[3, "abc", -2]`, `[3,"abc",-2]`, false},
+		{"merges alike components", `[1, 2, "a", "b", -1, -1]`, `[3,"ab",-2]`, false},
+		{"drops empty inserts", `[2, "", 1]`, `[3]`, false},
+		{"empty", `[]`, `[]`, false},
+		{"zero", `[0]`, "", true},
+		{"fraction", `[1.5]`, "", true},
+		{"object", `[{"retain": 1}]`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var op textOperation
+			err := json.Unmarshal([]byte(tt.json), &op)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := json.Marshal(op)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextOperationApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		op      string
+		want    string
+		wantErr bool
+	}{
+		{"insert", "Hello", `[5, " world"]`, "Hello world", false},
+		{"delete", "Hello world", `[5, -6]`, "Hello", false},
+		{"replace", "a cold world", `[2, -4, "warm", 6]`, "a warm world", false},
+		{"counts runes", "Ærø isle", `[3, -5]`, "Ærø", false},
+		{"too short", "Hello", `[3]`, "", true},
+		{"too long", "Hi", `[2, -1]`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTextOperation(t, tt.op).apply(tt.doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformText(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		a, b string
+		want string
+	}{
+		{"inserts at different positions", "world", `["the ", 5]`, `[5, "!"]`, "the world!"},
+		{"inserts at the same position put a first", "ab", `[1, "X", 1]`, `[1, "Y", 1]`, "aXYb"},
+		{"same deletion", "abcdef", `[1, -2, 3]`, `[1, -2, 3]`, "adef"},
+		{"overlapping deletions", "abcdef", `[1, -3, 2]`, `[2, -3, 1]`, "af"},
+		{"insert inside a deletion", "abcdef", `[1, -4, 1]`, `[3, "XY", 3]`, "aXYf"},
+		{"unicode", "Ærø", `[1, "é", 2]`, `[3, "ü"]`, "Æérøü"},
+		{"empty operations", "abc", `[3]`, `[3]`, "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parseTextOperation(t, tt.a), parseTextOperation(t, tt.b)
+			a1, b1, err := transformText(a, b)
+			if err != nil {
+				t.Fatalf("transformText() error = %v", err)
+			}
+
+			for _, path := range [][2]textOperation{{a, b1}, {b, a1}} {
+				got := applyAll(t, tt.doc, path[0], path[1])
+				if got != tt.want {
+					t.Errorf("applying %v then %v = %q, want %q", path[0], path[1], got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformTextRejectsDifferentBases(t *testing.T) {
+	if _, _, err := transformText(parseTextOperation(t, `[3]`), parseTextOperation(t, `[2, "x"]`)); err != errIncompatibleOperations {
+		t.Errorf("transformText() error = %v, want errIncompatibleOperations", err)
+	}
+}
+
+// Random concurrent operations converge whichever order they are applied in
+func TestTransformTextConverges(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		doc := randomText(rng, rng.Intn(12))
+		a, b := randomTextOperation(rng, doc), randomTextOperation(rng, doc)
+
+		a1, b1, err := transformText(a, b)
+		if err != nil {
+			t.Fatalf("transformText(%v, %v) error = %v", a, b, err)
+		}
+		if ab, ba := applyAll(t, doc, a, b1), applyAll(t, doc, b, a1); ab != ba {
+			t.Fatalf("on %q, %v and %v diverge: %q and %q", doc, a, b, ab, ba)
+		}
+	}
+}
+
+func TestSessionRebasesConcurrentOperations(t *testing.T) {
+	s := newTestSessionService()
+	ctx := context.Background()
+	w := &models.World{ID: 1, Name: "Aster", Description: "Hello world"}
+
+	alice, err := s.Join(ctx, w, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.Join(ctx, w, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both edit revision 0, so the second operation is rebased on the first
+	s.Handle(ctx, alice, models.SessionMessage{Type: SessionText, Revision: 0, Ops: json.RawMessage(`[5, ",", 6]`)})
+	s.Handle(ctx, bob, models.SessionMessage{Type: SessionText, Revision: 0, Ops: json.RawMessage(`[11, "!"]`)})
+	// An operation based on the latest revision is applied as it is
+	s.Handle(ctx, alice, models.SessionMessage{Type: SessionText, Revision: 2, Ops: json.RawMessage(`[13, " Bye."]`)})
+
+	state := sessionSnapshot(t, s, w.ID)
+	if want := "Hello, world! Bye."; state.World.Description != want {
+		t.Errorf("description = %q, want %q", state.World.Description, want)
+	}
+	if state.Revision != 3 {
+		t.Errorf("revision = %d, want 3", state.Revision)
+	}
+	for _, client := range []*SessionClient{alice, bob} {
+		if msg := lastMessage(client, SessionError); msg != nil {
+			t.Errorf("%s got an error: %s", client.User, msg.Message)
+		}
+	}
+}
+
+func TestSessionRejectsUnknownRevisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision int
+	}{
+		{"future revision", 1},
+		{"negative revision", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSessionService()
+			ctx := context.Background()
+			w := &models.World{ID: 1, Description: "Hello"}
+			client, err := s.Join(ctx, w, "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s.Handle(ctx, client, models.SessionMessage{Type: SessionText, Revision: tt.revision, Ops: json.RawMessage(`[5, "!"]`)})
+			if msg := lastMessage(client, SessionError); msg == nil {
+				t.Error("no error was sent")
+			}
+			if state := sessionSnapshot(t, s, w.ID); state.World.Description != "Hello" {
+				t.Errorf("description = %q, want it unchanged", state.World.Description)
+			}
+		})
+	}
+}
+
+func newTestSessionService() *SessionService {
+	worldService := newTestWorldService()
+	return NewSessionService(worldService.dbConfig, worldService)
+}
+
+// sessionSnapshot returns the stored state of a session
+func sessionSnapshot(t *testing.T, s *SessionService, worldID int) *sessionState {
+	t.Helper()
+	state, err := s.store.Update(context.Background(), worldID, func(*sessionState) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// lastMessage drains the messages sent to a client, returning the last one of a type
+func lastMessage(client *SessionClient, kind string) *models.SessionMessage {
+	var last *models.SessionMessage
+	for {
+		select {
+		case msg := <-client.Messages:
+			if msg.Type == kind {
+				last = &msg
+			}
+		default:
+			return last
+		}
+	}
+}
+
+func applyAll(t *testing.T, doc string, ops ...textOperation) string {
+	t.Helper()
+	for _, op := range ops {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			t.Fatalf("apply(%v) error = %v", op, err)
+		}
+	}
+	return doc
+}
+
+func randomText(rng *rand.Rand, n int) string {
+	runes := []rune("abcé✓")
+	text := make([]rune, n)
+	for i := range text {
+		text[i] = runes[rng.Intn(len(runes))]
+	}
+	return string(text)
+}
+
+// randomTextOperation returns a random operation on the document
+func randomTextOperation(rng *rand.Rand, doc string) textOperation {
+	var op textOperation
+	for left := utf8.RuneCountInString(doc); left > 0; {
+		n := 1 + rng.Intn(left)
+		switch rng.Intn(3) {
+		case 0:
+			op = op.retain(n)
+		case 1:
+			op = op.delete(n)
+		default:
+			op = op.insert(randomText(rng, 1+rng.Intn(3)))
+			continue
+		}
+		left -= n
+	}
+	if rng.Intn(2) == 0 {
+		op = op.insert(randomText(rng, 1+rng.Intn(3)))
+	}
+	return op
+}
+
+// A session whose world was saved elsewhere starts over from the stored world
+func TestReloadSession(t *testing.T) {
+	s := newTestSessionService()
+	ctx := context.Background()
+	w := &models.World{ID: 1, Name: "Aster", Description: "Hello", Version: 3}
+
+	alice, err := s.Join(ctx, w, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Handle(ctx, alice, models.SessionMessage{Type: SessionText, Revision: 0, Ops: json.RawMessage(`[5, "!"]`)})
+	s.Handle(ctx, alice, models.SessionMessage{Type: SessionEdit, Field: "name", Value: json.RawMessage(`"Brin"`)})
+	lastMessage(alice, SessionSnapshot)
+
+	stored := &models.World{ID: 1, Name: "Cael", Description: "Saved elsewhere", Version: 4}
+	state, err := s.store.Update(ctx, w.ID, func(state *sessionState) error {
+		reloadSession(state, stored)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.World.Name != "Cael" || state.World.Version != 4 || state.Dirty || len(state.Stamps) != 0 {
+		t.Errorf("reloaded state = %+v, want the stored world", state)
+	}
+
+	s.publish(ctx, w.ID, snapshotMessage(state, ""))
+	if msg := lastMessage(alice, SessionSnapshot); msg == nil || msg.Client != alice.ID || msg.World.Name != "Cael" {
+		t.Errorf("snapshot = %+v, want the stored world addressed to alice", msg)
+	}
+
+	// Operations based on the description edited before the reload are refused
+	s.Handle(ctx, alice, models.SessionMessage{Type: SessionText, Revision: 1, Ops: json.RawMessage(`[6, "?"]`)})
+	if msg := lastMessage(alice, SessionError); msg == nil {
+		t.Error("an operation based on a discarded revision was accepted")
+	}
+	if state := sessionSnapshot(t, s, w.ID); state.World.Description != "Saved elsewhere" {
+		t.Errorf("description = %q, want it unchanged", state.World.Description)
+	}
+}
+
+func TestSetWorldField(t *testing.T) {
+	tests := []struct {
+		field   string
+		value   string
+		wantErr bool
+	}{
+		{"name", `"Brin"`, false},
+		{"name", `"  "`, true},
+		{"population", `12`, false},
+		{"population", `-1`, true},
+		{"climate", `"Arid"`, false},
+		{"climate", `"Lava"`, true},
+		{"features", `["Dunes"]`, false},
+		{"features", `[]`, true},
+		{"features", `null`, true},
+		{"fauna", `[]`, false},
+		{"cultures", `"Nomads"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+" "+tt.value, func(t *testing.T) {
+			w := &models.World{Name: "Aster", Climate: "Temperate", Features: []string{"Hills"}}
+			before := *w
+			err := setWorldField(w, tt.field, json.RawMessage(tt.value))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setWorldField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && (w.Name != before.Name || len(w.Features) != len(before.Features)) {
+				t.Errorf("a refused value changed the world to %+v", w)
+			}
+		})
+	}
+}
+
+func TestLastParticipantClosesSession(t *testing.T) {
+	s := newTestSessionService()
+	ctx := context.Background()
+	w := &models.World{ID: 1, Name: "Aster", Description: "Hello"}
+
+	alice, err := s.Join(ctx, w, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := s.Join(ctx, w, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Leave(ctx, alice)
+	if state := sessionSnapshot(t, s, w.ID); len(state.Peers) != 1 {
+		t.Fatalf("session has %d participants, want 1", len(state.Peers))
+	}
+	s.Leave(ctx, bob)
+	if state := sessionSnapshot(t, s, w.ID); state.World.ID != 0 {
+		t.Errorf("the session of world %d is still open", state.World.ID)
+	}
+}
@@ -0,0 +1,674 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Session message types
+const (
+	SessionSnapshot = "snapshot"
+	SessionPresence = "presence"
+	SessionEdit     = "edit"
+	SessionText     = "text"
+	SessionAck      = "ack"
+	SessionLock     = "lock"
+	SessionUnlock   = "unlock"
+	SessionResync   = "resync"
+	SessionError    = "error"
+)
+
+const (
+	// Interval between the presence refreshes and the write-backs of the sessions of this instance
+	sessionTick = 15 * time.Second
+	// Participants not seen for this long are removed, in case their instance crashed
+	sessionPeerTimeout  = time.Minute
+	sessionLockDuration = 30 * time.Second
+	// Description operations kept to transform operations based on older revisions
+	sessionHistoryLimit = 200
+	// Messages buffered for a participant before it is disconnected as too slow
+	sessionClientBuffer = 64
+)
+
+// Fields edited with last-writer-wins; the description is edited with text operations
+var sessionFields = []string{"name", "population", "climate", "features", "fauna", "flora", "cultures", "dangers", "languages"}
+
+// SessionService runs the collaborative editing sessions of worlds
+type SessionService struct {
+	dbConfig     *config.DatabaseConfig
+	worldService *WorldService
+	store        sessionStore
+
+	mu      sync.Mutex
+	clients map[int]map[*SessionClient]bool
+	// Closes the subscription of each session with participants on this instance
+	unsubscribe map[int]func()
+}
+
+// SessionClient is a participant connected to this instance
+type SessionClient struct {
+	ID      string
+	User    string
+	WorldID int
+	// Messages is closed when the client leaves or falls too far behind
+	Messages <-chan models.SessionMessage
+
+	messages chan models.SessionMessage
+	closed   bool
+}
+
+// NewSessionService creates a new instance of the service
+func NewSessionService(dbConfig *config.DatabaseConfig, worldService *WorldService) *SessionService {
+	var store sessionStore
+	if dbConfig.RedisClient != nil {
+		store = &redisSessionStore{client: dbConfig.RedisClient}
+	} else {
+		store = newMemorySessionStore()
+	}
+
+	return &SessionService{
+		dbConfig:     dbConfig,
+		worldService: worldService,
+		store:        store,
+		clients:      make(map[int]map[*SessionClient]bool),
+		unsubscribe:  make(map[int]func()),
+	}
+}
+
+// Start periodically refreshes the presence of local participants and writes
+// changes back to the database, until the context is done
+func (s *SessionService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(sessionTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.tick(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Join adds a participant to the editing session of a world, opening the session when needed
+func (s *SessionService) Join(ctx context.Context, w *models.World, user string) (*SessionClient, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(chan models.SessionMessage, sessionClientBuffer)
+	client := &SessionClient{ID: id, User: user, WorldID: w.ID, Messages: messages, messages: messages}
+
+	// Subscribe before joining so that no message is missed
+	s.mu.Lock()
+	if s.clients[w.ID] == nil {
+		s.clients[w.ID] = make(map[*SessionClient]bool)
+		s.unsubscribe[w.ID] = s.store.Subscribe(context.Background(), w.ID, func(msg models.SessionMessage) {
+			s.deliver(w.ID, msg)
+		})
+	}
+	s.clients[w.ID][client] = true
+	s.mu.Unlock()
+
+	now := time.Now().UTC()
+	state, err := s.store.Update(ctx, w.ID, func(state *sessionState) error {
+		if state.World.ID == 0 {
+			*state = sessionState{
+				World:  *w,
+				Stamps: make(map[string]int64),
+				Peers:  make(map[string]models.SessionPeer),
+				Locks:  make(map[string]models.SessionLock),
+			}
+			state.World.Diagnostics = nil
+		}
+		pruneSession(state, now)
+		state.Peers[client.ID] = models.SessionPeer{Client: client.ID, User: user, JoinedAt: now, LastSeen: now}
+		return nil
+	})
+	if err != nil {
+		s.Leave(ctx, client)
+		return nil, err
+	}
+
+	s.sendTo(client, snapshotMessage(state, client.ID))
+	s.publish(ctx, w.ID, presenceMessage(state))
+	return client, nil
+}
+
+// Leave removes a participant, closing the session and writing it back once nobody is left
+func (s *SessionService) Leave(ctx context.Context, client *SessionClient) {
+	s.mu.Lock()
+	if !s.clients[client.WorldID][client] {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.clients[client.WorldID], client)
+	if len(s.clients[client.WorldID]) == 0 {
+		s.unsubscribe[client.WorldID]()
+		delete(s.unsubscribe, client.WorldID)
+		delete(s.clients, client.WorldID)
+	}
+	client.close()
+	s.mu.Unlock()
+
+	state, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if state.World.ID == 0 {
+			state.ended = true
+			return nil
+		}
+		delete(state.Peers, client.ID)
+		for field, lock := range state.Locks {
+			if lock.Client == client.ID {
+				delete(state.Locks, field)
+			}
+		}
+		pruneSession(state, time.Now().UTC())
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error leaving session %d: %v", client.WorldID, err)
+		return
+	}
+	if state.World.ID == 0 {
+		return
+	}
+	if len(state.Peers) > 0 {
+		s.publish(ctx, client.WorldID, presenceMessage(state))
+		return
+	}
+
+	// The last participant left: save the world, then close the session unless someone joined in the meantime
+	s.writeBack(ctx, client.WorldID)
+	_, err = s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if len(state.Peers) == 0 {
+			state.ended = true
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error closing session %d: %v", client.WorldID, err)
+	}
+}
+
+// Handle processes a message sent by a participant
+func (s *SessionService) Handle(ctx context.Context, client *SessionClient, msg models.SessionMessage) {
+	var err error
+	switch msg.Type {
+	case SessionEdit:
+		err = s.edit(ctx, client, msg)
+	case SessionText:
+		err = s.text(ctx, client, msg)
+	case SessionLock:
+		err = s.lock(ctx, client, msg.Field)
+	case SessionUnlock:
+		err = s.unlock(ctx, client, msg.Field)
+	case SessionResync:
+		err = s.resync(ctx, client)
+	default:
+		err = fmt.Errorf("unknown message type %q", msg.Type)
+	}
+
+	if err != nil {
+		s.sendTo(client, models.SessionMessage{Type: SessionError, Field: msg.Field, Message: err.Error()})
+	}
+}
+
+// edit sets a field, the last edit received winning over earlier ones
+func (s *SessionService) edit(ctx context.Context, client *SessionClient, msg models.SessionMessage) error {
+	if !containsString(sessionFields, msg.Field) {
+		if msg.Field == "description" {
+			return fmt.Errorf("the description is edited with text operations")
+		}
+		return fmt.Errorf("field %q cannot be edited", msg.Field)
+	}
+
+	var stamp int64
+	state, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if err := checkSessionLock(state, client, msg.Field); err != nil {
+			return err
+		}
+		if err := setWorldField(&state.World, msg.Field, msg.Value); err != nil {
+			return err
+		}
+		// Stamps keep increasing even if the clocks of the instances drift apart
+		stamp = max(time.Now().UnixNano(), state.Stamps[msg.Field]+1)
+		state.Stamps[msg.Field] = stamp
+		state.Dirty = true
+		state.Edits++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	value, _ := worldFieldValue(&state.World, msg.Field)
+	s.publish(ctx, client.WorldID, models.SessionMessage{
+		Type: SessionEdit, Field: msg.Field, Value: value, Stamp: stamp, User: client.User, Client: client.ID,
+	})
+	return nil
+}
+
+// text applies an operation on the description, transforming it against the operations
+// applied since the revision it was based on
+func (s *SessionService) text(ctx context.Context, client *SessionClient, msg models.SessionMessage) error {
+	var op textOperation
+	if err := json.Unmarshal(msg.Ops, &op); err != nil {
+		return fmt.Errorf("invalid text operation: %v", err)
+	}
+
+	var resync bool
+	state, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if err := checkSessionLock(state, client, "description"); err != nil {
+			return err
+		}
+
+		oldest := state.Revision - len(state.History)
+		if msg.Revision < oldest || msg.Revision > state.Revision {
+			resync = true
+			return fmt.Errorf("revision %d is not available, the current revision is %d", msg.Revision, state.Revision)
+		}
+		for _, concurrent := range state.History[msg.Revision-oldest:] {
+			var err error
+			if op, _, err = transformText(op, concurrent); err != nil {
+				return err
+			}
+		}
+
+		description, err := op.apply(state.World.Description)
+		if err != nil {
+			return err
+		}
+		state.World.Description = description
+		state.Revision++
+		if state.History = append(state.History, op); len(state.History) > sessionHistoryLimit {
+			state.History = state.History[len(state.History)-sessionHistoryLimit:]
+		}
+		state.Dirty = true
+		state.Edits++
+		return nil
+	})
+	if err != nil {
+		if resync {
+			s.resync(ctx, client)
+		}
+		return err
+	}
+
+	ops, _ := json.Marshal(op)
+	s.publish(ctx, client.WorldID, models.SessionMessage{
+		Type: SessionText, Revision: state.Revision, Ops: ops, User: client.User, Client: client.ID,
+	})
+	return nil
+}
+
+// lock reserves a field for the participant, or extends its reservation
+func (s *SessionService) lock(ctx context.Context, client *SessionClient, field string) error {
+	if field != "description" && !containsString(sessionFields, field) {
+		return fmt.Errorf("field %q cannot be edited", field)
+	}
+
+	var lock models.SessionLock
+	_, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if err := checkSessionLock(state, client, field); err != nil {
+			return err
+		}
+		lock = models.SessionLock{Field: field, User: client.User, Client: client.ID, ExpiresAt: time.Now().UTC().Add(sessionLockDuration)}
+		state.Locks[field] = lock
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, client.WorldID, models.SessionMessage{
+		Type: SessionLock, Field: field, User: client.User, Client: client.ID,
+		Locks: map[string]models.SessionLock{field: lock},
+	})
+	return nil
+}
+
+// unlock releases a field reserved by the participant
+func (s *SessionService) unlock(ctx context.Context, client *SessionClient, field string) error {
+	_, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if lock, ok := state.Locks[field]; !ok || lock.Client != client.ID {
+			return fmt.Errorf("field %q is not locked by you", field)
+		}
+		delete(state.Locks, field)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, client.WorldID, models.SessionMessage{Type: SessionUnlock, Field: field, User: client.User, Client: client.ID})
+	return nil
+}
+
+// resync sends the current state of the session to a participant
+func (s *SessionService) resync(ctx context.Context, client *SessionClient) error {
+	state, err := s.store.Update(ctx, client.WorldID, func(state *sessionState) error {
+		if state.World.ID == 0 {
+			return fmt.Errorf("the session has ended")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sendTo(client, snapshotMessage(state, client.ID))
+	return nil
+}
+
+// tick refreshes the presence of the local participants, removes the ones whose instance
+// stopped refreshing them and writes the changes of each session back to the database
+func (s *SessionService) tick(ctx context.Context) {
+	s.mu.Lock()
+	local := make(map[int][]string, len(s.clients))
+	for worldID, clients := range s.clients {
+		for client := range clients {
+			local[worldID] = append(local[worldID], client.ID)
+		}
+	}
+	s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for worldID, clientIDs := range local {
+		peers := 0
+		state, err := s.store.Update(ctx, worldID, func(state *sessionState) error {
+			if state.World.ID == 0 {
+				return nil
+			}
+			for _, id := range clientIDs {
+				if peer, ok := state.Peers[id]; ok {
+					peer.LastSeen = now
+					state.Peers[id] = peer
+				}
+			}
+			peers = len(state.Peers)
+			pruneSession(state, now)
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error refreshing session %d: %v", worldID, err)
+			continue
+		}
+		if len(state.Peers) != peers {
+			s.publish(ctx, worldID, presenceMessage(state))
+		}
+		s.writeBack(ctx, worldID)
+	}
+}
+
+// writeBack saves the changes of a session to the database. The world is saved outside of the
+// updates of the session, which the store may run more than once, and the session is only marked
+// as saved when it was not edited in the meantime. When the world was saved elsewhere since the
+// session loaded it, the session starts over from the stored world.
+func (s *SessionService) writeBack(ctx context.Context, worldID int) {
+	if s.dbConfig.DB == nil {
+		return
+	}
+
+	var world models.World
+	var edits int
+	var dirty bool
+	_, err := s.store.Update(ctx, worldID, func(state *sessionState) error {
+		if state.World.ID == 0 {
+			state.ended = true
+			return nil
+		}
+		world, edits, dirty = state.World, state.Edits, state.Dirty
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error reading session %d: %v", worldID, err)
+		return
+	}
+	if !dirty {
+		return
+	}
+
+	var stored *models.World
+	if err := s.worldService.UpdateWorld(ctx, &world); err != nil {
+		log.Printf("Error writing back session %d: %v", worldID, err)
+		if !errors.Is(err, ErrConflict) {
+			return
+		}
+		if stored, err = s.worldService.GetWorldByID(ctx, worldID); err != nil {
+			log.Printf("Error reloading session %d: %v", worldID, err)
+			return
+		}
+	}
+
+	state, err := s.store.Update(ctx, worldID, func(state *sessionState) error {
+		if state.World.ID == 0 {
+			state.ended = true
+			return nil
+		}
+		if stored != nil {
+			reloadSession(state, stored)
+			return nil
+		}
+		// Edits made during the save stay to be saved on top of the new version
+		state.World.Version = world.Version
+		if state.Edits == edits {
+			state.Dirty = false
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error updating session %d: %v", worldID, err)
+		return
+	}
+	if stored != nil && !state.ended {
+		s.publish(ctx, worldID, models.SessionMessage{
+			Type: SessionError, Message: "the world was saved outside the session, the edits not yet saved were discarded",
+		})
+		s.publish(ctx, worldID, snapshotMessage(state, ""))
+	}
+}
+
+// reloadSession replaces the world of a session with its stored version. Operations based on
+// earlier revisions of the description can no longer be transformed, so their authors resync.
+func reloadSession(state *sessionState, w *models.World) {
+	state.World = *w
+	state.World.Diagnostics = nil
+	state.Stamps = make(map[string]int64)
+	state.Revision++
+	state.History = nil
+	state.Dirty = false
+}
+
+// publish sends a message to the participants of a session on every instance
+func (s *SessionService) publish(ctx context.Context, worldID int, msg models.SessionMessage) {
+	if err := s.store.Publish(ctx, worldID, msg); err != nil {
+		log.Printf("Error publishing session message: %v", err)
+	}
+}
+
+// deliver hands a published message to the local participants of the session.
+// The author of a text operation receives an acknowledgement instead, and a snapshot
+// published to the whole session is addressed to each participant.
+func (s *SessionService) deliver(worldID int, msg models.SessionMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients[worldID] {
+		switch {
+		case msg.Type == SessionText && msg.Client == client.ID:
+			client.send(models.SessionMessage{Type: SessionAck, Revision: msg.Revision})
+		case msg.Type == SessionSnapshot && msg.Client == "":
+			addressed := msg
+			addressed.Client = client.ID
+			client.send(addressed)
+		default:
+			client.send(msg)
+		}
+	}
+}
+
+// sendTo sends a message to a single local participant
+func (s *SessionService) sendTo(client *SessionClient, msg models.SessionMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client.send(msg)
+}
+
+// send queues a message for the participant, disconnecting it when its buffer is full.
+// Callers hold the lock of the service.
+func (c *SessionClient) send(msg models.SessionMessage) {
+	if c.closed {
+		return
+	}
+	select {
+	case c.messages <- msg:
+	default:
+		c.close()
+	}
+}
+
+func (c *SessionClient) close() {
+	if !c.closed {
+		c.closed = true
+		close(c.messages)
+	}
+}
+
+// checkSessionLock fails when another participant holds a lock on the field
+func checkSessionLock(state *sessionState, client *SessionClient, field string) error {
+	if state.World.ID == 0 {
+		return fmt.Errorf("the session has ended")
+	}
+	if lock, ok := state.Locks[field]; ok && lock.Client != client.ID && time.Now().Before(lock.ExpiresAt) {
+		return fmt.Errorf("field %q is locked by %s", field, lock.User)
+	}
+	return nil
+}
+
+// pruneSession removes the expired locks and the participants not seen for too long
+func pruneSession(state *sessionState, now time.Time) {
+	for id, peer := range state.Peers {
+		if now.Sub(peer.LastSeen) > sessionPeerTimeout {
+			delete(state.Peers, id)
+		}
+	}
+	for field, lock := range state.Locks {
+		if _, ok := state.Peers[lock.Client]; !ok || now.After(lock.ExpiresAt) {
+			delete(state.Locks, field)
+		}
+	}
+}
+
+func snapshotMessage(state *sessionState, clientID string) models.SessionMessage {
+	world := state.World
+	return models.SessionMessage{
+		Type: SessionSnapshot, World: &world, Revision: state.Revision, Client: clientID,
+		Peers: sortedPeers(state), Locks: state.Locks,
+	}
+}
+
+func presenceMessage(state *sessionState) models.SessionMessage {
+	return models.SessionMessage{Type: SessionPresence, Peers: sortedPeers(state), Locks: state.Locks}
+}
+
+// sortedPeers lists the participants by the time they joined
+func sortedPeers(state *sessionState) []models.SessionPeer {
+	peers := make([]models.SessionPeer, 0, len(state.Peers))
+	for _, peer := range state.Peers {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].JoinedAt.Before(peers[j].JoinedAt) })
+	return peers
+}
+
+// setWorldField decodes and validates the new value of an editable field
+func setWorldField(w *models.World, field string, value json.RawMessage) error {
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid value for %s: %v", field, err)
+	}
+
+	switch field {
+	case "name":
+		var name string
+		if err := json.Unmarshal(value, &name); err != nil {
+			return invalid(err)
+		}
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("the name cannot be empty")
+		}
+		w.Name = name
+	case "population":
+		var population int
+		if err := json.Unmarshal(value, &population); err != nil {
+			return invalid(err)
+		}
+		if population < 0 {
+			return fmt.Errorf("the population cannot be negative")
+		}
+		w.Population = population
+	case "climate":
+		var climate string
+		if err := json.Unmarshal(value, &climate); err != nil {
+			return invalid(err)
+		}
+		if !validateClimate(climate) {
+			return fmt.Errorf("unknown climate %q", climate)
+		}
+		w.Climate = climate
+	default:
+		var items []string
+		if err := json.Unmarshal(value, &items); err != nil {
+			return invalid(err)
+		}
+		// Like imported worlds, a world keeps at least one feature
+		if field == "features" && len(items) == 0 {
+			return fmt.Errorf("at least one feature is required")
+		}
+		list, _ := worldList(w, field)
+		*list = items
+	}
+	return nil
+}
+
+// worldFieldValue encodes the value of an editable field
+func worldFieldValue(w *models.World, field string) (json.RawMessage, error) {
+	switch field {
+	case "name":
+		return json.Marshal(w.Name)
+	case "population":
+		return json.Marshal(w.Population)
+	case "climate":
+		return json.Marshal(w.Climate)
+	}
+	list, ok := worldList(w, field)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+	return json.Marshal(*list)
+}
+
+// worldList returns the list field of a world with the given name
+func worldList(w *models.World, field string) (*[]string, bool) {
+	lists := map[string]*[]string{
+		"features":  &w.Features,
+		"fauna":     &w.Fauna,
+		"flora":     &w.Flora,
+		"cultures":  &w.Cultures,
+		"dangers":   &w.Dangers,
+		"languages": &w.Languages,
+	}
+	list, ok := lists[field]
+	return list, ok
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/medinapdr/world-gen/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Helper functions for the shared state of editing sessions
+
+const (
+	// Sessions left behind by crashed instances expire after this long without updates
+	sessionTTL = time.Hour
+	// Attempts made to update a session state modified concurrently by another instance
+	sessionUpdateRetries = 10
+)
+
+// sessionState is the state of an editing session shared by every instance
+type sessionState struct {
+	World models.World `json:"world"`
+	// Stamps holds the stamp of the last edit of each field
+	Stamps map[string]int64 `json:"stamps"`
+	// Revision counts the operations applied to the description, the latest of which are kept in History
+	Revision int                           `json:"revision"`
+	History  []textOperation               `json:"history"`
+	Peers    map[string]models.SessionPeer `json:"peers"`
+	Locks    map[string]models.SessionLock `json:"locks"`
+	// Dirty is set until the changes are written back to the database
+	Dirty bool `json:"dirty"`
+	// Edits counts the changes to the world, telling a write-back whether the session changed while it was saving
+	Edits int `json:"edits"`
+
+	// ended removes the session once the update is stored
+	ended bool
+}
+
+// sessionStore keeps the state of sessions and relays their messages between instances
+type sessionStore interface {
+	// Update applies fn to the state of a session atomically. The state is empty when the session does not exist.
+	Update(ctx context.Context, worldID int, fn func(state *sessionState) error) (*sessionState, error)
+	Publish(ctx context.Context, worldID int, msg models.SessionMessage) error
+	// Subscribe delivers the messages published to a session until the returned function is called
+	Subscribe(ctx context.Context, worldID int, deliver func(models.SessionMessage)) func()
+}
+
+func sessionKey(worldID int) string {
+	return fmt.Sprintf("session:%d", worldID)
+}
+
+// redisSessionStore shares sessions between instances. States are updated with optimistic
+// transactions and messages go through a pub/sub channel per session.
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+func (s *redisSessionStore) Update(ctx context.Context, worldID int, fn func(state *sessionState) error) (*sessionState, error) {
+	key := sessionKey(worldID)
+	var state *sessionState
+
+	update := func(tx *redis.Tx) error {
+		state = &sessionState{}
+		stateJSON, err := tx.Get(ctx, key).Bytes()
+		if err == nil {
+			if err := json.Unmarshal(stateJSON, state); err != nil {
+				return err
+			}
+		} else if err != redis.Nil {
+			return err
+		}
+
+		if err := fn(state); err != nil {
+			return err
+		}
+
+		newJSON, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if state.ended {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.Set(ctx, key, newJSON, sessionTTL)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < sessionUpdateRetries; attempt++ {
+		err := s.client.Watch(ctx, update, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return state, err
+	}
+	return nil, fmt.Errorf("session %d is too busy, try again", worldID)
+}
+
+func (s *redisSessionStore) Publish(ctx context.Context, worldID int, msg models.SessionMessage) error {
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, sessionKey(worldID), msgJSON).Err()
+}
+
+func (s *redisSessionStore) Subscribe(ctx context.Context, worldID int, deliver func(models.SessionMessage)) func() {
+	pubsub := s.client.Subscribe(ctx, sessionKey(worldID))
+	go func() {
+		for msg := range pubsub.Channel() {
+			var sessionMsg models.SessionMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &sessionMsg); err != nil {
+				log.Printf("Error deserializing session message: %v", err)
+				continue
+			}
+			deliver(sessionMsg)
+		}
+	}()
+	return func() { pubsub.Close() }
+}
+
+// memorySessionStore keeps sessions in process when Redis is not available
+type memorySessionStore struct {
+	mu          sync.Mutex
+	states      map[int][]byte
+	subscribers map[int]map[*func(models.SessionMessage)]bool
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{
+		states:      make(map[int][]byte),
+		subscribers: make(map[int]map[*func(models.SessionMessage)]bool),
+	}
+}
+
+// States are stored serialized so that a failed update leaves no trace
+func (s *memorySessionStore) Update(ctx context.Context, worldID int, fn func(state *sessionState) error) (*sessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := &sessionState{}
+	if stateJSON, ok := s.states[worldID]; ok {
+		if err := json.Unmarshal(stateJSON, state); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := fn(state); err != nil {
+		return nil, err
+	}
+
+	if state.ended {
+		delete(s.states, worldID)
+		return state, nil
+	}
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	s.states[worldID] = stateJSON
+	return state, nil
+}
+
+func (s *memorySessionStore) Publish(ctx context.Context, worldID int, msg models.SessionMessage) error {
+	s.mu.Lock()
+	var delivers []func(models.SessionMessage)
+	for deliver := range s.subscribers[worldID] {
+		delivers = append(delivers, *deliver)
+	}
+	s.mu.Unlock()
+
+	for _, deliver := range delivers {
+		deliver(msg)
+	}
+	return nil
+}
+
+func (s *memorySessionStore) Subscribe(ctx context.Context, worldID int, deliver func(models.SessionMessage)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers[worldID] == nil {
+		s.subscribers[worldID] = make(map[*func(models.SessionMessage)]bool)
+	}
+	s.subscribers[worldID][&deliver] = true
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers[worldID], &deliver)
+		if len(s.subscribers[worldID]) == 0 {
+			delete(s.subscribers, worldID)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+// ErrSystemNotFound is returned when a star system does not exist
+var ErrSystemNotFound = newError(ErrNotFound, "system not found")
+
+// SystemService manages the creation and retrieval of star systems
+type SystemService struct {
+	dbConfig     *config.DatabaseConfig
+	worldService *WorldService
+}
+
+// NewSystemService creates a new instance of the service
+func NewSystemService(dbConfig *config.DatabaseConfig, worldService *WorldService) *SystemService {
+	return &SystemService{
+		dbConfig:     dbConfig,
+		worldService: worldService,
+	}
+}
+
+// Celestial body types
+const (
+	BodyRocky        = "rocky planet"
+	BodyDwarf        = "dwarf planet"
+	BodyGasGiant     = "gas giant"
+	BodyIceGiant     = "ice giant"
+	BodyAsteroidBelt = "asteroid belt"
+	BodyRockyMoon    = "rocky moon"
+	BodyIceMoon      = "ice moon"
+)
+
+// Star systems are only generated for the sci-fi theme
+const systemWorldsTheme = "sci-fi"
+
+// starClass describes the physical ranges of a spectral class
+type starClass struct {
+	Class       string
+	Weight      int
+	Description string
+	MinTemp     int
+	MaxTemp     int
+	MinLum      float64
+	MaxLum      float64
+	Mass        float64
+}
+
+var starClasses = []starClass{
+	{"O", 1, "Blue supergiant", 30000, 50000, 30000, 100000, 30},
+	{"B", 2, "Blue-white giant", 10000, 30000, 25, 30000, 8},
+	{"A", 4, "White star", 7500, 10000, 5, 25, 2},
+	{"F", 8, "Yellow-white star", 6000, 7500, 1.5, 5, 1.3},
+	{"G", 12, "Yellow dwarf", 5200, 6000, 0.6, 1.5, 1},
+	{"K", 15, "Orange dwarf", 3700, 5200, 0.08, 0.6, 0.7},
+	{"M", 20, "Red dwarf", 2400, 3700, 0.001, 0.08, 0.3},
+}
+
+// Climates of habitable bodies, from the inner to the outer edge of the habitable zone
+var climatesByZone = [][]string{
+	{"Desert", "Arid", "Savanna"},
+	{"Tropical", "Rainforest", "Monsoonal", "Humid Subtropical"},
+	{"Temperate", "Mediterranean", "Oceanic", "Continental"},
+	{"Alpine", "Tundra"},
+	{"Arctic", "Polar"},
+}
+
+var romanNumerals = []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII"}
+
+// GenerateSystem creates a star system and a full world for each of its habitable bodies
+func (s *SystemService) GenerateSystem(ctx context.Context, req models.CreateSystemRequest) (*models.StarSystem, error) {
+	if req.StarClass != "" && findStarClass(req.StarClass) == nil {
+		return nil, newError(ErrValidation, "invalid star class %q", req.StarClass)
+	}
+
+	rng := rand.New(rand.NewSource(rand.Int63()))
+	system := randomStarSystem(rng, strings.ToUpper(req.StarClass))
+
+	// Each habitable body becomes a world with the climate of its orbit
+	var worlds []*models.World
+	var habitable []*models.CelestialBody
+	forEachBody(system.Bodies, func(body *models.CelestialBody) error {
+		if body.Habitable {
+			world, _ := buildWorld(systemWorldsTheme, &generateOptions{climate: body.Climate})
+			body.WorldName = world.Name
+			worlds = append(worlds, world)
+			habitable = append(habitable, body)
+		}
+		return nil
+	})
+
+	appConfig := s.worldService.appConfig
+	if err := persist(s.dbConfig, appConfig, "system", func() error {
+		return s.saveSystemToDB(ctx, system, worlds, habitable)
+	}); err != nil {
+		return nil, err
+	}
+
+	system.Worlds = make([]models.World, 0, len(worlds))
+	for _, world := range worlds {
+		if s.dbConfig.RedisClient != nil {
+			s.worldService.cacheWorld(ctx, world)
+		}
+		s.worldService.events.Publish(ctx, EventWorldGenerated, world)
+		system.Worlds = append(system.Worlds, *world)
+	}
+
+	if s.dbConfig.RedisClient != nil {
+		s.cacheSystem(ctx, system)
+	}
+
+	return system, nil
+}
+
+// saveSystemToDB stores the star system and the worlds of its habitable bodies in a single transaction,
+// updating their IDs once committed so that a failure leaves neither stored
+func (s *SystemService) saveSystemToDB(ctx context.Context, system *models.StarSystem, worlds []*models.World, bodies []*models.CelestialBody) (err error) {
+	tx, err := s.dbConfig.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	defer func() {
+		if err != nil {
+			system.ID, system.CreatedAt = 0, time.Time{}
+			for i, world := range worlds {
+				world.ID, world.SystemID = 0, nil
+				bodies[i].WorldID = 0
+			}
+		}
+	}()
+
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO star_systems(name, star, habitable_zone, bodies)
+		 VALUES($1,$2,$3,$4) RETURNING id, created_at`,
+		system.Name, system.Star, system.HabitableZone, system.Bodies).Scan(&system.ID, &system.CreatedAt); err != nil {
+		return err
+	}
+
+	for i, world := range worlds {
+		systemID := system.ID
+		world.SystemID = &systemID
+		if err := saveWorldToDB(ctx, tx, world); err != nil {
+			return err
+		}
+		bodies[i].WorldID = world.ID
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE star_systems SET bodies = $1 WHERE id = $2`, system.Bodies, system.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// cacheSystem stores the star system in Redis
+func (s *SystemService) cacheSystem(ctx context.Context, system *models.StarSystem) {
+	if system.ID == 0 {
+		return
+	}
+
+	systemJSON, err := json.Marshal(system)
+	if err != nil {
+		log.Printf("Error serializing system: %v", err)
+		return
+	}
+
+	systemKey := fmt.Sprintf("system:%d", system.ID)
+	s.dbConfig.RedisClient.Set(ctx, systemKey, string(systemJSON), 0)
+}
+
+// GetSystemByID retrieves a star system with its worlds
+func (s *SystemService) GetSystemByID(ctx context.Context, id int) (*models.StarSystem, error) {
+	// Try to get from Redis cache first
+	if s.dbConfig.RedisClient != nil {
+		systemKey := fmt.Sprintf("system:%d", id)
+		systemJSON, err := s.dbConfig.RedisClient.Get(ctx, systemKey).Result()
+
+		if err == nil {
+			var system models.StarSystem
+			if err := json.Unmarshal([]byte(systemJSON), &system); err == nil {
+				return &system, nil
+			}
+		}
+	}
+
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var system models.StarSystem
+	err := s.dbConfig.DB.QueryRow(ctx,
+		`SELECT id, name, star, habitable_zone, bodies, created_at FROM star_systems WHERE id = $1`, id).Scan(
+		&system.ID, &system.Name, &system.Star, &system.HabitableZone, &system.Bodies, &system.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("%w: ID %d", ErrSystemNotFound, id)
+	} else if err != nil {
+		return nil, storageError(err)
+	}
+
+	system.Worlds, err = s.worldService.GetWorldsBySystemID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.dbConfig.RedisClient != nil {
+		s.cacheSystem(ctx, &system)
+	}
+
+	return &system, nil
+}
+
+// randomStarSystem generates a star and its orbits, using the requested spectral class if any
+func randomStarSystem(rng *rand.Rand, class string) *models.StarSystem {
+	sc := findStarClass(class)
+	if sc == nil {
+		sc = randomStarClass(rng)
+	}
+
+	name := randomName(rng, systemWorldsTheme)
+	luminosity := sc.MinLum * math.Pow(sc.MaxLum/sc.MinLum, rng.Float64())
+	star := models.Star{
+		Name:        name + " A",
+		Class:       sc.Class,
+		Description: sc.Description,
+		Temperature: sc.MinTemp + rng.Intn(sc.MaxTemp-sc.MinTemp),
+		Luminosity:  roundTo(luminosity, 3),
+		Mass:        roundTo(sc.Mass*(0.8+rng.Float64()*0.4), 2),
+	}
+
+	zone := models.HabitableZone{
+		InnerAU: roundTo(math.Sqrt(luminosity/1.1), 3),
+		OuterAU: roundTo(math.Sqrt(luminosity/0.53), 3),
+	}
+
+	count := 3 + rng.Intn(7) // 3-9 orbits
+	orbit := math.Max(0.2*math.Sqrt(luminosity), 0.02)
+	bodies := make([]models.CelestialBody, 0, count)
+	for i := 0; i < count; i++ {
+		body := randomBody(rng, orbit, zone)
+		body.Designation = fmt.Sprintf("%s %c", name, 'b'+i)
+		for j := range body.Moons {
+			body.Moons[j].Designation = fmt.Sprintf("%s %s", body.Designation, romanNumerals[j])
+		}
+		bodies = append(bodies, body)
+		orbit *= 1.4 + rng.Float64()*0.6
+	}
+
+	ensureHabitableBody(bodies, zone)
+
+	return &models.StarSystem{
+		Name:          name,
+		Star:          star,
+		HabitableZone: zone,
+		Bodies:        bodies,
+	}
+}
+
+// randomBody generates the body at an orbit, according to its position relative to the habitable zone
+func randomBody(rng *rand.Rand, orbit float64, zone models.HabitableZone) models.CelestialBody {
+	body := models.CelestialBody{OrbitAU: roundTo(orbit, 3)}
+	inZone := orbit >= zone.InnerAU && orbit <= zone.OuterAU
+
+	roll := rng.Float64()
+	switch {
+	case orbit < zone.InnerAU:
+		body.Type = BodyRocky
+		if roll < 0.4 {
+			body.Type = BodyDwarf
+		}
+	case inZone:
+		body.Type = BodyRocky
+		if roll < 0.2 {
+			body.Type = BodyGasGiant
+		}
+	default:
+		body.Type = BodyGasGiant
+		if roll < 0.35 {
+			body.Type = BodyIceGiant
+		} else if roll < 0.5 {
+			body.Type = BodyAsteroidBelt
+		}
+	}
+
+	if body.Type == BodyRocky && inZone {
+		body.Habitable = true
+		body.Climate = climateForOrbit(rng, orbit, zone)
+	}
+
+	moonCount := 0
+	switch body.Type {
+	case BodyGasGiant:
+		moonCount = 1 + rng.Intn(5)
+	case BodyIceGiant:
+		moonCount = 1 + rng.Intn(3)
+	case BodyRocky:
+		moonCount = rng.Intn(3)
+	}
+
+	for i := 0; i < moonCount; i++ {
+		moon := models.CelestialBody{Type: BodyRockyMoon}
+		if !inZone && orbit > zone.OuterAU {
+			moon.Type = BodyIceMoon
+		}
+		// Large moons of giants inside the habitable zone may hold life
+		if inZone && body.Type == BodyGasGiant && rng.Float64() < 0.3 {
+			moon.Habitable = true
+			moon.Climate = climateForOrbit(rng, orbit, zone)
+		}
+		body.Moons = append(body.Moons, moon)
+	}
+
+	return body
+}
+
+// ensureHabitableBody turns the rocky planet closest to the habitable zone into a habitable one when none exists
+func ensureHabitableBody(bodies []models.CelestialBody, zone models.HabitableZone) {
+	found := false
+	_ = forEachBody(bodies, func(body *models.CelestialBody) error {
+		found = found || body.Habitable
+		return nil
+	})
+	if found || len(bodies) == 0 {
+		return
+	}
+
+	center := (zone.InnerAU + zone.OuterAU) / 2
+	closest := 0
+	for i, body := range bodies {
+		if math.Abs(body.OrbitAU-center) < math.Abs(bodies[closest].OrbitAU-center) {
+			closest = i
+		}
+	}
+
+	body := &bodies[closest]
+	body.Type = BodyRocky
+	body.Habitable = true
+	body.Climate = "Arctic"
+	if body.OrbitAU < zone.InnerAU {
+		body.Climate = "Desert"
+	}
+}
+
+// climateForOrbit picks a climate matching the position of an orbit within the habitable zone
+func climateForOrbit(rng *rand.Rand, orbit float64, zone models.HabitableZone) string {
+	position := (orbit - zone.InnerAU) / (zone.OuterAU - zone.InnerAU)
+	idx := int(position * float64(len(climatesByZone)))
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(climatesByZone) {
+		idx = len(climatesByZone) - 1
+	}
+
+	options := climatesByZone[idx]
+	return options[rng.Intn(len(options))]
+}
+
+// forEachBody calls fn for every body of the system, moons included, stopping at the first error
+func forEachBody(bodies []models.CelestialBody, fn func(*models.CelestialBody) error) error {
+	for i := range bodies {
+		if err := fn(&bodies[i]); err != nil {
+			return err
+		}
+		if err := forEachBody(bodies[i].Moons, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findStarClass returns the spectral class with the given letter, or nil
+func findStarClass(class string) *starClass {
+	for i := range starClasses {
+		if strings.EqualFold(starClasses[i].Class, class) {
+			return &starClasses[i]
+		}
+	}
+	return nil
+}
+
+// randomStarClass picks a spectral class, cooler stars being more common
+func randomStarClass(rng *rand.Rand) *starClass {
+	total := 0
+	for _, sc := range starClasses {
+		total += sc.Weight
+	}
+
+	roll := rng.Intn(total)
+	for i := range starClasses {
+		if roll < starClasses[i].Weight {
+			return &starClasses[i]
+		}
+		roll -= starClasses[i].Weight
+	}
+	return &starClasses[len(starClasses)-1]
+}
+
+// roundTo rounds a value to the given number of decimals
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
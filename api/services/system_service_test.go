@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+func TestGenerateSystemWithoutDatabase(t *testing.T) {
+	db := &config.DatabaseConfig{}
+	s := NewSystemService(db, NewWorldService(db, config.NewAppConfig(), NewEventBus(db)))
+
+	for i := 0; i < 20; i++ {
+		system, err := s.GenerateSystem(t.Context(), models.CreateSystemRequest{})
+		if err != nil {
+			t.Fatalf("GenerateSystem() error = %v", err)
+		}
+
+		habitable := 0
+		forEachBody(system.Bodies, func(body *models.CelestialBody) error {
+			if body.Habitable {
+				if body.WorldName != system.Worlds[habitable].Name {
+					t.Errorf("body names world %q, want %q", body.WorldName, system.Worlds[habitable].Name)
+				}
+				habitable++
+			}
+			return nil
+		})
+		if len(system.Worlds) != habitable {
+			t.Errorf("system has %d worlds for %d habitable bodies", len(system.Worlds), habitable)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryRetrying  = "retrying"
+	DeliveryDead      = "dead"
+)
+
+// WebhookEvents lists the events webhooks can subscribe to
+var WebhookEvents = []string{EventWorldGenerated, EventWorldUpdated, EventWorldDeleted, EventWorldImported}
+
+// ErrWebhookNotFound is returned for unknown webhook IDs
+var ErrWebhookNotFound = newError(ErrNotFound, "webhook not found")
+
+const (
+	webhooksKey = "webhooks"
+	// Deliveries waiting for their next attempt, scored by the time it is due
+	webhookQueueKey = "webhooks:queue"
+	// Deliveries that failed every attempt
+	webhookDeadKey         = "webhooks:dead"
+	webhookDeadLimit       = 1000
+	webhookLogLimit        = 100
+	webhookMaxAttempts     = 6
+	webhookBaseBackoff     = 10 * time.Second
+	webhookPollInterval    = time.Second
+	webhookWorkers         = 4
+	webhookTimeout         = 10 * time.Second
+	webhookSignatureHeader = "X-WorldGen-Signature"
+	webhookTimestampHeader = "X-WorldGen-Timestamp"
+	webhookEventHeader     = "X-WorldGen-Event"
+	webhookDeliveryHeader  = "X-WorldGen-Delivery"
+	webhookDeliveryBatch   = 10
+	// Claimed deliveries are due again after the lease, so that the deliveries of a crashed instance are retried
+	webhookLease = time.Minute
+)
+
+// claimWebhooksScript leases the due tasks of the queue by pushing their score past the lease,
+// returning them to the instance that claimed them
+var claimWebhooksScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, member in ipairs(members) do
+	redis.call('ZADD', KEYS[1], 'XX', ARGV[2], member)
+end
+return members
+`)
+
+// Private ranges not covered by net.IP methods: shared address space (RFC 6598)
+var webhookBlockedNetworks = []*net.IPNet{
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+}
+
+// WebhookService delivers world events to the registered webhooks.
+// Webhooks, pending deliveries and their logs are kept in Redis, so that
+// every instance shares them and each delivery is attempted by a single instance.
+type WebhookService struct {
+	dbConfig *config.DatabaseConfig
+	client   *http.Client
+	// allowPrivate lets webhooks target loopback and private addresses
+	allowPrivate bool
+}
+
+// webhookTask is a delivery waiting for its next attempt. Tasks that failed
+// every attempt are kept in the dead-letter list with their last error.
+type webhookTask struct {
+	ID        string            `json:"id"`
+	WebhookID string            `json:"webhook_id"`
+	Attempt   int               `json:"attempt"`
+	Event     models.WorldEvent `json:"event"`
+	Error     string            `json:"error,omitempty"`
+
+	// member is the queue entry of the task, removed once the attempt is over
+	member string
+}
+
+// NewWebhookService creates a new instance of the service, queueing a delivery for every event published by this instance
+func NewWebhookService(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, events *EventBus) *WebhookService {
+	s := &WebhookService{dbConfig: dbConfig, allowPrivate: appConfig.WebhookAllowPrivate}
+	// Addresses are checked once resolved, right before connecting, so that a host cannot resolve
+	// to a public address when registered and to a private one when delivered to
+	dialer := &net.Dialer{Timeout: webhookTimeout, Control: s.controlDial}
+	s.client = &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	events.OnPublish(s.enqueue)
+	return s
+}
+
+// Start delivers the due events until the context is done
+func (s *WebhookService) Start(ctx context.Context) {
+	if s.dbConfig.RedisClient == nil {
+		return
+	}
+
+	tasks := make(chan webhookTask)
+	for i := 0; i < webhookWorkers; i++ {
+		go func() {
+			for task := range tasks {
+				s.deliver(ctx, task)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(tasks)
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		for {
+			for _, task := range s.claimDue(ctx) {
+				select {
+				case tasks <- task:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// ValidateWebhookRequest checks a webhook registration, subscribing to every event when none is given.
+// The host of the URL must resolve to public addresses only.
+func (s *WebhookService) ValidateWebhookRequest(ctx context.Context, req *models.CreateWebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return newError(ErrValidation, "url must be an absolute http or https URL")
+	}
+	if !s.allowPrivate {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+		if err != nil || len(addrs) == 0 {
+			return newError(ErrValidation, "url host %q cannot be resolved", u.Hostname())
+		}
+		for _, addr := range addrs {
+			if blockedWebhookIP(addr.IP) {
+				return newError(ErrValidation, "url host %q resolves to a private address", u.Hostname())
+			}
+		}
+	}
+
+	if len(req.Events) == 0 {
+		req.Events = WebhookEvents
+	}
+	for _, event := range req.Events {
+		if !containsString(WebhookEvents, event) {
+			return newError(ErrValidation, "unknown event %q", event)
+		}
+	}
+	return nil
+}
+
+// CreateWebhook registers a webhook, generating its secret when none is given
+func (s *WebhookService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (*models.Webhook, error) {
+	if s.dbConfig.RedisClient == nil {
+		return nil, ErrNoDatabase
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newJobID(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.Webhook{ID: id, URL: req.URL, Events: req.Events, Secret: secret, CreatedAt: time.Now().UTC()}
+	webhookJSON, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dbConfig.RedisClient.HSet(ctx, webhooksKey, id, webhookJSON).Err(); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetWebhooks lists the registered webhooks, without their secrets
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.loadWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook unregisters a webhook along with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	if s.dbConfig.RedisClient == nil {
+		return ErrNoDatabase
+	}
+
+	removed, err := s.dbConfig.RedisClient.HDel(ctx, webhooksKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrWebhookNotFound
+	}
+	s.dbConfig.RedisClient.Del(ctx, webhookLogKey(id))
+	return nil
+}
+
+// GetDeliveries returns the latest delivery attempts of a webhook, most recent first
+func (s *WebhookService) GetDeliveries(ctx context.Context, id string) ([]models.WebhookDelivery, error) {
+	if _, err := s.loadWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.dbConfig.RedisClient.LRange(ctx, webhookLogKey(id), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(entries))
+	for _, entry := range entries {
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal([]byte(entry), &delivery); err == nil {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+// enqueue schedules the delivery of an event to the webhooks subscribed to it
+func (s *WebhookService) enqueue(ctx context.Context, event models.WorldEvent) {
+	if s.dbConfig.RedisClient == nil {
+		return
+	}
+
+	webhooks, err := s.loadWebhooks(ctx)
+	if err != nil {
+		log.Printf("Error loading webhooks: %v", err)
+		return
+	}
+	for _, webhook := range webhooks {
+		if !containsString(webhook.Events, event.Type) {
+			continue
+		}
+		id, err := newJobID()
+		if err != nil {
+			log.Printf("Error queueing webhook delivery: %v", err)
+			continue
+		}
+		s.schedule(ctx, webhookTask{ID: id, WebhookID: webhook.ID, Attempt: 1, Event: event}, time.Now())
+	}
+}
+
+// schedule queues a task for an attempt at the given time
+func (s *WebhookService) schedule(ctx context.Context, task webhookTask, due time.Time) {
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		log.Printf("Error serializing webhook delivery: %v", err)
+		return
+	}
+	err = s.dbConfig.RedisClient.ZAdd(ctx, webhookQueueKey, redis.Z{Score: float64(due.UnixMilli()), Member: taskJSON}).Err()
+	if err != nil {
+		log.Printf("Error queueing webhook delivery: %v", err)
+	}
+}
+
+// claimDue leases the due tasks of the queue. A task stays queued while it is attempted and
+// belongs to the instance that claimed it until its lease expires.
+func (s *WebhookService) claimDue(ctx context.Context) []webhookTask {
+	now := time.Now()
+	members, err := claimWebhooksScript.Run(ctx, s.dbConfig.RedisClient, []string{webhookQueueKey},
+		now.UnixMilli(), now.Add(webhookLease).UnixMilli(), webhookDeliveryBatch).StringSlice()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Error reading webhook queue: %v", err)
+		}
+		return nil
+	}
+
+	var tasks []webhookTask
+	for _, member := range members {
+		var task webhookTask
+		if err := json.Unmarshal([]byte(member), &task); err != nil {
+			log.Printf("Error deserializing webhook delivery: %v", err)
+			s.dbConfig.RedisClient.ZRem(ctx, webhookQueueKey, member)
+			continue
+		}
+		task.member = member
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// complete removes an attempted task from the queue, queueing its next attempt in the same transaction
+func (s *WebhookService) complete(ctx context.Context, task webhookTask, next *webhookTask, due time.Time) {
+	pipe := s.dbConfig.RedisClient.TxPipeline()
+	pipe.ZRem(ctx, webhookQueueKey, task.member)
+	if next != nil {
+		taskJSON, err := json.Marshal(next)
+		if err != nil {
+			log.Printf("Error serializing webhook delivery: %v", err)
+			return
+		}
+		pipe.ZAdd(ctx, webhookQueueKey, redis.Z{Score: float64(due.UnixMilli()), Member: taskJSON})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error completing webhook delivery: %v", err)
+	}
+}
+
+// deliver attempts a delivery, then logs it and either retries it with exponential backoff or moves it to the dead-letter list
+func (s *WebhookService) deliver(ctx context.Context, task webhookTask) {
+	webhook, err := s.loadWebhook(ctx, task.WebhookID)
+	if err != nil {
+		// Deliveries to deleted webhooks are dropped
+		if !errors.Is(err, ErrWebhookNotFound) {
+			log.Printf("Error loading webhook %s: %v", task.WebhookID, err)
+			return
+		}
+		s.complete(ctx, task, nil, time.Time{})
+		return
+	}
+
+	delivery := models.WebhookDelivery{
+		ID: task.ID, WebhookID: webhook.ID, EventID: task.Event.ID, Event: task.Event.Type,
+		Attempt: task.Attempt, CreatedAt: time.Now().UTC(),
+	}
+	start := time.Now()
+	delivery.StatusCode, err = s.post(ctx, webhook, task)
+	delivery.DurationMS = time.Since(start).Milliseconds()
+
+	switch {
+	case err == nil:
+		delivery.Status = DeliveryDelivered
+		s.complete(ctx, task, nil, time.Time{})
+	case task.Attempt < webhookMaxAttempts:
+		delivery.Status = DeliveryRetrying
+		delivery.Error = err.Error()
+		due := time.Now().Add(webhookBaseBackoff << (task.Attempt - 1)).UTC()
+		delivery.NextRetry = &due
+		next := task
+		next.Attempt++
+		s.complete(ctx, task, &next, due)
+	default:
+		delivery.Status = DeliveryDead
+		delivery.Error = err.Error()
+		task.Error = err.Error()
+		s.deadLetter(ctx, task)
+		s.complete(ctx, task, nil, time.Time{})
+	}
+
+	s.logDelivery(ctx, delivery)
+}
+
+// post sends the event to the webhook, signing the timestamp and the body with its secret
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, task webhookTask) (int, error) {
+	body, err := json.Marshal(task.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "world-gen-webhooks")
+	req.Header.Set(webhookEventHeader, task.Event.Type)
+	req.Header.Set(webhookDeliveryHeader, task.ID)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// logDelivery adds an attempt to the delivery log of the webhook
+func (s *WebhookService) logDelivery(ctx context.Context, delivery models.WebhookDelivery) {
+	deliveryJSON, err := json.Marshal(delivery)
+	if err != nil {
+		log.Printf("Error serializing webhook delivery: %v", err)
+		return
+	}
+	pipe := s.dbConfig.RedisClient.Pipeline()
+	pipe.LPush(ctx, webhookLogKey(delivery.WebhookID), deliveryJSON)
+	pipe.LTrim(ctx, webhookLogKey(delivery.WebhookID), 0, webhookLogLimit-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error logging webhook delivery: %v", err)
+	}
+}
+
+// deadLetter keeps a delivery that failed every attempt so that it can be inspected or replayed
+func (s *WebhookService) deadLetter(ctx context.Context, task webhookTask) {
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		log.Printf("Error serializing webhook delivery: %v", err)
+		return
+	}
+	pipe := s.dbConfig.RedisClient.Pipeline()
+	pipe.LPush(ctx, webhookDeadKey, taskJSON)
+	pipe.LTrim(ctx, webhookDeadKey, 0, webhookDeadLimit-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Error storing dead webhook delivery: %v", err)
+	}
+}
+
+func (s *WebhookService) loadWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	if s.dbConfig.RedisClient == nil {
+		return nil, ErrNoDatabase
+	}
+
+	entries, err := s.dbConfig.RedisClient.HGetAll(ctx, webhooksKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	webhooks := make([]models.Webhook, 0, len(entries))
+	for id, entry := range entries {
+		var webhook models.Webhook
+		if err := json.Unmarshal([]byte(entry), &webhook); err != nil {
+			log.Printf("Error deserializing webhook %s: %v", id, err)
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks, nil
+}
+
+func (s *WebhookService) loadWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	if s.dbConfig.RedisClient == nil {
+		return nil, ErrNoDatabase
+	}
+
+	webhookJSON, err := s.dbConfig.RedisClient.HGet(ctx, webhooksKey, id).Bytes()
+	if err == redis.Nil {
+		return nil, ErrWebhookNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var webhook models.Webhook
+	if err := json.Unmarshal(webhookJSON, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// controlDial refuses connections to the addresses webhooks cannot target
+func (s *WebhookService) controlDial(network, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || blockedWebhookIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// blockedWebhookIP reports whether an address is loopback, private, link-local, unspecified or multicast
+func blockedWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, network := range webhookBlockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookLogKey(id string) string {
+	return "webhook:" + id + ":deliveries"
+}
+
+// signWebhook computes the hex HMAC-SHA256 of "<timestamp>.<body>"
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/medinapdr/world-gen/config"
+	"github.com/medinapdr/world-gen/models"
+)
+
+func TestSignWebhook(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{"event", "secret", "1700000000", `{"type":"world.generated"}`, "40139d14d559e7ca6a67b02554c837cd5b899d663b424732e96fcef2143497ac"},
+		{"empty body", "key", "0", "", "85841b4efc3cd7776c3c8f9b7cca9e281c550e5d19889d78e9e669c6337f000d"},
+		{"empty secret", "", "1", "x", "d356c76f2be16120eca994746a279fd612267e791d245c4745ebbf0f98fc31f2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signWebhook(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("signWebhook() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockedWebhookIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", false},
+		{"2606:4700:4700::1111", false},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"fd00::1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"100.64.0.1", true},
+		{"::ffff:127.0.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := blockedWebhookIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("blockedWebhookIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		wantErr      bool
+	}{
+		{"public address", "https://8.8.8.8/hook", false, false},
+		{"public address with port", "http://8.8.8.8:8080/hook", false, false},
+		{"not http", "ftp://8.8.8.8/hook", false, true},
+		{"relative", "/hook", false, true},
+		{"loopback", "http://127.0.0.1/hook", false, true},
+		{"loopback IPv6", "http://[::1]:8080/hook", false, true},
+		{"metadata service", "http://169.254.169.254/latest/meta-data", false, true},
+		{"private", "http://10.0.0.5/hook", false, true},
+		{"private allowed", "http://10.0.0.5/hook", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &config.DatabaseConfig{}
+			s := NewWebhookService(db, &config.AppConfig{WebhookAllowPrivate: tt.allowPrivate}, NewEventBus(db))
+			req := models.CreateWebhookRequest{URL: tt.url}
+
+			err := s.ValidateWebhookRequest(context.Background(), &req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateWebhookRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("ValidateWebhookRequest() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestWebhookDialRefusesPrivateAddresses(t *testing.T) {
+	db := &config.DatabaseConfig{}
+	s := NewWebhookService(db, &config.AppConfig{}, NewEventBus(db))
+
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "192.168.0.10:8080"} {
+		if err := s.controlDial("tcp", address, nil); err == nil {
+			t.Errorf("controlDial(%s) accepted a private address", address)
+		}
+	}
+	if err := s.controlDial("tcp", "8.8.8.8:443", nil); err != nil {
+		t.Errorf("controlDial(8.8.8.8:443) error = %v", err)
+	}
+}
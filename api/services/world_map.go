@@ -0,0 +1,389 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/medinapdr/world-gen/models"
+)
+
+// Helper functions for world map generation
+
+const (
+	mapWidth  = 32
+	mapHeight = 20
+)
+
+// Terrain types used on world maps
+const (
+	TerrainWater     = "water"
+	TerrainPlains    = "plains"
+	TerrainForest    = "forest"
+	TerrainHills     = "hills"
+	TerrainMountains = "mountains"
+	TerrainDesert    = "desert"
+	TerrainIce       = "ice"
+	TerrainSwamp     = "swamp"
+	TerrainJungle    = "jungle"
+)
+
+var terrainSymbols = map[string]string{
+	TerrainWater:     "~",
+	TerrainPlains:    ".",
+	TerrainForest:    "f",
+	TerrainHills:     "n",
+	TerrainMountains: "^",
+	TerrainDesert:    ":",
+	TerrainIce:       "*",
+	TerrainSwamp:     "%",
+	TerrainJungle:    "&",
+}
+
+// Movement cost of entering a tile of each terrain
+var terrainCosts = map[string]int{
+	TerrainWater:     5,
+	TerrainPlains:    1,
+	TerrainForest:    2,
+	TerrainHills:     3,
+	TerrainMountains: 8,
+	TerrainDesert:    2,
+	TerrainIce:       3,
+	TerrainSwamp:     4,
+	TerrainJungle:    3,
+}
+
+// Lowland terrains of each climate, ordered from dry to wet
+var lowlandsByClimate = map[string][]string{
+	"Arid":              {TerrainDesert, TerrainDesert, TerrainDesert, TerrainPlains},
+	"Temperate":         {TerrainPlains, TerrainPlains, TerrainForest, TerrainForest},
+	"Tropical":          {TerrainPlains, TerrainJungle, TerrainJungle, TerrainSwamp},
+	"Arctic":            {TerrainPlains, TerrainIce, TerrainIce, TerrainIce},
+	"Mediterranean":     {TerrainPlains, TerrainPlains, TerrainForest},
+	"Alpine":            {TerrainPlains, TerrainForest, TerrainForest},
+	"Oceanic":           {TerrainPlains, TerrainPlains, TerrainForest, TerrainSwamp},
+	"Continental":       {TerrainPlains, TerrainPlains, TerrainPlains, TerrainForest},
+	"Monsoonal":         {TerrainPlains, TerrainPlains, TerrainJungle, TerrainSwamp},
+	"Polar":             {TerrainIce},
+	"Desert":            {TerrainDesert},
+	"Savanna":           {TerrainDesert, TerrainPlains, TerrainPlains, TerrainPlains},
+	"Rainforest":        {TerrainJungle, TerrainJungle, TerrainJungle, TerrainSwamp},
+	"Tundra":            {TerrainPlains, TerrainPlains, TerrainIce, TerrainIce},
+	"Humid Subtropical": {TerrainPlains, TerrainForest, TerrainForest, TerrainSwamp},
+}
+
+// Words in feature names that suggest a lot of surface water
+var wetFeatureKeywords = []string{"coast", "island", "beach", "lake", "sea", "reef", "lagoon", "cove", "floe"}
+
+// Suffixes naming a region after its dominant terrain
+var regionSuffixByTerrain = map[string]string{
+	TerrainWater:     "Waters",
+	TerrainPlains:    "Lowlands",
+	TerrainForest:    "Woods",
+	TerrainHills:     "Highlands",
+	TerrainMountains: "Peaks",
+	TerrainDesert:    "Wastes",
+	TerrainIce:       "Icefields",
+	TerrainSwamp:     "Marshes",
+	TerrainJungle:    "Jungle",
+}
+
+// worldMap is the generated terrain grid of a world with its regions and settlements
+type worldMap struct {
+	tiles       [][]string
+	regions     []models.Region
+	settlements []models.Settlement
+}
+
+// worldSeed derives a stable seed for a world and a generation purpose
+func worldSeed(w *models.World, purpose string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s:%s", w.ID, w.Name, purpose)
+	return int64(h.Sum64())
+}
+
+// generateWorldMap builds the deterministic terrain map of a world
+func generateWorldMap(w *models.World) *worldMap {
+	rng := rand.New(rand.NewSource(worldSeed(w, "map")))
+
+	elevation := valueNoise(rng, mapWidth, mapHeight, 6)
+	moisture := valueNoise(rng, mapWidth, mapHeight, 8)
+
+	waterLevel, hillLevel, mountainLevel := 0.25, 0.62, 0.78
+	for _, feature := range w.Features {
+		if containsAnyKeyword(feature, wetFeatureKeywords) {
+			waterLevel = 0.35
+			break
+		}
+	}
+	if w.Climate == "Alpine" {
+		hillLevel, mountainLevel = 0.45, 0.62
+	}
+
+	lowlands := lowlandsByClimate[w.Climate]
+	if lowlands == nil {
+		lowlands = lowlandsByClimate["Temperate"]
+	}
+
+	tiles := make([][]string, mapHeight)
+	for y := 0; y < mapHeight; y++ {
+		tiles[y] = make([]string, mapWidth)
+		for x := 0; x < mapWidth; x++ {
+			e := elevation[y][x]
+			switch {
+			case e < waterLevel:
+				tiles[y][x] = TerrainWater
+			case e > mountainLevel:
+				tiles[y][x] = TerrainMountains
+			case e > hillLevel:
+				tiles[y][x] = TerrainHills
+			default:
+				idx := int(moisture[y][x] * float64(len(lowlands)))
+				if idx >= len(lowlands) {
+					idx = len(lowlands) - 1
+				}
+				tiles[y][x] = lowlands[idx]
+			}
+		}
+	}
+
+	m := &worldMap{tiles: tiles}
+	m.settlements = placeSettlements(rng, m, w)
+	m.regions = divideRegions(m)
+	return m
+}
+
+// divideRegions assigns every tile to its nearest settlement and describes the resulting regions
+func divideRegions(m *worldMap) []models.Region {
+	if len(m.settlements) == 0 {
+		return []models.Region{}
+	}
+
+	counts := make([]map[string]int, len(m.settlements))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	for y, row := range m.tiles {
+		for x, t := range row {
+			nearest := 0
+			for i, st := range m.settlements {
+				if chebyshevDistance(models.Point{X: x, Y: y}, st.Position) <
+					chebyshevDistance(models.Point{X: x, Y: y}, m.settlements[nearest].Position) {
+					nearest = i
+				}
+			}
+			counts[nearest][t]++
+		}
+	}
+
+	regions := make([]models.Region, 0, len(m.settlements))
+	for i, st := range m.settlements {
+		dominant, tiles := TerrainPlains, 0
+		for _, t := range sortedKeys(counts[i]) {
+			tiles += counts[i][t]
+			if t != TerrainWater && counts[i][t] > counts[i][dominant] {
+				dominant = t
+			}
+		}
+
+		regions = append(regions, models.Region{
+			Name:       st.Name + " " + regionSuffixByTerrain[dominant],
+			Settlement: st.Name,
+			Terrain:    dominant,
+			Coastal:    counts[i][TerrainWater] > 0,
+			Tiles:      tiles,
+		})
+	}
+
+	return regions
+}
+
+// findRegion looks a region up by its name or the name of its settlement, ignoring case
+func (m *worldMap) findRegion(name string) (models.Region, bool) {
+	for _, r := range m.regions {
+		if strings.EqualFold(r.Name, name) || strings.EqualFold(r.Settlement, name) {
+			return r, true
+		}
+	}
+	return models.Region{}, false
+}
+
+// regionOrFirst looks a region up by name, defaulting to the first region of the map when no name is given
+func (m *worldMap) regionOrFirst(name string) (models.Region, error) {
+	if name == "" {
+		if len(m.regions) == 0 {
+			return models.Region{Name: "Everywhere", Terrain: TerrainPlains}, nil
+		}
+		return m.regions[0], nil
+	}
+
+	if region, ok := m.findRegion(name); ok {
+		return region, nil
+	}
+
+	names := make([]string, 0, len(m.regions))
+	for _, r := range m.regions {
+		names = append(names, r.Name)
+	}
+	return models.Region{}, newError(ErrValidation, "unknown region %q, available regions: %s", name, strings.Join(names, ", "))
+}
+
+// placeSettlements chooses settlement sites on habitable tiles, keeping them apart from each other
+func placeSettlements(rng *rand.Rand, m *worldMap, w *models.World) []models.Settlement {
+	var candidates []models.Point
+	for y := 0; y < mapHeight; y++ {
+		for x := 0; x < mapWidth; x++ {
+			if t := m.tiles[y][x]; t != TerrainWater && t != TerrainMountains {
+				candidates = append(candidates, models.Point{X: x, Y: y})
+			}
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	count := 4 + rng.Intn(4) // 4-7 settlements
+	var sites []models.Point
+	for minDistance := 6; minDistance > 0 && len(sites) < count; minDistance-- {
+		for _, p := range candidates {
+			if len(sites) >= count {
+				break
+			}
+			if farFromAll(p, sites, minDistance) {
+				sites = append(sites, p)
+			}
+		}
+	}
+
+	// Share the world population between settlements
+	weights := make([]float64, len(sites))
+	total := 0.0
+	for i := range weights {
+		weights[i] = 0.2 + rng.Float64()
+		total += weights[i]
+	}
+
+	language := pickLanguage(rng, w.Languages, w.Theme)
+	names := make(map[string]bool, len(sites))
+	settlements := make([]models.Settlement, 0, len(sites))
+	for i, p := range sites {
+		name := languageWord(rng, language)
+		for attempts := 0; names[name] && attempts < 10; attempts++ {
+			name = languageWord(rng, language)
+		}
+		names[name] = true
+
+		share := weights[i] / total
+		size := "village"
+		if share > 0.3 {
+			size = "city"
+		} else if share > 0.15 {
+			size = "town"
+		}
+
+		settlements = append(settlements, models.Settlement{
+			Name:       name,
+			Size:       size,
+			Population: int(share * float64(w.Population)),
+			Terrain:    m.tiles[p.Y][p.X],
+			Position:   p,
+		})
+	}
+
+	return settlements
+}
+
+// toModel converts the map to its API representation
+func (m *worldMap) toModel() models.WorldMap {
+	rows := make([]string, mapHeight)
+	for y, row := range m.tiles {
+		var sb strings.Builder
+		for _, t := range row {
+			sb.WriteString(terrainSymbols[t])
+		}
+		rows[y] = sb.String()
+	}
+
+	legend := make(map[string]string, len(terrainSymbols))
+	for t, symbol := range terrainSymbols {
+		legend[symbol] = t
+	}
+
+	return models.WorldMap{
+		Width:       mapWidth,
+		Height:      mapHeight,
+		Rows:        rows,
+		Legend:      legend,
+		Regions:     m.regions,
+		Settlements: m.settlements,
+	}
+}
+
+// valueNoise returns a smooth field of values in [0, 1) interpolated from a coarse random lattice
+func valueNoise(rng *rand.Rand, width, height, cell int) [][]float64 {
+	cols := width/cell + 2
+	lines := height/cell + 2
+	lattice := make([][]float64, lines)
+	for i := range lattice {
+		lattice[i] = make([]float64, cols)
+		for j := range lattice[i] {
+			lattice[i][j] = rng.Float64()
+		}
+	}
+
+	smooth := func(t float64) float64 { return t * t * (3 - 2*t) }
+
+	field := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		field[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			gx, gy := x/cell, y/cell
+			tx := smooth(float64(x%cell) / float64(cell))
+			ty := smooth(float64(y%cell) / float64(cell))
+
+			top := lattice[gy][gx]*(1-tx) + lattice[gy][gx+1]*tx
+			bottom := lattice[gy+1][gx]*(1-tx) + lattice[gy+1][gx+1]*tx
+			field[y][x] = math.Min(top*(1-ty)+bottom*ty, 0.999)
+		}
+	}
+
+	return field
+}
+
+// farFromAll reports whether p is at least minDistance tiles away from every point
+func farFromAll(p models.Point, points []models.Point, minDistance int) bool {
+	for _, other := range points {
+		if chebyshevDistance(p, other) < minDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// chebyshevDistance is the number of king moves between two tiles
+func chebyshevDistance(a, b models.Point) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
+
+// containsAnyKeyword reports whether text contains any of the keywords, ignoring case
+func containsAnyKeyword(text string, keywords []string) bool {
+	lower := strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
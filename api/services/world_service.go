@@ -12,22 +12,116 @@ import (
 	"github.com/medinapdr/world-gen/models"
 )
 
+// ErrWorldNotFound is returned when a world does not exist
+var ErrWorldNotFound = newError(ErrNotFound, "world not found")
+
 // WorldService manages the creation and retrieval of worlds
 type WorldService struct {
 	dbConfig  *config.DatabaseConfig
 	appConfig *config.AppConfig
+	events    *EventBus
 }
 
 // NewWorldService creates a new instance of the service
-func NewWorldService(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig) *WorldService {
+func NewWorldService(dbConfig *config.DatabaseConfig, appConfig *config.AppConfig, events *EventBus) *WorldService {
 	return &WorldService{
 		dbConfig:  dbConfig,
 		appConfig: appConfig,
+		events:    events,
+	}
+}
+
+// GenerateOption customizes how GenerateWorld builds a world
+type GenerateOption func(*generateOptions)
+
+// generateOptions holds the settings applied by GenerateOption values
+type generateOptions struct {
+	climate     string
+	systemID    *int
+	seed        *int64
+	diagnostics bool
+	population  *[2]int
+}
+
+// WithClimate forces the climate of the generated world instead of picking a random one
+func WithClimate(climate string) GenerateOption {
+	return func(o *generateOptions) {
+		o.climate = climate
 	}
 }
 
+// WithSystemID attaches the generated world to a star system
+func WithSystemID(systemID int) GenerateOption {
+	return func(o *generateOptions) {
+		o.systemID = &systemID
+	}
+}
+
+// WithSeed makes generation deterministic: the same seed, theme and climate always yield the same world
+func WithSeed(seed int64) GenerateOption {
+	return func(o *generateOptions) {
+		o.seed = &seed
+	}
+}
+
+// WithDiagnostics attaches the report of the coherence rules to the generated world
+func WithDiagnostics() GenerateOption {
+	return func(o *generateOptions) {
+		o.diagnostics = true
+	}
+}
+
+// WithPopulationRange keeps the population of the generated world between min and max
+func WithPopulationRange(min, max int) GenerateOption {
+	return func(o *generateOptions) {
+		o.population = &[2]int{min, max}
+	}
+}
+
+// ValidateGenerateRequest checks the theme and climate of a world to generate, filling in the default theme
+func (s *WorldService) ValidateGenerateRequest(req *models.GenerateWorldRequest) error {
+	if req.Theme == "" {
+		req.Theme = "fantasy"
+	}
+	if !validateTheme(req.Theme) {
+		return newError(ErrValidation, "unknown theme %q", req.Theme)
+	}
+
+	if req.Climate != "" && !validateClimate(req.Climate) {
+		return newError(ErrValidation, "unknown climate %q", req.Climate)
+	}
+
+	return nil
+}
+
 // GenerateWorld creates a new world based on the theme
-func (s *WorldService) GenerateWorld(ctx context.Context, theme string) (*models.World, error) {
+func (s *WorldService) GenerateWorld(ctx context.Context, theme string, opts ...GenerateOption) (*models.World, error) {
+	options := &generateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	w, diagnostics := buildWorld(theme, options)
+
+	if err := persist(s.dbConfig, s.appConfig, "world", func() error { return saveWorldToDB(ctx, s.dbConfig.DB, w) }); err != nil {
+		return nil, err
+	}
+
+	if s.dbConfig.RedisClient != nil {
+		s.cacheWorld(ctx, w)
+	}
+
+	s.events.Publish(ctx, EventWorldGenerated, w)
+
+	if options.diagnostics {
+		w.Diagnostics = diagnostics
+	}
+
+	return w, nil
+}
+
+// buildWorld generates a world without storing it, returning the report of the coherence rules
+func buildWorld(theme string, options *generateOptions) (*models.World, *models.Diagnostics) {
 	if theme == "" {
 		theme = "fantasy"
 	}
@@ -36,18 +130,32 @@ func (s *WorldService) GenerateWorld(ctx context.Context, theme string) (*models
 		theme = "fantasy"
 	}
 
-	climate := randomClimate()
-	features := randomFeatures(climate)
-	fauna := randomFauna(climate, theme)
-	flora := randomFlora(climate, theme)
-	cultures := randomCultures(theme)
-	dangers := randomDangers(climate, theme)
-	languages := randomLanguages(theme)
+	// Every pool draws from a single source so the world can be reproduced from its seed
+	seed := rand.Int63()
+	if options.seed != nil {
+		seed = *options.seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// The climate is drawn even when it is forced, so that a world is reproduced from its seed and climate
+	climate := randomClimate(rng)
+	if validateClimate(options.climate) {
+		climate = options.climate
+	}
+	features := randomFeatures(rng, climate)
+	fauna := randomFauna(rng, climate, theme, features)
+	flora := randomFlora(rng, climate, theme, features)
+	cultures := randomCultures(rng, theme, features)
+	dangers := randomDangers(rng, climate, theme, features)
+	languages := randomLanguages(rng, theme)
+
+	religions := randomReligions(rng, theme, climate, cultures, features, languages)
+	powerSystem := randomPowerSystem(rng, theme, cultures, dangers, languages)
 
 	w := &models.World{
-		Name:        randomName(theme),
-		Description: generateDescription(theme, climate, features, fauna, flora),
-		Population:  rand.Intn(10000000),
+		Name:        randomName(rng, theme),
+		Description: generateDescription(rng, theme, climate, features, fauna, flora),
+		Population:  rng.Intn(MaxPopulation + 1),
 		Climate:     climate,
 		Features:    features,
 		Theme:       theme,
@@ -56,35 +164,55 @@ func (s *WorldService) GenerateWorld(ctx context.Context, theme string) (*models
 		Cultures:    cultures,
 		Dangers:     dangers,
 		Languages:   languages,
+		Religions:   religions,
+		PowerSystem: powerSystem,
+		SystemID:    options.systemID,
+		Seed:        seed,
 	}
-
-	if s.dbConfig.DB != nil {
-		err := s.saveWorldToDB(ctx, w)
-		if err != nil {
-			log.Printf("Error inserting into DB: %v", err)
-		}
+	if r := options.population; r != nil && (w.Population < r[0] || w.Population > r[1]) {
+		w.Population = r[0] + rng.Intn(r[1]-r[0]+1)
 	}
 
-	if s.dbConfig.RedisClient != nil {
-		s.cacheWorld(ctx, w)
+	// Repair the fields that contradict each other before the world is stored
+	diagnostics := applyCoherenceRules(rng, w, true)
+	w.Rarities = worldRarities(w)
+
+	return w, diagnostics
+}
+
+// worldColumns lists the columns selected when loading worlds, in the order expected by scanWorld
+const worldColumns = `id, name, description, population, climate, features, theme, created_at,
+	fauna, flora, cultures, dangers, languages, religions, power_system, system_id, seed, version`
+
+// scanWorld reads a row selected with worldColumns into a world
+func scanWorld(row pgx.Row, w *models.World) error {
+	err := row.Scan(&w.ID, &w.Name, &w.Description, &w.Population,
+		&w.Climate, &w.Features, &w.Theme, &w.CreatedAt,
+		&w.Fauna, &w.Flora, &w.Cultures, &w.Dangers, &w.Languages, &w.Religions, &w.PowerSystem, &w.SystemID, &w.Seed, &w.Version)
+	if err != nil {
+		return err
 	}
 
-	return w, nil
+	w.Rarities = worldRarities(w)
+	return nil
 }
 
-// saveWorldToDB persists the world to the database and updates the ID
-func (s *WorldService) saveWorldToDB(ctx context.Context, w *models.World) error {
-	var id int
-	err := s.dbConfig.DB.QueryRow(ctx,
-		`INSERT INTO worlds(name, description, population, climate, features, theme) 
-		 VALUES($1,$2,$3,$4,$5,$6) RETURNING id`,
-		w.Name, w.Description, w.Population, w.Climate, w.Features, w.Theme).Scan(&id)
+// saveWorldToDB persists the world to the database, on the pool or in a transaction, and updates the ID and version
+func saveWorldToDB(ctx context.Context, db queryRower, w *models.World) error {
+	var id, version int
+	err := db.QueryRow(ctx,
+		`INSERT INTO worlds(name, description, population, climate, features, theme,
+		                    fauna, flora, cultures, dangers, languages, religions, power_system, system_id, seed)
+		 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING id, version`,
+		w.Name, w.Description, w.Population, w.Climate, w.Features, w.Theme,
+		w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages, w.Religions, w.PowerSystem, w.SystemID, w.Seed).Scan(&id, &version)
 
 	if err != nil {
 		return err
 	}
 
 	w.ID = id
+	w.Version = version
 	return nil
 }
 
@@ -124,11 +252,8 @@ func (s *WorldService) GetWorldByID(ctx context.Context, id int) (*models.World,
 	// Fallback to database if Redis failed or world not found in cache
 	if s.dbConfig.DB != nil {
 		var world models.World
-		err := s.dbConfig.DB.QueryRow(ctx,
-			`SELECT id, name, description, population, climate, features, theme, created_at 
-			 FROM worlds WHERE id = $1`, id).Scan(
-			&world.ID, &world.Name, &world.Description, &world.Population,
-			&world.Climate, &world.Features, &world.Theme, &world.CreatedAt)
+		err := scanWorld(s.dbConfig.DB.QueryRow(ctx,
+			`SELECT `+worldColumns+` FROM worlds WHERE id = $1`, id), &world)
 
 		if err == nil {
 			// Update cache
@@ -137,86 +262,258 @@ func (s *WorldService) GetWorldByID(ctx context.Context, id int) (*models.World,
 			}
 			return &world, nil
 		} else if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("world with ID %d not found", id)
+			return nil, fmt.Errorf("%w: ID %d", ErrWorldNotFound, id)
 		} else {
-			return nil, err
+			return nil, storageError(err)
 		}
 	}
 
-	return nil, fmt.Errorf("no database connection available")
+	return nil, ErrNoDatabase
 }
 
-// SearchWorlds searches for worlds based on criteria
-func (s *WorldService) SearchWorlds(ctx context.Context, query string, theme, climate string, limit, offset int) ([]models.World, int, error) {
+// UpdateWorld saves the editable fields of an existing world and refreshes its cached copy.
+// The world must still be at the version it was loaded with, otherwise a conflict is returned.
+func (s *WorldService) UpdateWorld(ctx context.Context, w *models.World) error {
 	if s.dbConfig.DB == nil {
-		return nil, 0, fmt.Errorf("no database connection available")
+		return ErrNoDatabase
 	}
 
-	if limit <= 0 {
-		limit = 10
+	var version int
+	err := s.dbConfig.DB.QueryRow(ctx,
+		`UPDATE worlds SET name = $2, description = $3, population = $4, climate = $5, features = $6,
+		                   fauna = $7, flora = $8, cultures = $9, dangers = $10, languages = $11,
+		                   version = version + 1
+		 WHERE id = $1 AND version = $12 RETURNING version`,
+		w.ID, w.Name, w.Description, w.Population, w.Climate, w.Features,
+		w.Fauna, w.Flora, w.Cultures, w.Dangers, w.Languages, w.Version).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return s.updateConflict(ctx, w)
+	}
+	if err != nil {
+		return storageError(err)
 	}
 
-	// Build base query without LIMIT and OFFSET for counting total records
-	baseQuery := `SELECT id, name, description, population, climate, features, theme, created_at 
-				 FROM worlds WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM worlds WHERE 1=1`
+	w.Version = version
+	w.Rarities = worldRarities(w)
+	if s.dbConfig.RedisClient != nil {
+		if worldJSON, err := json.Marshal(w); err != nil {
+			log.Printf("Error serializing world: %v", err)
+		} else {
+			s.dbConfig.RedisClient.Set(ctx, fmt.Sprintf("world:%d", w.ID), string(worldJSON), 0)
+		}
+	}
 
-	args := make([]interface{}, 0)
-	argPos := 1
+	s.events.Publish(ctx, EventWorldUpdated, w)
+	return nil
+}
 
-	if query != "" {
-		whereClause := fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argPos, argPos)
-		baseQuery += whereClause
-		countQuery += whereClause
-		args = append(args, "%"+query+"%")
-		argPos++
+// updateConflict explains why an update matched no row: the world is gone or was saved since it was loaded
+func (s *WorldService) updateConflict(ctx context.Context, w *models.World) error {
+	var version int
+	err := s.dbConfig.DB.QueryRow(ctx, `SELECT version FROM worlds WHERE id = $1`, w.ID).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("%w: ID %d", ErrWorldNotFound, w.ID)
+	}
+	if err != nil {
+		return storageError(err)
 	}
 
-	if theme != "" {
-		whereClause := fmt.Sprintf(" AND theme = $%d", argPos)
-		baseQuery += whereClause
-		countQuery += whereClause
-		args = append(args, theme)
-		argPos++
+	// The cached copy may predate the other save
+	if s.dbConfig.RedisClient != nil {
+		s.dbConfig.RedisClient.Del(ctx, fmt.Sprintf("world:%d", w.ID))
+	}
+	return newError(ErrConflict, "world %d was saved at version %d since version %d was loaded", w.ID, version, w.Version)
+}
+
+// DeleteWorld removes a world along with its locations and used hooks
+func (s *WorldService) DeleteWorld(ctx context.Context, id int) error {
+	if s.dbConfig.DB == nil {
+		return ErrNoDatabase
 	}
 
-	if climate != "" {
-		whereClause := fmt.Sprintf(" AND climate = $%d", argPos)
-		baseQuery += whereClause
-		countQuery += whereClause
-		args = append(args, climate)
-		argPos++
+	w, err := s.GetWorldByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.dbConfig.DB.Exec(ctx, `DELETE FROM worlds WHERE id = $1`, id)
+	if err != nil {
+		return storageError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: ID %d", ErrWorldNotFound, id)
+	}
+
+	if s.dbConfig.RedisClient != nil {
+		s.dbConfig.RedisClient.Del(ctx, fmt.Sprintf("world:%d", id))
+	}
+
+	s.events.Publish(ctx, EventWorldDeleted, w)
+	return nil
+}
+
+// GetWorldsByIDs retrieves several worlds at once, reading the cached ones from Redis and the rest
+// from the database in a single query. Unknown IDs are left out of the result.
+func (s *WorldService) GetWorldsByIDs(ctx context.Context, ids []int) (map[int]*models.World, error) {
+	worlds := make(map[int]*models.World, len(ids))
+	missing := ids
+
+	if s.dbConfig.RedisClient != nil && len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = fmt.Sprintf("world:%d", id)
+		}
+		values, err := s.dbConfig.RedisClient.MGet(ctx, keys...).Result()
+		if err == nil {
+			missing = nil
+			for i, value := range values {
+				var world models.World
+				if worldJSON, ok := value.(string); ok && json.Unmarshal([]byte(worldJSON), &world) == nil {
+					worlds[ids[i]] = &world
+				} else {
+					missing = append(missing, ids[i])
+				}
+			}
+		}
+	}
+
+	if len(missing) == 0 {
+		return worlds, nil
+	}
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := s.dbConfig.DB.Query(ctx, `SELECT `+worldColumns+` FROM worlds WHERE id = ANY($1)`, missing)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var world models.World
+		if err := scanWorld(rows, &world); err != nil {
+			return nil, err
+		}
+		worlds[world.ID] = &world
+	}
+
+	return worlds, rows.Err()
+}
+
+// GetWorldsBySystemID retrieves the worlds generated for the bodies of a star system
+func (s *WorldService) GetWorldsBySystemID(ctx context.Context, systemID int) ([]models.World, error) {
+	if s.dbConfig.DB == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := s.dbConfig.DB.Query(ctx,
+		`SELECT `+worldColumns+` FROM worlds WHERE system_id = $1 ORDER BY id`, systemID)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	defer rows.Close()
+
+	worlds := []models.World{}
+	for rows.Next() {
+		var world models.World
+		if err := scanWorld(rows, &world); err != nil {
+			return nil, err
+		}
+		worlds = append(worlds, world)
 	}
 
+	return worlds, rows.Err()
+}
+
+// SearchWorlds searches for worlds based on criteria
+func (s *WorldService) SearchWorlds(ctx context.Context, query string, theme, climate string, limit, offset int) ([]models.World, int, error) {
+	if s.dbConfig.DB == nil {
+		return nil, 0, ErrNoDatabase
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	where, args := searchConditions(query, theme, climate)
+
 	// First, get the total count
 	var total int
-	err := s.dbConfig.DB.QueryRow(ctx, countQuery, args...).Scan(&total)
+	err := s.dbConfig.DB.QueryRow(ctx, `SELECT COUNT(*) FROM worlds WHERE 1=1`+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, storageError(err)
+	}
+
+	var worlds []models.World
+	err = s.EachSearchedWorld(ctx, query, theme, climate, limit, offset, func(world *models.World) error {
+		worlds = append(worlds, *world)
+		return nil
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Then, get the paginated results
-	selectQuery := baseQuery + " ORDER BY created_at DESC LIMIT $" + fmt.Sprint(argPos) + " OFFSET $" + fmt.Sprint(argPos+1)
+	return worlds, total, nil
+}
+
+// EachSearchedWorld calls fn with every world matching the search, in the order of SearchWorlds.
+// Worlds are read one at a time from the database, so that large results are never held in memory.
+func (s *WorldService) EachSearchedWorld(ctx context.Context, query, theme, climate string, limit, offset int, fn func(*models.World) error) error {
+	if s.dbConfig.DB == nil {
+		return ErrNoDatabase
+	}
+
+	where, args := searchConditions(query, theme, climate)
+	argPos := len(args) + 1
+	selectQuery := `SELECT ` + worldColumns + ` FROM worlds WHERE 1=1` + where +
+		" ORDER BY created_at DESC LIMIT $" + fmt.Sprint(argPos) + " OFFSET $" + fmt.Sprint(argPos+1)
 	args = append(args, limit, offset)
 
 	rows, err := s.dbConfig.DB.Query(ctx, selectQuery, args...)
 	if err != nil {
-		return nil, 0, err
+		return storageError(err)
 	}
 	defer rows.Close()
 
-	var worlds []models.World
 	for rows.Next() {
 		var world models.World
-		err := rows.Scan(&world.ID, &world.Name, &world.Description, &world.Population,
-			&world.Climate, &world.Features, &world.Theme, &world.CreatedAt)
+		err := scanWorld(rows, &world)
 		if err != nil {
 			continue
 		}
-		worlds = append(worlds, world)
+		if err := fn(&world); err != nil {
+			return err
+		}
 	}
 
-	return worlds, total, nil
+	return storageError(rows.Err())
+}
+
+// searchConditions builds the conditions of a world search, appended to a WHERE clause, and their arguments
+func searchConditions(query, theme, climate string) (string, []interface{}) {
+	var where string
+	args := make([]interface{}, 0)
+	argPos := 1
+
+	if query != "" {
+		where += fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argPos, argPos)
+		args = append(args, "%"+query+"%")
+		argPos++
+	}
+
+	if theme != "" {
+		where += fmt.Sprintf(" AND theme = $%d", argPos)
+		args = append(args, theme)
+		argPos++
+	}
+
+	if climate != "" {
+		where += fmt.Sprintf(" AND climate = $%d", argPos)
+		args = append(args, climate)
+	}
+
+	return where, args
 }
 
 // GetWorldHistory retrieves the history of generated worlds
@@ -364,7 +661,16 @@ func validateTheme(theme string) bool {
 	return false
 }
 
-func randomName(theme string) string {
+func validateClimate(climate string) bool {
+	for _, validClimate := range climates {
+		if climate == validClimate {
+			return true
+		}
+	}
+	return false
+}
+
+func randomName(rng *rand.Rand, theme string) string {
 	prefixes := map[string][]string{
 		"fantasy":          {"Aure", "Eld", "Myth", "Zan", "Thaur", "Crystal", "Ever", "Fel", "Glimmer", "Iron"},
 		"sci-fi":           {"Xen", "Nova", "Qar", "Zy", "Eco", "Neb", "Sol", "Astra", "Orb", "Pulse"},
@@ -381,10 +687,10 @@ func randomName(theme string) string {
 		pre = prefixes["fantasy"]
 		suf = suffixes["fantasy"]
 	}
-	return fmt.Sprintf("%s%s", pre[rand.Intn(len(pre))], suf[rand.Intn(len(suf))])
+	return fmt.Sprintf("%s%s", pre[rng.Intn(len(pre))], suf[rng.Intn(len(suf))])
 }
 
-func generateDescription(theme, climate string, features, fauna, flora []string) string {
+func generateDescription(rng *rand.Rand, theme, climate string, features, fauna, flora []string) string {
 	f1, f2 := features[0], features[1]
 	animal := ""
 	if len(fauna) > 0 {
@@ -401,113 +707,89 @@ func generateDescription(theme, climate string, features, fauna, flora []string)
 		"This %s world is defined by its %s climate and %s alongside %s. Travelers may encounter %s near the %s.",
 		"Explore a %s realm under %s skies, with %s and %s. Beware of %s hiding within the %s.",
 	}
-	tmpl := templates[rand.Intn(len(templates))]
+	tmpl := templates[rng.Intn(len(templates))]
 	return fmt.Sprintf(tmpl, theme, climate, f1, f2, animal, plant)
 }
 
-func randomClimate() string {
-	return climates[rand.Intn(len(climates))]
+func randomClimate(rng *rand.Rand) string {
+	return climates[rng.Intn(len(climates))]
 }
 
-func randomWithoutDuplicates(items []string, count int) []string {
-	if count <= 0 {
-		return []string{}
-	}
-
-	// Create a copy to avoid modifying the original
-	itemsCopy := make([]string, len(items))
-	copy(itemsCopy, items)
-
-	// Shuffle the copy
-	rand.Shuffle(len(itemsCopy), func(i, j int) {
-		itemsCopy[i], itemsCopy[j] = itemsCopy[j], itemsCopy[i]
-	})
+// randomWithoutDuplicatesFrom returns up to count unique items using the given source,
+// weighted by the rarity of each item
+func randomWithoutDuplicatesFrom(rng *rand.Rand, items []string, count int) []string {
+	return weightedSample(rng, items, count, nil)
+}
 
-	// Return the specified number of items or all if count > len
-	if count > len(itemsCopy) {
-		count = len(itemsCopy)
+// containsString reports whether the slice contains the value
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
 	}
-	return itemsCopy[:count]
+	return false
 }
 
-func randomFeatures(climate string) []string {
+func randomFeatures(rng *rand.Rand, climate string) []string {
 	feats := featuresByClimate[climate]
 	if feats == nil {
 		feats = featuresByClimate["Temperate"]
 	}
 
 	// Get 2-4 unique features
-	count := 2 + rand.Intn(3) // 2, 3, or 4
-	return randomWithoutDuplicates(feats, count)
+	count := 2 + rng.Intn(3) // 2, 3, or 4
+	return weightedSample(rng, feats, count, nil)
 }
 
-func randomFauna(climate, theme string) []string {
-	fauna := faunaByClimate[theme][climate]
-	if fauna == nil {
-		// Fallback to another climate or theme if specific combination not found
-		for _, f := range faunaByClimate[theme] {
-			fauna = f
-			break
-		}
-		if fauna == nil {
-			fauna = faunaByClimate["fantasy"]["Temperate"]
-		}
+// climatePool returns the pool of a theme and climate, falling back to another climate of the theme
+func climatePool(rng *rand.Rand, pools map[string]map[string][]string, theme, climate string) []string {
+	if pool := pools[theme][climate]; pool != nil {
+		return pool
 	}
 
-	count := 2 + rand.Intn(3) // 2-4 fauna
-	return randomWithoutDuplicates(fauna, count)
+	byClimate := pools[theme]
+	if byClimate == nil {
+		return pools["fantasy"]["Temperate"]
+	}
+	keys := sortedKeys(byClimate)
+	return byClimate[keys[rng.Intn(len(keys))]]
 }
 
-func randomFlora(climate, theme string) []string {
-	flora := floraByClimate[theme][climate]
-	if flora == nil {
-		// Fallback to another climate or theme if specific combination not found
-		for _, f := range floraByClimate[theme] {
-			flora = f
-			break
-		}
-		if flora == nil {
-			flora = floraByClimate["fantasy"]["Temperate"]
-		}
-	}
+func randomFauna(rng *rand.Rand, climate, theme string, features []string) []string {
+	fauna := climatePool(rng, faunaByClimate, theme, climate)
+	count := 2 + rng.Intn(3) // 2-4 fauna
+	return weightedSample(rng, fauna, count, features)
+}
 
-	count := 2 + rand.Intn(3) // 2-4 flora
-	return randomWithoutDuplicates(flora, count)
+func randomFlora(rng *rand.Rand, climate, theme string, features []string) []string {
+	flora := climatePool(rng, floraByClimate, theme, climate)
+	count := 2 + rng.Intn(3) // 2-4 flora
+	return weightedSample(rng, flora, count, features)
 }
 
-func randomCultures(theme string) []string {
+func randomCultures(rng *rand.Rand, theme string, features []string) []string {
 	cultures := culturesByTheme[theme]
 	if cultures == nil {
 		cultures = culturesByTheme["fantasy"]
 	}
 
-	count := 1 + rand.Intn(3) // 1-3 cultures
-	return randomWithoutDuplicates(cultures, count)
+	count := 1 + rng.Intn(3) // 1-3 cultures
+	return weightedSample(rng, cultures, count, features)
 }
 
-func randomDangers(climate, theme string) []string {
-	dangers := dangersByTheme[theme][climate]
-	if dangers == nil {
-		// Fallback to another climate or theme if specific combination not found
-		for _, d := range dangersByTheme[theme] {
-			dangers = d
-			break
-		}
-		if dangers == nil {
-			dangers = dangersByTheme["fantasy"]["Temperate"]
-		}
-	}
-
-	count := 1 + rand.Intn(2) // 1-2 dangers
-	return randomWithoutDuplicates(dangers, count)
+func randomDangers(rng *rand.Rand, climate, theme string, features []string) []string {
+	dangers := climatePool(rng, dangersByTheme, theme, climate)
+	count := 1 + rng.Intn(2) // 1-2 dangers
+	return weightedSample(rng, dangers, count, features)
 }
 
-func randomLanguages(theme string) []string {
+func randomLanguages(rng *rand.Rand, theme string) []string {
 	langs := languagesByTheme[theme]
 	if langs == nil {
 		langs = languagesByTheme["fantasy"]
 	}
 
-	count := 1 + rand.Intn(3) // 1-3 languages
-	return randomWithoutDuplicates(langs, count)
+	count := 1 + rng.Intn(3) // 1-3 languages
+	return weightedSample(rng, langs, count, nil)
 }
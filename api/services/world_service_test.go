@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateWorldIsSeeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		options []GenerateOption
+	}{
+		{"fantasy", "fantasy", nil},
+		{"sci-fi with a climate", "sci-fi", []GenerateOption{WithClimate("Arctic")}},
+		{"post-apocalyptic with a population range", "post-apocalyptic", []GenerateOption{WithPopulationRange(10, 20)}},
+		{"with diagnostics", "fantasy", []GenerateOption{WithDiagnostics(), WithClimate("Polar")}},
+	}
+
+	s := newTestWorldService()
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 10; seed++ {
+				opts := append([]GenerateOption{WithSeed(seed)}, tt.options...)
+				first, err := s.GenerateWorld(ctx, tt.theme, opts...)
+				if err != nil {
+					t.Fatalf("GenerateWorld() error = %v", err)
+				}
+				second, err := s.GenerateWorld(ctx, tt.theme, opts...)
+				if err != nil {
+					t.Fatalf("GenerateWorld() error = %v", err)
+				}
+
+				if first.Seed != seed {
+					t.Errorf("world seed = %d, want %d", first.Seed, seed)
+				}
+				if !reflect.DeepEqual(first, second) {
+					t.Errorf("seed %d yields different worlds:\n%+v\n%+v", seed, first, second)
+				}
+			}
+		})
+	}
+}
+
+// A world regenerated from the seed it reports is the same world
+func TestGenerateWorldReportsItsSeed(t *testing.T) {
+	s := newTestWorldService()
+	ctx := context.Background()
+	w, err := s.GenerateWorld(ctx, "sci-fi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.GenerateWorld(ctx, "sci-fi", WithSeed(w.Seed), WithClimate(w.Climate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(w, again) {
+		t.Errorf("regenerating seed %d yields a different world:\n%+v\n%+v", w.Seed, w, again)
+	}
+}
+
+func TestGenerateWorldSeedsDiffer(t *testing.T) {
+	names := map[string]bool{}
+	for seed := int64(1); seed <= 20; seed++ {
+		w, _ := buildWorld("fantasy", &generateOptions{seed: &seed})
+		names[w.Name+w.Description] = true
+	}
+	if len(names) < 15 {
+		t.Errorf("20 seeds yield only %d different worlds", len(names))
+	}
+}
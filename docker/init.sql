@@ -1,3 +1,12 @@
+CREATE TABLE star_systems (
+  id             SERIAL PRIMARY KEY,
+  name           TEXT  NOT NULL,
+  star           JSONB NOT NULL,
+  habitable_zone JSONB NOT NULL,
+  bodies         JSONB NOT NULL,
+  created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
 CREATE TABLE worlds (
   id          SERIAL PRIMARY KEY,
   name        TEXT    NOT NULL,
@@ -11,12 +20,41 @@ CREATE TABLE worlds (
   flora       TEXT[],
   cultures    TEXT[],
   dangers     TEXT[],
-  languages   TEXT[]
+  languages   TEXT[],
+  religions   JSONB,
+  power_system JSONB,
+  system_id   INTEGER REFERENCES star_systems(id),
+  seed        BIGINT  NOT NULL DEFAULT 0,
+  version     INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE locations (
+  id          SERIAL PRIMARY KEY,
+  world_id    INTEGER NOT NULL REFERENCES worlds(id) ON DELETE CASCADE,
+  name        TEXT    NOT NULL,
+  type        TEXT    NOT NULL,
+  danger      TEXT    NOT NULL,
+  feature     TEXT    NOT NULL,
+  history     TEXT    NOT NULL,
+  layout      JSONB   NOT NULL,
+  inhabitants JSONB   NOT NULL,
+  treasure    JSONB   NOT NULL,
+  created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE used_hooks (
+  campaign   TEXT    NOT NULL,
+  world_id   INTEGER NOT NULL REFERENCES worlds(id) ON DELETE CASCADE,
+  hook_id    INTEGER NOT NULL,
+  used_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (campaign, world_id, hook_id)
 );
 
 CREATE INDEX idx_worlds_theme ON worlds(theme);
 CREATE INDEX idx_worlds_climate ON worlds(climate);
 CREATE INDEX idx_worlds_created_at ON worlds(created_at);
+CREATE INDEX idx_worlds_system_id ON worlds(system_id);
+CREATE INDEX idx_locations_world_id ON locations(world_id);
 CREATE INDEX idx_worlds_name_desc ON worlds
        USING gin(to_tsvector('english', name || ' ' || description));
 